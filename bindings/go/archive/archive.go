@@ -0,0 +1,144 @@
+/*
+Package archive reads and writes archives of blobs with their commitments and
+proofs, for backfill and historical sync tooling which must not trust its own
+storage.
+
+Archives use the e2store framing of era files: a sequence of entries, each a
+2 byte type, a 4 byte little-endian length, 2 reserved zero bytes and the
+data. An archive starts with a version entry, followed by one blob entry per
+blob whose data is the blob, commitment and proof concatenated. Files in a
+directory of archives end in FileExt.
+
+Readers verify every blob proof before handing the blob out. Verification runs
+in parallel a bounded number of entries ahead of the caller, so it mostly
+overlaps with reading and with the caller's own processing.
+*/
+package archive
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+)
+
+// FileExt is the extension of archive files in a directory.
+const FileExt = ".e2s"
+
+const headerSize = 8
+
+// BlobEntrySize is the length of the data of a blob entry.
+const BlobEntrySize = ckzg4844.BytesPerBlob + ckzg4844.BytesPerCommitment + ckzg4844.BytesPerProof
+
+var (
+	// TypeVersion is the type of the version entry which starts an archive.
+	TypeVersion = [2]byte{0x65, 0x32}
+	// TypeBlob is the type of blob entries.
+	TypeBlob = [2]byte{0x0b, 0x40}
+)
+
+var (
+	// ErrInvalidArchive is returned when reading data which isn't a valid
+	// archive.
+	ErrInvalidArchive = errors.New("invalid blob archive")
+	// ErrClosed is returned by a Reader which was closed.
+	ErrClosed = errors.New("archive reader is closed")
+)
+
+// Entry is a blob read from an archive.
+type Entry struct {
+	// Source is the archive file the blob was read from, or empty for
+	// readers created by NewReader.
+	Source string
+	// Index is the position of the blob in its archive.
+	Index      int
+	Blob       *ckzg4844.Blob
+	Commitment ckzg4844.Bytes48
+	Proof      ckzg4844.Bytes48
+}
+
+// Writer writes an archive.
+type Writer struct {
+	w       io.Writer
+	started bool
+	buf     []byte
+}
+
+// NewWriter returns a Writer which writes an archive to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+func appendHeader(out []byte, typ [2]byte, length int) []byte {
+	out = append(out, typ[:]...)
+	out = binary.LittleEndian.AppendUint32(out, uint32(length))
+	return append(out, 0, 0)
+}
+
+// Write appends a blob entry to the archive. The proof isn't checked.
+func (w *Writer) Write(blob *ckzg4844.Blob, commitment, proof ckzg4844.Bytes48) error {
+	out := w.buf[:0]
+	if !w.started {
+		out = appendHeader(out, TypeVersion, 0)
+	}
+	out = appendHeader(out, TypeBlob, BlobEntrySize)
+	out = append(out, blob[:]...)
+	out = append(out, commitment[:]...)
+	out = append(out, proof[:]...)
+	w.buf = out
+	if _, err := w.w.Write(out); err != nil {
+		return err
+	}
+	w.started = true
+	return nil
+}
+
+// readEntry reads the next blob entry from r into e, skipping entries of
+// other types. It returns io.EOF at the end of the archive.
+func readEntry(r io.Reader, e *Entry, first bool) error {
+	var header [headerSize]byte
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			if err == io.EOF {
+				return io.EOF
+			}
+			if err == io.ErrUnexpectedEOF {
+				return fmt.Errorf("%w: truncated entry header", ErrInvalidArchive)
+			}
+			return err
+		}
+		typ := [2]byte{header[0], header[1]}
+		length := binary.LittleEndian.Uint32(header[2:])
+		if header[6] != 0 || header[7] != 0 {
+			return fmt.Errorf("%w: reserved header bytes aren't zero", ErrInvalidArchive)
+		}
+		if first {
+			if typ != TypeVersion || length != 0 {
+				return fmt.Errorf("%w: missing version entry", ErrInvalidArchive)
+			}
+			first = false
+			continue
+		}
+		if typ != TypeBlob {
+			if _, err := io.CopyN(io.Discard, r, int64(length)); err != nil {
+				return fmt.Errorf("%w: truncated entry", ErrInvalidArchive)
+			}
+			continue
+		}
+		if length != BlobEntrySize {
+			return fmt.Errorf("%w: blob entry is %d bytes, expected %d", ErrInvalidArchive, length, BlobEntrySize)
+		}
+		e.Blob = new(ckzg4844.Blob)
+		for _, b := range [][]byte{e.Blob[:], e.Commitment[:], e.Proof[:]} {
+			if _, err := io.ReadFull(r, b); err != nil {
+				if err == io.EOF || err == io.ErrUnexpectedEOF {
+					return fmt.Errorf("%w: truncated blob entry", ErrInvalidArchive)
+				}
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,108 @@
+package archive
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/ckzgtest"
+	"github.com/stretchr/testify/require"
+)
+
+func writeArchive(t *testing.T, w io.Writer, seeds ...int64) {
+	aw := NewWriter(w)
+	for _, seed := range seeds {
+		blob := ckzgtest.RandomBlob(seed)
+		commitment, err := ckzg4844.BlobToKZGCommitment(blob)
+		require.NoError(t, err)
+		proof, err := ckzg4844.ComputeBlobKZGProof(blob, ckzg4844.Bytes48(commitment))
+		require.NoError(t, err)
+		require.NoError(t, aw.Write(blob, ckzg4844.Bytes48(commitment), ckzg4844.Bytes48(proof)))
+	}
+}
+
+func readAll(r *Reader) ([]*Entry, []error) {
+	var entries []*Entry
+	var errs []error
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			return entries, errs
+		}
+		entries = append(entries, e)
+		errs = append(errs, err)
+		if e == nil {
+			return entries, errs
+		}
+	}
+}
+
+func TestReader(t *testing.T) {
+	ckzgtest.LoadTrustedSetup(t)
+	var buf bytes.Buffer
+	writeArchive(t, &buf, 0, 1, 2, 3, 4)
+	data := buf.Bytes()
+	// Corrupt the proof of the third blob.
+	data[headerSize+3*(headerSize+BlobEntrySize)-1] ^= 1
+
+	r := NewReader(bytes.NewReader(data), ReaderConfig{Workers: 2, ReadAhead: 2})
+	defer r.Close()
+	entries, errs := readAll(r)
+	require.Len(t, entries, 5)
+	for i, e := range entries {
+		require.Equal(t, i, e.Index)
+		require.Equal(t, ckzgtest.RandomBlob(int64(i)), e.Blob)
+		if i == 2 {
+			require.Error(t, errs[i])
+		} else {
+			require.NoError(t, errs[i])
+		}
+	}
+
+	r = NewReader(bytes.NewReader(data[:len(data)-1]), ReaderConfig{})
+	defer r.Close()
+	entries, errs = readAll(r)
+	require.Len(t, entries, 5)
+	require.Nil(t, entries[4])
+	require.ErrorIs(t, errs[4], ErrInvalidArchive)
+	_, err := r.Next()
+	require.ErrorIs(t, err, ErrInvalidArchive)
+}
+
+func TestOpenDir(t *testing.T) {
+	ckzgtest.LoadTrustedSetup(t)
+	dir := t.TempDir()
+	for name, seeds := range map[string][]int64{"b" + FileExt: {2}, "a" + FileExt: {0, 1}, "c.txt": {3}} {
+		f, err := os.Create(filepath.Join(dir, name))
+		require.NoError(t, err)
+		writeArchive(t, f, seeds...)
+		require.NoError(t, f.Close())
+	}
+
+	r, err := OpenDir(dir, ReaderConfig{})
+	require.NoError(t, err)
+	entries, errs := readAll(r)
+	require.Len(t, entries, 3)
+	for i, e := range entries {
+		require.NoError(t, errs[i])
+		require.Equal(t, ckzgtest.RandomBlob(int64(i)), e.Blob)
+	}
+	require.Equal(t, filepath.Join(dir, "b"+FileExt), entries[2].Source)
+	require.Equal(t, 0, entries[2].Index)
+	require.NoError(t, r.Close())
+}
+
+func TestReaderClose(t *testing.T) {
+	ckzgtest.LoadTrustedSetup(t)
+	var buf bytes.Buffer
+	writeArchive(t, &buf, 0, 1, 2, 3)
+	r := NewReader(&buf, ReaderConfig{Workers: 1, ReadAhead: 1})
+	_, err := r.Next()
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	_, err = r.Next()
+	require.ErrorIs(t, err, ErrClosed)
+}
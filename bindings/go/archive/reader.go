@@ -0,0 +1,202 @@
+package archive
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+)
+
+// ReaderConfig configures a Reader.
+type ReaderConfig struct {
+	// Workers is the number of blobs verified in parallel. Defaults to
+	// GOMAXPROCS.
+	Workers int
+	// ReadAhead is the number of blobs read and verified ahead of the
+	// caller. Defaults to twice the number of workers.
+	ReadAhead int
+}
+
+type source struct {
+	name string
+	open func() (io.ReadCloser, error)
+}
+
+type result struct {
+	entry *Entry
+	err   error
+}
+
+// Reader reads blobs from one or more archives and verifies them. Its methods
+// must not be called concurrently.
+type Reader struct {
+	results chan chan result
+	stop    chan struct{}
+	once    sync.Once
+	wg      sync.WaitGroup
+	err     error
+}
+
+/*
+NewReader returns a Reader for the archive read from r. The trusted setup must
+be loaded.
+*/
+func NewReader(r io.Reader, config ReaderConfig) *Reader {
+	return newReader([]source{{open: func() (io.ReadCloser, error) {
+		return io.NopCloser(r), nil
+	}}}, config)
+}
+
+/*
+OpenDir returns a Reader for the archives in dir, which are the files ending
+in FileExt, in lexical order. Files are opened as they are reached. The
+trusted setup must be loaded.
+*/
+func OpenDir(dir string, config ReaderConfig) (*Reader, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var sources []source
+	for _, d := range dirEntries {
+		if d.IsDir() || !strings.HasSuffix(d.Name(), FileExt) {
+			continue
+		}
+		path := filepath.Join(dir, d.Name())
+		sources = append(sources, source{name: path, open: func() (io.ReadCloser, error) {
+			return os.Open(path)
+		}})
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].name < sources[j].name })
+	return newReader(sources, config), nil
+}
+
+func newReader(sources []source, config ReaderConfig) *Reader {
+	if config.Workers <= 0 {
+		config.Workers = runtime.GOMAXPROCS(0)
+	}
+	if config.ReadAhead <= 0 {
+		config.ReadAhead = 2 * config.Workers
+	}
+	r := &Reader{
+		results: make(chan chan result, config.ReadAhead),
+		stop:    make(chan struct{}),
+	}
+	r.wg.Add(1)
+	go r.read(sources, config.Workers)
+	return r
+}
+
+// read reads the entries of all sources and starts verifying each one, as
+// long as fewer than ReadAhead entries are waiting for the caller.
+func (r *Reader) read(sources []source, workers int) {
+	defer r.wg.Done()
+	defer close(r.results)
+	sem := make(chan struct{}, workers)
+	push := func(c chan result) bool {
+		select {
+		case r.results <- c:
+			return true
+		case <-r.stop:
+			return false
+		}
+	}
+	fail := func(err error) {
+		c := make(chan result, 1)
+		c <- result{err: err}
+		push(c)
+	}
+	for _, s := range sources {
+		f, err := s.open()
+		if err != nil {
+			fail(err)
+			return
+		}
+		for i := 0; ; i++ {
+			e := &Entry{Source: s.name, Index: i}
+			err := readEntry(f, e, i == 0)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				f.Close()
+				if s.name != "" {
+					err = fmt.Errorf("%s: %w", s.name, err)
+				}
+				fail(err)
+				return
+			}
+			c := make(chan result, 1)
+			if !push(c) {
+				f.Close()
+				return
+			}
+			select {
+			case sem <- struct{}{}:
+			case <-r.stop:
+				f.Close()
+				return
+			}
+			r.wg.Add(1)
+			go func() {
+				defer r.wg.Done()
+				defer func() { <-sem }()
+				c <- verify(e)
+			}()
+		}
+		f.Close()
+	}
+}
+
+func verify(e *Entry) result {
+	ok, err := ckzg4844.VerifyBlobKZGProof(e.Blob, e.Commitment, e.Proof)
+	if err == nil && !ok {
+		err = ckzg4844.ErrVerificationFailed
+	}
+	if err != nil {
+		if e.Source != "" {
+			return result{entry: e, err: fmt.Errorf("%s: blob %d: %w", e.Source, e.Index, err)}
+		}
+		return result{entry: e, err: fmt.Errorf("blob %d: %w", e.Index, err)}
+	}
+	return result{entry: e}
+}
+
+/*
+Next returns the next blob, in archive order, once its proof is verified. It
+returns io.EOF after the last blob. If the proof doesn't verify, it returns the
+entry together with an error wrapping ckzg4844.ErrVerificationFailed or
+ErrBadArgs, and the caller may go on reading. Any other error, such as
+ErrInvalidArchive, ends the reader and is returned again by later calls.
+*/
+func (r *Reader) Next() (*Entry, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	c, ok := <-r.results
+	if !ok {
+		r.err = io.EOF
+		return nil, r.err
+	}
+	res := <-c
+	if res.entry == nil {
+		r.err = res.err
+	}
+	return res.entry, res.err
+}
+
+// Close stops reading and waits for verifications in progress to finish.
+func (r *Reader) Close() error {
+	r.once.Do(func() { close(r.stop) })
+	r.wg.Wait()
+	if r.err == nil {
+		r.err = ErrClosed
+	}
+	return nil
+}
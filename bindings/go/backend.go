@@ -0,0 +1,61 @@
+package ckzg4844
+
+/*
+Backend is the set of KZG operations, so that code built on these bindings can
+swap the real cryptography for another implementation, such as the one in the
+simulation package. NativeBackend and *Context implement it with the C
+library.
+*/
+type Backend interface {
+	BlobToKZGCommitment(blob *Blob) (KZGCommitment, error)
+	ComputeKZGProof(blob *Blob, zBytes Bytes32) (KZGProof, Bytes32, error)
+	ComputeBlobKZGProof(blob *Blob, commitmentBytes Bytes48) (KZGProof, error)
+	VerifyKZGProof(commitmentBytes Bytes48, zBytes, yBytes Bytes32, proofBytes Bytes48) (bool, error)
+	VerifyBlobKZGProof(blob *Blob, commitmentBytes, proofBytes Bytes48) (bool, error)
+	VerifyBlobKZGProofBatch(blobs []Blob, commitmentsBytes, proofsBytes []Bytes48) (bool, error)
+	ComputeCellsAndKZGProofs(blob *Blob) ([CellsPerExtBlob]Cell, [CellsPerExtBlob]KZGProof, error)
+	RecoverCellsAndKZGProofs(cellIndices []uint64, cells []Cell) ([CellsPerExtBlob]Cell, [CellsPerExtBlob]KZGProof, error)
+	VerifyCellKZGProofBatch(commitmentsBytes []Bytes48, cellIndices []uint64, cells []Cell, proofsBytes []Bytes48) (bool, error)
+}
+
+// NativeBackend is the Backend of the package level functions, which use the
+// loaded trusted setup.
+type NativeBackend struct{}
+
+var _ Backend = NativeBackend{}
+
+func (NativeBackend) BlobToKZGCommitment(blob *Blob) (KZGCommitment, error) {
+	return BlobToKZGCommitment(blob)
+}
+
+func (NativeBackend) ComputeKZGProof(blob *Blob, zBytes Bytes32) (KZGProof, Bytes32, error) {
+	return ComputeKZGProof(blob, zBytes)
+}
+
+func (NativeBackend) ComputeBlobKZGProof(blob *Blob, commitmentBytes Bytes48) (KZGProof, error) {
+	return ComputeBlobKZGProof(blob, commitmentBytes)
+}
+
+func (NativeBackend) VerifyKZGProof(commitmentBytes Bytes48, zBytes, yBytes Bytes32, proofBytes Bytes48) (bool, error) {
+	return VerifyKZGProof(commitmentBytes, zBytes, yBytes, proofBytes)
+}
+
+func (NativeBackend) VerifyBlobKZGProof(blob *Blob, commitmentBytes, proofBytes Bytes48) (bool, error) {
+	return VerifyBlobKZGProof(blob, commitmentBytes, proofBytes)
+}
+
+func (NativeBackend) VerifyBlobKZGProofBatch(blobs []Blob, commitmentsBytes, proofsBytes []Bytes48) (bool, error) {
+	return VerifyBlobKZGProofBatch(blobs, commitmentsBytes, proofsBytes)
+}
+
+func (NativeBackend) ComputeCellsAndKZGProofs(blob *Blob) ([CellsPerExtBlob]Cell, [CellsPerExtBlob]KZGProof, error) {
+	return ComputeCellsAndKZGProofs(blob)
+}
+
+func (NativeBackend) RecoverCellsAndKZGProofs(cellIndices []uint64, cells []Cell) ([CellsPerExtBlob]Cell, [CellsPerExtBlob]KZGProof, error) {
+	return RecoverCellsAndKZGProofs(cellIndices, cells)
+}
+
+func (NativeBackend) VerifyCellKZGProofBatch(commitmentsBytes []Bytes48, cellIndices []uint64, cells []Cell, proofsBytes []Bytes48) (bool, error) {
+	return VerifyCellKZGProofBatch(commitmentsBytes, cellIndices, cells, proofsBytes)
+}
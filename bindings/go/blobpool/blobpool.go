@@ -0,0 +1,159 @@
+// Package blobpool helps execution layer blob mempools verify the blobs of
+// incoming blob (type 3) transactions once, when they are received, and cheaply
+// check at block building or import time that an included transaction's
+// commitments are the ones which were verified.
+//
+// A trusted setup must be loaded before validating transactions.
+package blobpool
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"errors"
+	"sync"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+)
+
+// VersionedHashVersionKZG is the version byte of versioned hashes of KZG
+// commitments, as defined in EIP-4844.
+const VersionedHashVersionKZG = 0x01
+
+// ErrInvalidBlobProofs is returned by ValidateTx when the blob proofs of a
+// transaction don't verify.
+var ErrInvalidBlobProofs = errors.New("blob proofs don't verify")
+
+// VersionedHash returns the versioned hash of a commitment, which is how blob
+// transactions refer to their blobs.
+func VersionedHash(commitment ckzg4844.Bytes48) [32]byte {
+	hash := sha256.Sum256(commitment[:])
+	hash[0] = VersionedHashVersionKZG
+	return hash
+}
+
+type entry struct {
+	txHash      [32]byte
+	commitments []ckzg4844.Bytes48
+}
+
+/*
+Validator verifies the blobs of transactions and remembers the commitments of
+the transactions which verified, by transaction hash. It remembers at most
+maxTxs transactions, forgetting the oldest first. It is safe for concurrent
+use.
+*/
+type Validator struct {
+	maxTxs int
+
+	mu    sync.Mutex
+	txs   map[[32]byte]*list.Element
+	order *list.List
+}
+
+// NewValidator returns a Validator which remembers up to maxTxs transactions.
+// It panics if maxTxs isn't positive.
+func NewValidator(maxTxs int) *Validator {
+	if maxTxs <= 0 {
+		panic("blobpool: maxTxs must be positive")
+	}
+	return &Validator{
+		maxTxs: maxTxs,
+		txs:    make(map[[32]byte]*list.Element),
+		order:  list.New(),
+	}
+}
+
+/*
+ValidateTx verifies the blobs, commitments and proofs of the transaction with
+hash txHash and remembers its commitments if they verify. It returns
+ErrInvalidBlobProofs if the proofs don't verify. The blobs are verified every
+time, even if a transaction with the same hash was already validated, since
+the hash is chosen by the sender and says nothing about the blobs.
+*/
+func (v *Validator) ValidateTx(txHash [32]byte, blobs []ckzg4844.Blob, commitmentsBytes, proofsBytes []ckzg4844.Bytes48) error {
+	if len(blobs) == 0 || len(blobs) != len(commitmentsBytes) || len(blobs) != len(proofsBytes) {
+		return ckzg4844.ErrBadArgs
+	}
+	ok, err := ckzg4844.VerifyBlobKZGProofBatch(blobs, commitmentsBytes, proofsBytes)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidBlobProofs
+	}
+
+	commitments := append([]ckzg4844.Bytes48(nil), commitmentsBytes...)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if element, ok := v.txs[txHash]; ok {
+		element.Value.(*entry).commitments = commitments
+		return nil
+	}
+	if v.order.Len() >= v.maxTxs {
+		oldest := v.order.Front()
+		v.order.Remove(oldest)
+		delete(v.txs, oldest.Value.(*entry).txHash)
+	}
+	v.txs[txHash] = v.order.PushBack(&entry{txHash: txHash, commitments: commitments})
+	return nil
+}
+
+// Check reports whether the transaction with hash txHash was validated with
+// exactly these commitments.
+func (v *Validator) Check(txHash [32]byte, commitmentsBytes []ckzg4844.Bytes48) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	element, ok := v.txs[txHash]
+	if !ok {
+		return false
+	}
+	commitments := element.Value.(*entry).commitments
+	if len(commitments) != len(commitmentsBytes) {
+		return false
+	}
+	for i := range commitments {
+		if commitments[i] != commitmentsBytes[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckVersionedHashes reports whether the transaction with hash txHash was
+// validated with commitments matching versionedHashes, in order.
+func (v *Validator) CheckVersionedHashes(txHash [32]byte, versionedHashes [][32]byte) bool {
+	v.mu.Lock()
+	element, ok := v.txs[txHash]
+	var commitments []ckzg4844.Bytes48
+	if ok {
+		commitments = element.Value.(*entry).commitments
+	}
+	v.mu.Unlock()
+	if !ok || len(commitments) != len(versionedHashes) {
+		return false
+	}
+	for i := range commitments {
+		if VersionedHash(commitments[i]) != versionedHashes[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Remove forgets the transaction with hash txHash, for example once it has
+// been included or dropped from the pool.
+func (v *Validator) Remove(txHash [32]byte) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if element, ok := v.txs[txHash]; ok {
+		v.order.Remove(element)
+		delete(v.txs, txHash)
+	}
+}
+
+// Len returns the number of remembered transactions.
+func (v *Validator) Len() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.order.Len()
+}
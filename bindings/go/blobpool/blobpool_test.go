@@ -0,0 +1,50 @@
+package blobpool
+
+import (
+	"testing"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/ckzgtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidator(t *testing.T) {
+	ckzgtest.LoadTrustedSetup(t)
+	var blobs []ckzg4844.Blob
+	var commitments, proofs []ckzg4844.Bytes48
+	for i := int64(0); i < 2; i++ {
+		blob := ckzgtest.RandomBlob(i)
+		commitment, err := ckzg4844.BlobToKZGCommitment(blob)
+		require.NoError(t, err)
+		proof, err := ckzg4844.ComputeBlobKZGProof(blob, ckzg4844.Bytes48(commitment))
+		require.NoError(t, err)
+		blobs = append(blobs, *blob)
+		commitments = append(commitments, ckzg4844.Bytes48(commitment))
+		proofs = append(proofs, ckzg4844.Bytes48(proof))
+	}
+	tx1, tx2, tx3 := [32]byte{1}, [32]byte{2}, [32]byte{3}
+
+	v := NewValidator(2)
+	require.NoError(t, v.ValidateTx(tx1, blobs, commitments, proofs))
+	require.True(t, v.Check(tx1, commitments))
+	require.False(t, v.Check(tx1, commitments[:1]))
+	require.True(t, v.CheckVersionedHashes(tx1, [][32]byte{VersionedHash(commitments[0]), VersionedHash(commitments[1])}))
+	require.False(t, v.CheckVersionedHashes(tx1, [][32]byte{VersionedHash(commitments[1]), VersionedHash(commitments[0])}))
+
+	swapped := []ckzg4844.Bytes48{proofs[1], proofs[0]}
+	require.ErrorIs(t, v.ValidateTx(tx2, blobs, commitments, swapped), ErrInvalidBlobProofs)
+	require.False(t, v.Check(tx2, commitments))
+
+	// A known transaction hash doesn't skip the verification of its blobs.
+	corrupted := append([]ckzg4844.Blob(nil), blobs...)
+	corrupted[1][ckzg4844.BytesPerFieldElement-1] ^= 1
+	require.ErrorIs(t, v.ValidateTx(tx1, corrupted, commitments, proofs), ErrInvalidBlobProofs)
+
+	require.NoError(t, v.ValidateTx(tx2, blobs[:1], commitments[:1], proofs[:1]))
+	require.NoError(t, v.ValidateTx(tx3, blobs[1:], commitments[1:], proofs[1:]))
+	require.Equal(t, 2, v.Len())
+	require.False(t, v.Check(tx1, commitments), "oldest transaction should be evicted")
+	v.Remove(tx2)
+	require.False(t, v.Check(tx2, commitments[:1]))
+	require.Equal(t, 1, v.Len())
+}
@@ -0,0 +1,221 @@
+package ckzg4844
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// verificationKey identifies a (commitment, proof, blob) triple which verified.
+type verificationKey struct {
+	commitment Bytes48
+	proof      Bytes48
+	blobHash   [32]byte
+}
+
+type verificationEntry struct {
+	key     verificationKey
+	expires time.Time
+}
+
+/*
+VerificationCache remembers blob proofs which already verified, so that a blob
+verified when it entered the mempool isn't verified again when the block that
+includes it is imported. Entries are keyed by the commitment, the proof and the
+SHA-256 hash of the blob. Only successful verifications are cached.
+
+The cache holds at most size entries, evicting the least recently used entry
+when full, and entries expire ttl after they were added. A ttl of zero means
+entries never expire. It is safe for concurrent use.
+*/
+type VerificationCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[verificationKey]*list.Element
+	lru     *list.List
+	hits    uint64
+	misses  uint64
+}
+
+// NewVerificationCache returns a cache with room for size entries which expire
+// after ttl. It panics if size isn't positive.
+func NewVerificationCache(size int, ttl time.Duration) *VerificationCache {
+	if size <= 0 {
+		panic("verification cache size must be positive")
+	}
+	return &VerificationCache{
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[verificationKey]*list.Element, size),
+		lru:     list.New(),
+	}
+}
+
+func newVerificationKey(blob *Blob, commitmentBytes, proofBytes Bytes48) verificationKey {
+	return verificationKey{
+		commitment: commitmentBytes,
+		proof:      proofBytes,
+		blobHash:   sha256.Sum256(blob[:]),
+	}
+}
+
+// contains reports whether key is cached and hasn't expired.
+func (c *VerificationCache) contains(key verificationKey) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return false
+	}
+	entry := element.Value.(*verificationEntry)
+	if c.ttl > 0 && !c.now().Before(entry.expires) {
+		c.lru.Remove(element)
+		delete(c.entries, key)
+		c.misses++
+		return false
+	}
+	c.lru.MoveToFront(element)
+	c.hits++
+	return true
+}
+
+func (c *VerificationCache) add(key verificationKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expires := c.now().Add(c.ttl)
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*verificationEntry).expires = expires
+		c.lru.MoveToFront(element)
+		return
+	}
+	if c.lru.Len() >= c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*verificationEntry).key)
+	}
+	c.entries[key] = c.lru.PushFront(&verificationEntry{key: key, expires: expires})
+}
+
+// Contains reports whether the triple already verified and is still cached.
+func (c *VerificationCache) Contains(blob *Blob, commitmentBytes, proofBytes Bytes48) bool {
+	if blob == nil {
+		return false
+	}
+	return c.contains(newVerificationKey(blob, commitmentBytes, proofBytes))
+}
+
+/*
+VerifyBlobKZGProof is like the package level VerifyBlobKZGProof, but returns
+true without verifying if the triple is cached, and caches it if it verifies.
+*/
+func (c *VerificationCache) VerifyBlobKZGProof(blob *Blob, commitmentBytes, proofBytes Bytes48) (bool, error) {
+	if blob == nil {
+		return false, &Error{Op: "VerifyBlobKZGProof", Err: ErrBadArgs}
+	}
+	key := newVerificationKey(blob, commitmentBytes, proofBytes)
+	if c.contains(key) {
+		return true, nil
+	}
+	ok, err := VerifyBlobKZGProof(blob, commitmentBytes, proofBytes)
+	if err == nil && ok {
+		c.add(key)
+	}
+	return ok, err
+}
+
+/*
+VerifyBlobKZGProofBatch is like the package level VerifyBlobKZGProofBatch, but
+only verifies the triples which aren't cached. If the batch verifies, all of
+its triples are cached.
+*/
+func (c *VerificationCache) VerifyBlobKZGProofBatch(blobs []Blob, commitmentsBytes, proofsBytes []Bytes48) (bool, error) {
+	result, err := c.VerifyBlobKZGProofBatchWithOptions(blobs, commitmentsBytes, proofsBytes, VerifyOptions{})
+	return result.OK, err
+}
+
+/*
+VerifyBlobKZGProofBatchWithOptions is like the package level
+VerifyBlobKZGProofBatchWithOptions, but only verifies the triples which aren't
+cached, which count as verified in the result. The triples which verify are
+cached: all of them if the batch verifies, otherwise those reported by
+ReturnPerItem.
+*/
+func (c *VerificationCache) VerifyBlobKZGProofBatchWithOptions(blobs []Blob, commitmentsBytes, proofsBytes []Bytes48, opts VerifyOptions) (VerifyResult, error) {
+	if err := checkBlobBatchLengths("VerifyBlobKZGProofBatch", blobs, commitmentsBytes, proofsBytes); err != nil {
+		return VerifyResult{}, err
+	}
+	var keys []verificationKey
+	var uncached []int
+	for i := range blobs {
+		key := newVerificationKey(&blobs[i], commitmentsBytes[i], proofsBytes[i])
+		if !c.contains(key) {
+			keys = append(keys, key)
+			uncached = append(uncached, i)
+		}
+	}
+	var items []bool
+	if opts.ReturnPerItem {
+		items = make([]bool, len(blobs))
+		for i := range items {
+			items[i] = true
+		}
+	}
+	if len(keys) == 0 {
+		return VerifyResult{OK: true, Items: items}, nil
+	}
+	blobs2, commitments, proofs := blobs, commitmentsBytes, proofsBytes
+	if len(keys) < len(blobs) {
+		blobs2 = make([]Blob, len(uncached))
+		commitments = make([]Bytes48, len(uncached))
+		proofs = make([]Bytes48, len(uncached))
+		for j, i := range uncached {
+			blobs2[j], commitments[j], proofs[j] = blobs[i], commitmentsBytes[i], proofsBytes[i]
+		}
+	}
+	result, err := VerifyBlobKZGProofBatchWithOptions(blobs2, commitments, proofs, opts)
+	if err != nil {
+		return VerifyResult{}, err
+	}
+	for j, key := range keys {
+		if result.OK || (result.Items != nil && result.Items[j]) {
+			c.add(key)
+		}
+	}
+	if result.Items == nil {
+		// FailFast stopped early.
+		items = nil
+	} else {
+		for j, i := range uncached {
+			items[i] = result.Items[j]
+		}
+	}
+	return VerifyResult{OK: result.OK, Items: items}, nil
+}
+
+// Len returns the number of cached entries, including expired ones which
+// haven't been evicted yet.
+func (c *VerificationCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// Stats returns the number of cache hits and misses so far.
+func (c *VerificationCache) Stats() (hits, misses uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// Purge removes all entries from the cache.
+func (c *VerificationCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[verificationKey]*list.Element, c.size)
+	c.lru.Init()
+}
@@ -0,0 +1,82 @@
+package ckzg4844
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerificationCache(t *testing.T) {
+	blob := selfTestBlob()
+	commitment, err := BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+	proof, err := ComputeBlobKZGProof(blob, Bytes48(commitment))
+	require.NoError(t, err)
+
+	now := time.Unix(0, 0)
+	cache := NewVerificationCache(1, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	fi := NewFaultInjector()
+	SetFaultInjector(fi)
+	defer SetFaultInjector(nil)
+
+	ok, err := cache.VerifyBlobKZGProof(blob, Bytes48(commitment), Bytes48(proof))
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = cache.VerifyBlobKZGProofBatch([]Blob{*blob}, []Bytes48{Bytes48(commitment)}, []Bytes48{Bytes48(proof)})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 1, fi.Calls("VerifyBlobKZGProof"))
+	require.Equal(t, 0, fi.Calls("VerifyBlobKZGProofBatch"))
+
+	// Failed verifications aren't cached.
+	ok, err = cache.VerifyBlobKZGProof(blob, Bytes48(commitment), Bytes48(commitment))
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.False(t, cache.Contains(blob, Bytes48(commitment), Bytes48(commitment)))
+	require.Equal(t, 1, cache.Len())
+
+	now = now.Add(time.Minute)
+	require.False(t, cache.Contains(blob, Bytes48(commitment), Bytes48(proof)))
+	require.Equal(t, 0, cache.Len())
+}
+
+func TestVerificationCacheWithOptions(t *testing.T) {
+	blobs := []Blob{*selfTestBlob(), *selfTestBlob(), *selfTestBlob()}
+	blobs[1][31] = 1
+	commitments := make([]Bytes48, len(blobs))
+	proofs := make([]Bytes48, len(blobs))
+	for i := range blobs {
+		commitment, err := BlobToKZGCommitment(&blobs[i])
+		require.NoError(t, err)
+		proof, err := ComputeBlobKZGProof(&blobs[i], Bytes48(commitment))
+		require.NoError(t, err)
+		commitments[i], proofs[i] = Bytes48(commitment), Bytes48(proof)
+	}
+	proofs[2] = commitments[2]
+
+	cache := NewVerificationCache(4, 0)
+	ok, err := cache.VerifyBlobKZGProof(&blobs[0], commitments[0], proofs[0])
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	fi := NewFaultInjector()
+	SetFaultInjector(fi)
+	defer SetFaultInjector(nil)
+
+	// The cached triple isn't verified again, and of the others only the one
+	// which verifies is cached.
+	result, err := cache.VerifyBlobKZGProofBatchWithOptions(blobs, commitments, proofs, VerifyOptions{ReturnPerItem: true})
+	require.NoError(t, err)
+	require.False(t, result.OK)
+	require.Equal(t, []bool{true, true, false}, result.Items)
+	require.True(t, cache.Contains(&blobs[1], commitments[1], proofs[1]))
+	require.False(t, cache.Contains(&blobs[2], commitments[2], proofs[2]))
+	require.Equal(t, 2, cache.Len())
+
+	_, err = cache.VerifyBlobKZGProofBatchWithOptions(blobs, commitments[:1], proofs, VerifyOptions{})
+	var mismatch ErrLengthMismatch
+	require.ErrorAs(t, err, &mismatch)
+}
@@ -0,0 +1,139 @@
+// Package cellstore provides CellStore, a building block for the custody
+// databases of data availability sampling nodes, and Verified, an
+// implementation which only stores cells whose proofs verify and checks them
+// again when they are read.
+//
+// A trusted setup must be loaded before storing cells.
+package cellstore
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/proofcache"
+)
+
+var (
+	// ErrNotFound is returned when a cell isn't stored.
+	ErrNotFound = proofcache.ErrNotFound
+	// ErrInvalidProof is returned when storing cells whose proofs don't verify.
+	ErrInvalidProof = errors.New("cell proof doesn't verify")
+	// ErrCorrupted is returned when a stored cell fails its check on read.
+	ErrCorrupted = errors.New("stored cell is corrupted")
+)
+
+// CellStore stores cells with their proofs, by the commitment of their blob
+// and their index.
+type CellStore interface {
+	// Put stores cells, which are as for ckzg4844.VerifyCellKZGProofBatch.
+	Put(commitmentsBytes []ckzg4844.Bytes48, cellIndices []uint64, cells []ckzg4844.Cell, proofsBytes []ckzg4844.Bytes48) error
+	// Get returns a stored cell and its proof, or ErrNotFound.
+	Get(commitmentBytes ckzg4844.Bytes48, cellIndex uint64) (*ckzg4844.Cell, ckzg4844.Bytes48, error)
+}
+
+// ReadCheck is how Verified checks cells when they are read.
+type ReadCheck int
+
+const (
+	// ReadChecksum compares a checksum stored with the cell, which detects
+	// corruption of the storage cheaply.
+	ReadChecksum ReadCheck = iota
+	// ReadVerify verifies the proof of the cell again, which also protects
+	// against storage which was tampered with.
+	ReadVerify
+)
+
+const (
+	valueSize    = ckzg4844.BytesPerCell + ckzg4844.BytesPerProof + sha256.Size
+	checksumFrom = ckzg4844.BytesPerCell + ckzg4844.BytesPerProof
+)
+
+/*
+Verified is a CellStore over a key-value store which verifies the proofs of
+cells before storing them, so only valid cells are ever persisted, and checks
+cells when they are read according to its ReadCheck.
+*/
+type Verified struct {
+	store proofcache.Store
+	check ReadCheck
+}
+
+var _ CellStore = (*Verified)(nil)
+
+// NewVerified returns a Verified storing cells in store.
+func NewVerified(store proofcache.Store, check ReadCheck) *Verified {
+	return &Verified{store: store, check: check}
+}
+
+func key(commitmentBytes ckzg4844.Bytes48, cellIndex uint64) []byte {
+	k := make([]byte, 0, len(commitmentBytes)+8)
+	k = append(k, commitmentBytes[:]...)
+	return binary.BigEndian.AppendUint64(k, cellIndex)
+}
+
+// checksum covers the key as well, so that a value can't be moved to another key.
+func checksum(k, cellAndProof []byte) [sha256.Size]byte {
+	h := sha256.New()
+	h.Write(k)
+	h.Write(cellAndProof)
+	var out [sha256.Size]byte
+	h.Sum(out[:0])
+	return out
+}
+
+// Put verifies the proofs of the cells as a batch and stores the cells if they
+// verify. Otherwise it returns ErrInvalidProof and stores nothing.
+func (v *Verified) Put(commitmentsBytes []ckzg4844.Bytes48, cellIndices []uint64, cells []ckzg4844.Cell, proofsBytes []ckzg4844.Bytes48) error {
+	ok, err := ckzg4844.VerifyCellKZGProofBatch(commitmentsBytes, cellIndices, cells, proofsBytes)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidProof
+	}
+	for i := range cells {
+		k := key(commitmentsBytes[i], cellIndices[i])
+		value := make([]byte, 0, valueSize)
+		value = append(value, cells[i][:]...)
+		value = append(value, proofsBytes[i][:]...)
+		sum := checksum(k, value)
+		value = append(value, sum[:]...)
+		if err := v.store.Put(k, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get returns a stored cell and its proof after checking them, or
+// ErrCorrupted if the check fails.
+func (v *Verified) Get(commitmentBytes ckzg4844.Bytes48, cellIndex uint64) (*ckzg4844.Cell, ckzg4844.Bytes48, error) {
+	k := key(commitmentBytes, cellIndex)
+	value, err := v.store.Get(k)
+	if err != nil {
+		return nil, ckzg4844.Bytes48{}, err
+	}
+	if len(value) != valueSize {
+		return nil, ckzg4844.Bytes48{}, ErrCorrupted
+	}
+	cell := new(ckzg4844.Cell)
+	var proof ckzg4844.Bytes48
+	copy(cell[:], value)
+	copy(proof[:], value[ckzg4844.BytesPerCell:])
+
+	switch v.check {
+	case ReadVerify:
+		ok, err := ckzg4844.VerifyCellKZGProofBatch(
+			[]ckzg4844.Bytes48{commitmentBytes}, []uint64{cellIndex}, []ckzg4844.Cell{*cell}, []ckzg4844.Bytes48{proof})
+		if err != nil || !ok {
+			return nil, ckzg4844.Bytes48{}, ErrCorrupted
+		}
+	default:
+		if checksum(k, value[:checksumFrom]) != *(*[sha256.Size]byte)(value[checksumFrom:]) {
+			return nil, ckzg4844.Bytes48{}, ErrCorrupted
+		}
+	}
+	return cell, proof, nil
+}
@@ -0,0 +1,54 @@
+package cellstore
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/ckzgtest"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/proofcache"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerified(t *testing.T) {
+	ckzgtest.LoadTrustedSetup(t)
+	blob := ckzgtest.RandomBlob(1)
+	commitment, err := ckzg4844.BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+	cells, proofs, err := ckzg4844.ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+	c := ckzg4844.Bytes48(commitment)
+
+	for _, check := range []ReadCheck{ReadChecksum, ReadVerify} {
+		dir := t.TempDir()
+		kv, err := proofcache.NewDirStore(dir)
+		require.NoError(t, err)
+		s := NewVerified(kv, check)
+
+		err = s.Put([]ckzg4844.Bytes48{c}, []uint64{1}, []ckzg4844.Cell{cells[0]}, []ckzg4844.Bytes48{ckzg4844.Bytes48(proofs[0])})
+		require.ErrorIs(t, err, ErrInvalidProof)
+		_, _, err = s.Get(c, 1)
+		require.ErrorIs(t, err, ErrNotFound)
+
+		require.NoError(t, s.Put(
+			[]ckzg4844.Bytes48{c, c},
+			[]uint64{0, 5},
+			[]ckzg4844.Cell{cells[0], cells[5]},
+			[]ckzg4844.Bytes48{ckzg4844.Bytes48(proofs[0]), ckzg4844.Bytes48(proofs[5])}))
+		cell, proof, err := s.Get(c, 5)
+		require.NoError(t, err)
+		require.Equal(t, cells[5], *cell)
+		require.Equal(t, ckzg4844.Bytes48(proofs[5]), proof)
+
+		// Flip a bit of the stored cell.
+		path := filepath.Join(dir, hex.EncodeToString(key(c, 5)))
+		value, err := os.ReadFile(path)
+		require.NoError(t, err)
+		value[100] ^= 1
+		require.NoError(t, os.WriteFile(path, value, 0o644))
+		_, _, err = s.Get(c, 5)
+		require.ErrorIs(t, err, ErrCorrupted)
+	}
+}
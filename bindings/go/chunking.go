@@ -0,0 +1,75 @@
+package ckzg4844
+
+import (
+	"sync/atomic"
+)
+
+/*
+BatchChunking controls how VerifyBlobKZGProofBatch and VerifyCellKZGProofBatch
+split very large batches. A batch which would need more memory in the C library
+than allowed is verified as several chunks, and the batch verifies only if
+every chunk does. Callers verifying thousands of cells or hundreds of blobs at
+once don't have to split batches themselves to avoid allocation failures.
+*/
+type BatchChunking struct {
+	// MemoryBudget is the memory in bytes a single call into the C library
+	// may allocate for a batch, estimated from the number of items. Zero
+	// means only the library's own limit applies.
+	MemoryBudget int
+	// Parallelism is the number of chunks verified concurrently. Chunks are
+	// verified one after the other if it is less than two.
+	Parallelism int
+}
+
+var batchChunking atomic.Pointer[BatchChunking]
+
+// SetBatchChunking sets how large batches are split for all later
+// verifications.
+func SetBatchChunking(c BatchChunking) {
+	batchChunking.Store(&c)
+}
+
+const (
+	// maxChunkBytes is the most memory a single call is allowed to
+	// allocate, which also keeps the C library's size computations far from
+	// overflowing on 32-bit platforms.
+	maxChunkBytes = 1 << 30
+
+	// Rough upper bounds of the memory allocated by the C library per item.
+	blobBatchItemBytes = 512
+	cellBatchItemBytes = BytesPerCell + 1024
+)
+
+// chunkSize returns the number of items of itemBytes bytes which are verified
+// in one call, and the number of chunks verified concurrently.
+func chunkSize(itemBytes int) (int, int) {
+	budget, parallelism := maxChunkBytes, 1
+	if c := batchChunking.Load(); c != nil {
+		if c.MemoryBudget > 0 && c.MemoryBudget < budget {
+			budget = c.MemoryBudget
+		}
+		if c.Parallelism > 1 {
+			parallelism = c.Parallelism
+		}
+	}
+	size := budget / itemBytes
+	if size < 1 {
+		size = 1
+	}
+	return size, parallelism
+}
+
+// verifyChunked verifies n items, in chunks if they don't fit in the memory
+// budget. As with a single call, it returns an error if any item isn't validly
+// encoded.
+func verifyChunked(n, itemBytes int, verify func(start, end int) (bool, error)) (bool, error) {
+	size, parallelism := chunkSize(itemBytes)
+	if n <= size {
+		return verify(0, n)
+	}
+	if chunks := (n + size - 1) / size; parallelism > chunks {
+		parallelism = chunks
+	}
+	result, err := verifyChunks(n, size, parallelism, VerifyOptions{ValidateEncodings: true}, verify)
+	return result.OK, err
+}
@@ -0,0 +1,63 @@
+package ckzg4844
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchChunking(t *testing.T) {
+	defer SetBatchChunking(BatchChunking{})
+	EnableLatencyHistograms(true)
+	defer EnableLatencyHistograms(false)
+	ResetLatencyHistograms()
+	defer ResetLatencyHistograms()
+
+	blob := selfTestBlob()
+	commitment, err := BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+	cells, proofs, err := ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+	var commitments, cellProofs []Bytes48
+	var cellIndices []uint64
+	for i := range cells {
+		commitments = append(commitments, Bytes48(commitment))
+		cellIndices = append(cellIndices, uint64(i))
+		cellProofs = append(cellProofs, Bytes48(proofs[i]))
+	}
+
+	for _, parallelism := range []int{0, 3} {
+		// Chunks of 10 cells.
+		SetBatchChunking(BatchChunking{MemoryBudget: 10 * cellBatchItemBytes, Parallelism: parallelism})
+		ResetLatencyHistograms()
+		ok, err := VerifyCellKZGProofBatch(commitments, cellIndices, cells[:], cellProofs)
+		require.NoError(t, err)
+		require.True(t, ok)
+		histograms := LatencyHistograms()
+		require.Len(t, histograms, 1)
+		require.Equal(t, uint64(13), histograms[0].Count)
+
+		cellProofs[100], cellProofs[101] = cellProofs[101], cellProofs[100]
+		ok, err = VerifyCellKZGProofBatch(commitments, cellIndices, cells[:], cellProofs)
+		require.NoError(t, err)
+		require.False(t, ok)
+		cellProofs[100], cellProofs[101] = cellProofs[101], cellProofs[100]
+
+		commitments[127] = Bytes48{}
+		_, err = VerifyCellKZGProofBatch(commitments, cellIndices, cells[:], cellProofs)
+		require.ErrorIs(t, err, ErrBadArgs)
+		commitments[127] = Bytes48(commitment)
+	}
+
+	SetBatchChunking(BatchChunking{MemoryBudget: 1})
+	proof, err := ComputeBlobKZGProof(blob, Bytes48(commitment))
+	require.NoError(t, err)
+	ResetLatencyHistograms()
+	ok, err := VerifyBlobKZGProofBatch(
+		[]Blob{*blob, *blob},
+		[]Bytes48{Bytes48(commitment), Bytes48(commitment)},
+		[]Bytes48{Bytes48(proof), Bytes48(proof)})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, uint64(2), LatencyHistograms()[0].Count)
+}
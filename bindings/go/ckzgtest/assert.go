@@ -0,0 +1,107 @@
+package ckzgtest
+
+import (
+	"math/rand"
+	"testing"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/internal/fixtures"
+)
+
+// RandomFieldElement returns a random canonical field element.
+func RandomFieldElement(r *rand.Rand) ckzg4844.Bytes32 {
+	return fixtures.RandomFieldElement(r)
+}
+
+// RandomBlob returns a blob of random canonical field elements derived from seed.
+func RandomBlob(seed int64) *ckzg4844.Blob {
+	return fixtures.RandomBlob(seed)
+}
+
+// RequireVerifies fails the test unless proof is a valid blob proof for blob and commitment.
+func RequireVerifies(tb testing.TB, blob *ckzg4844.Blob, commitment ckzg4844.KZGCommitment, proof ckzg4844.KZGProof) {
+	tb.Helper()
+	ok, err := ckzg4844.VerifyBlobKZGProof(blob, ckzg4844.Bytes48(commitment), ckzg4844.Bytes48(proof))
+	if err != nil {
+		tb.Fatalf("VerifyBlobKZGProof failed: %v", err)
+	}
+	if !ok {
+		tb.Fatalf("VerifyBlobKZGProof rejected proof %v for commitment %v", proof, commitment)
+	}
+}
+
+// RequireCellsVerify fails the test unless every cell proof is valid for commitment.
+func RequireCellsVerify(tb testing.TB, commitment ckzg4844.KZGCommitment, cells *[ckzg4844.CellsPerExtBlob]ckzg4844.Cell, proofs *[ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof) {
+	tb.Helper()
+	commitments := make([]ckzg4844.Bytes48, ckzg4844.CellsPerExtBlob)
+	cellIndices := make([]uint64, ckzg4844.CellsPerExtBlob)
+	proofsBytes := make([]ckzg4844.Bytes48, ckzg4844.CellsPerExtBlob)
+	for i := range cellIndices {
+		commitments[i] = ckzg4844.Bytes48(commitment)
+		cellIndices[i] = uint64(i)
+		proofsBytes[i] = ckzg4844.Bytes48(proofs[i])
+	}
+	ok, err := ckzg4844.VerifyCellKZGProofBatch(commitments, cellIndices, cells[:], proofsBytes)
+	if err != nil {
+		tb.Fatalf("VerifyCellKZGProofBatch failed: %v", err)
+	}
+	if !ok {
+		tb.Fatalf("VerifyCellKZGProofBatch rejected cells for commitment %v", commitment)
+	}
+}
+
+// RequireRecoverable fails the test unless the cells at cellIndices are enough
+// to recover exactly the given cells and proofs.
+func RequireRecoverable(tb testing.TB, cells *[ckzg4844.CellsPerExtBlob]ckzg4844.Cell, proofs *[ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof, cellIndices []uint64) {
+	tb.Helper()
+	partialCells := make([]ckzg4844.Cell, len(cellIndices))
+	for i, cellIndex := range cellIndices {
+		partialCells[i] = cells[cellIndex]
+	}
+	recoveredCells := new([ckzg4844.CellsPerExtBlob]ckzg4844.Cell)
+	recoveredProofs := new([ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof)
+	err := ckzg4844.RecoverCellsAndKZGProofsInto(recoveredCells, recoveredProofs, cellIndices, partialCells)
+	if err != nil {
+		tb.Fatalf("RecoverCellsAndKZGProofs failed: %v", err)
+	}
+	if *recoveredCells != *cells {
+		tb.Fatalf("recovered cells do not match the original cells")
+	}
+	if *recoveredProofs != *proofs {
+		tb.Fatalf("recovered proofs do not match the original proofs")
+	}
+}
+
+// HalfCellIndices returns the indices of every other cell, which is the
+// minimum needed for recovery.
+func HalfCellIndices() []uint64 {
+	return fixtures.HalfCellIndices()
+}
+
+/*
+RequireRoundTrip checks the core properties of the library for blob:
+
+  - a blob proof for its commitment verifies,
+  - every cell proof verifies against the commitment,
+  - the cells and proofs can be recovered from half of the cells.
+*/
+func RequireRoundTrip(tb testing.TB, blob *ckzg4844.Blob) {
+	tb.Helper()
+	commitment, err := ckzg4844.BlobToKZGCommitment(blob)
+	if err != nil {
+		tb.Fatalf("BlobToKZGCommitment failed: %v", err)
+	}
+	proof, err := ckzg4844.ComputeBlobKZGProof(blob, ckzg4844.Bytes48(commitment))
+	if err != nil {
+		tb.Fatalf("ComputeBlobKZGProof failed: %v", err)
+	}
+	RequireVerifies(tb, blob, commitment, proof)
+
+	cells := new([ckzg4844.CellsPerExtBlob]ckzg4844.Cell)
+	proofs := new([ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof)
+	if err := ckzg4844.ComputeCellsAndKZGProofsInto(cells, proofs, blob); err != nil {
+		tb.Fatalf("ComputeCellsAndKZGProofs failed: %v", err)
+	}
+	RequireCellsVerify(tb, commitment, cells, proofs)
+	RequireRecoverable(tb, cells, proofs, HalfCellIndices())
+}
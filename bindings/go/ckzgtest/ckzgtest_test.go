@@ -0,0 +1,121 @@
+package ckzgtest
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/quick"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+)
+
+func TestInsecureTrustedSetup(t *testing.T) {
+	LoadInsecureTrustedSetup(t)
+	RequireRoundTrip(t, RandomBlob(1))
+	RequireRoundTrip(t, new(ckzg4844.Blob))
+}
+
+func TestReferenceTests(t *testing.T) {
+	type Test struct {
+		Input struct {
+			Blob string `yaml:"blob"`
+		}
+	}
+	for _, testPath := range ReferenceTests(t, "blob_to_kzg_commitment") {
+		var test Test
+		DecodeYAML(t, testPath, &test)
+		if test.Input.Blob == "" {
+			t.Fatalf("%s: missing blob", testPath)
+		}
+	}
+}
+
+func TestWriteGoldenFixtures(t *testing.T) {
+	LoadInsecureTrustedSetup(t)
+	dirs := []string{t.TempDir(), t.TempDir()}
+	for _, dir := range dirs {
+		if err := WriteGoldenFixtures(dir, 7); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The output must be byte-for-byte identical across runs.
+	for _, name := range []string{"seed.txt", "zero/blob.txt", "random_0/commitment.txt", "random_2/cells.txt", "random_2/cell_proofs.txt"} {
+		a, err := os.ReadFile(filepath.Join(dirs[0], name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := os.ReadFile(filepath.Join(dirs[1], name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(a, b) {
+			t.Fatalf("%s differs between runs", name)
+		}
+	}
+
+	fixtures, err := GoldenFixtures(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitment, err := os.ReadFile(filepath.Join(dirs[0], "random_0", "commitment.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(commitment) != fixtures[1].Commitment.String()+"\n" {
+		t.Fatalf("unexpected commitment file: %s", commitment)
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	LoadInsecureTrustedSetup(t)
+
+	property := func(blob GenBlob, subset GenCellSubset) bool {
+		b := ckzg4844.Blob(blob)
+		cells, proofs, err := ckzg4844.ComputeCellsAndKZGProofs(&b)
+		if err != nil {
+			return false
+		}
+		RequireRecoverable(t, &cells, &proofs, subset)
+		return true
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	canonical := func(fieldElement GenCanonicalFieldElement) bool {
+		var blob ckzg4844.Blob
+		copy(blob[:], fieldElement[:])
+		_, err := ckzg4844.BlobToKZGCommitment(&blob)
+		return err == nil
+	}
+	if err := quick.Check(canonical, &quick.Config{MaxCount: 16}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFixture(t *testing.T) {
+	LoadInsecureTrustedSetup(t)
+	for _, name := range FixtureNames() {
+		fixture, err := Fixture(name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if fixture.Version != FixturesVersion {
+			t.Fatalf("%s: unexpected version %d", name, fixture.Version)
+		}
+		RequireVerifies(t, fixture.Blob, fixture.Commitment, fixture.Proof)
+		RequireCellsVerify(t, fixture.Commitment, fixture.Cells, fixture.CellProofs)
+	}
+
+	if _, err := Fixture("zero-blob@v1"); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"unknown", "zero-blob@v0", "zero-blob@vx"} {
+		if _, err := Fixture(name); !errors.Is(err, ErrUnknownFixture) {
+			t.Fatalf("%s: expected ErrUnknownFixture, got %v", name, err)
+		}
+	}
+}
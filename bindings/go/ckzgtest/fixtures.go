@@ -0,0 +1,48 @@
+package ckzgtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/internal/fixtures"
+	"gopkg.in/yaml.v3"
+)
+
+// ReferenceTestsDir returns the directory containing the reference tests. The
+// tests are only found by the tests of this module, which run inside the
+// c-kzg-4844 source tree; elsewhere it panics.
+func ReferenceTestsDir() string {
+	dir, err := fixtures.ReferenceTestsDir()
+	if err != nil {
+		panic(err)
+	}
+	return dir
+}
+
+// ReferenceTests returns the paths of all reference test files for the given
+// spec function, e.g. "verify_blob_kzg_proof".
+func ReferenceTests(tb testing.TB, name string) []string {
+	tb.Helper()
+	tests, err := filepath.Glob(filepath.Join(ReferenceTestsDir(), name, "*", "*", "*"))
+	if err != nil {
+		tb.Fatalf("failed to glob reference tests: %v", err)
+	}
+	if len(tests) == 0 {
+		tb.Fatalf("no reference tests found for %s", name)
+	}
+	return tests
+}
+
+// DecodeYAML decodes the YAML file at path into v.
+func DecodeYAML(tb testing.TB, path string, v any) {
+	tb.Helper()
+	testFile, err := os.Open(path)
+	if err != nil {
+		tb.Fatalf("failed to open %s: %v", path, err)
+	}
+	defer testFile.Close()
+	if err := yaml.NewDecoder(testFile).Decode(v); err != nil {
+		tb.Fatalf("failed to decode %s: %v", path, err)
+	}
+}
@@ -0,0 +1,89 @@
+package ckzgtest
+
+import (
+	"math/big"
+	"math/rand"
+	"reflect"
+	"sort"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+)
+
+/*
+The Gen* types implement testing/quick's Generator interface, so they can be
+used directly as arguments of property functions:
+
+	quick.Check(func(blob ckzgtest.GenBlob, subset ckzgtest.GenCellSubset) bool {
+		...
+	}, nil)
+
+For other property testing libraries, such as rapid, the underlying
+CanonicalFieldElement, CanonicalBlob, and CellSubset functions only need a
+*rand.Rand, which can be seeded from a drawn value:
+
+	rapid.Custom(func(t *rapid.T) *ckzg4844.Blob {
+		return ckzgtest.CanonicalBlob(rand.New(rand.NewSource(rapid.Int64().Draw(t, "seed"))))
+	})
+*/
+
+type (
+	// GenCanonicalFieldElement is a uniformly random canonical field element.
+	GenCanonicalFieldElement ckzg4844.Bytes32
+	// GenBlob is a blob of canonical field elements, biased towards edge cases.
+	GenBlob ckzg4844.Blob
+	// GenCellSubset is a sorted set of cell indices which is large enough for recovery.
+	GenCellSubset []uint64
+)
+
+// CanonicalFieldElement returns a uniformly random canonical field element.
+func CanonicalFieldElement(r *rand.Rand) ckzg4844.Bytes32 {
+	x := new(big.Int).Rand(r, blsModulus)
+	var fieldElement ckzg4844.Bytes32
+	x.FillBytes(fieldElement[:])
+	return fieldElement
+}
+
+// CanonicalBlob returns a blob of canonical field elements. Each element has a
+// small chance of being zero, one, or the largest canonical value.
+func CanonicalBlob(r *rand.Rand) *ckzg4844.Blob {
+	maxElement := new(big.Int).Sub(blsModulus, big.NewInt(1))
+	blob := new(ckzg4844.Blob)
+	for i := 0; i < ckzg4844.FieldElementsPerBlob; i++ {
+		fieldElement := blob[i*ckzg4844.BytesPerFieldElement : (i+1)*ckzg4844.BytesPerFieldElement]
+		switch r.Intn(16) {
+		case 0:
+			// Leave it as zero.
+		case 1:
+			fieldElement[ckzg4844.BytesPerFieldElement-1] = 1
+		case 2:
+			maxElement.FillBytes(fieldElement)
+		default:
+			x := CanonicalFieldElement(r)
+			copy(fieldElement, x[:])
+		}
+	}
+	return blob
+}
+
+// CellSubset returns a sorted random set of at least minCells distinct cell indices.
+func CellSubset(r *rand.Rand, minCells int) []uint64 {
+	numCells := minCells + r.Intn(ckzg4844.CellsPerExtBlob-minCells+1)
+	cellIndices := make([]uint64, numCells)
+	for i, cellIndex := range r.Perm(ckzg4844.CellsPerExtBlob)[:numCells] {
+		cellIndices[i] = uint64(cellIndex)
+	}
+	sort.Slice(cellIndices, func(i, j int) bool { return cellIndices[i] < cellIndices[j] })
+	return cellIndices
+}
+
+func (GenCanonicalFieldElement) Generate(r *rand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(GenCanonicalFieldElement(CanonicalFieldElement(r)))
+}
+
+func (GenBlob) Generate(r *rand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(GenBlob(*CanonicalBlob(r)))
+}
+
+func (GenCellSubset) Generate(r *rand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(GenCellSubset(CellSubset(r, ckzg4844.CellsPerExtBlob/2)))
+}
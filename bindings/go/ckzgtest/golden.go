@@ -0,0 +1,42 @@
+package ckzgtest
+
+import (
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/internal/fixtures"
+)
+
+// NumRandomGoldenFixtures is the number of random blobs in the golden fixtures.
+const NumRandomGoldenFixtures = fixtures.NumRandomGoldenFixtures
+
+// GoldenFixture holds a blob and everything derived from it.
+type GoldenFixture = fixtures.GoldenFixture
+
+// NewGoldenFixture computes the commitment, proof, cells, and cell proofs for blob.
+func NewGoldenFixture(name string, blob *ckzg4844.Blob) (*GoldenFixture, error) {
+	return fixtures.NewGoldenFixture(name, blob)
+}
+
+// GoldenFixtures returns the zero blob fixture followed by
+// NumRandomGoldenFixtures random blob fixtures derived from seed.
+func GoldenFixtures(seed int64) ([]*GoldenFixture, error) {
+	return fixtures.GoldenFixtures(seed)
+}
+
+/*
+WriteGoldenFixtures writes the fixtures from GoldenFixtures to dir, one
+directory per fixture. Every file has one hex value per line (blobs are split
+into field elements), so the output is stable and a diff of two runs points
+directly at the values that changed. setup.txt holds the fingerprint of the
+trusted setup which the values were computed with:
+
+	dir/seed.txt
+	dir/setup.txt
+	dir/<name>/blob.txt
+	dir/<name>/commitment.txt
+	dir/<name>/proof.txt
+	dir/<name>/cells.txt
+	dir/<name>/cell_proofs.txt
+*/
+func WriteGoldenFixtures(dir string, seed int64) error {
+	return fixtures.WriteGoldenFixtures(dir, seed)
+}
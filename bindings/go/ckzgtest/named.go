@@ -0,0 +1,31 @@
+package ckzgtest
+
+import "github.com/ethereum/c-kzg-4844/v2/bindings/go/internal/fixtures"
+
+// FixturesVersion is the current version of the named fixtures. It is bumped
+// whenever the contents of an existing fixture change, so regression tests can
+// pin the version they were written against.
+const FixturesVersion = fixtures.FixturesVersion
+
+var ErrUnknownFixture = fixtures.ErrUnknownFixture
+
+// FixtureNames returns the names of all named fixtures, sorted.
+func FixtureNames() []string {
+	return fixtures.FixtureNames()
+}
+
+/*
+Fixture returns the named fixture, computed with the loaded trusted setup. The
+name may have a version suffix, like "zero-blob@v1"; without one, the latest
+version (FixturesVersion) is returned. The available names are:
+
+  - zero-blob: every field element is zero
+  - max-value-elements: every field element is the largest canonical value
+  - single-nonzero: the first field element is one, the rest are zero
+  - last-nonzero: the last field element is one, the rest are zero
+  - ascending-elements: the i-th field element is i
+  - random: random canonical field elements from a fixed seed
+*/
+func Fixture(name string) (*GoldenFixture, error) {
+	return fixtures.Fixture(name)
+}
@@ -0,0 +1,81 @@
+package ckzgtest
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/devnet"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/mainnet"
+)
+
+const (
+	// NumG1Points is the number of G1 points in a trusted setup.
+	NumG1Points = devnet.NumG1Points
+	// NumG2Points is the number of G2 points in a trusted setup.
+	NumG2Points = devnet.NumG2Points
+	// InsecureSecret is the default secret used by LoadInsecureTrustedSetup.
+	InsecureSecret = devnet.DefaultSecret
+)
+
+var (
+	// blsModulus is the order of the BLS12-381 scalar field.
+	blsModulus = new(big.Int).SetBytes(ckzg4844.BLSModulus[:])
+
+	setupOnce sync.Once
+	setupKind string
+	setupErr  error
+)
+
+/*
+InsecureTrustedSetup generates a trusted setup from a publicly known secret.
+The returned byte slices are in the form expected by LoadTrustedSetup. Anyone
+who knows the secret can forge proofs, so this must only be used for testing.
+It is devnet.InsecureTrustedSetup.
+*/
+func InsecureTrustedSetup(secret uint64) (g1MonomialBytes, g1LagrangeBytes, g2MonomialBytes []byte, err error) {
+	return devnet.InsecureTrustedSetup(secret)
+}
+
+// loadOnce loads a trusted setup into the default context the first time it is
+// called. The package level functions of every test in the process share that
+// context, so asking for a different kind of setup afterwards is a test
+// failure; tests which need several setups should use their own Context.
+func loadOnce(tb testing.TB, kind string, load func() error) {
+	tb.Helper()
+	setupOnce.Do(func() {
+		setupKind = kind
+		setupErr = load()
+	})
+	if setupErr != nil {
+		tb.Fatalf("failed to load %s trusted setup: %v", setupKind, setupErr)
+	}
+	if setupKind != kind {
+		tb.Fatalf("cannot load %s trusted setup, %s trusted setup already loaded", kind, setupKind)
+	}
+}
+
+// LoadTrustedSetup loads the mainnet trusted setup, which is embedded in the
+// mainnet package, for the rest of the process. It is safe to call from every
+// test; only the first call does any work.
+func LoadTrustedSetup(tb testing.TB) {
+	tb.Helper()
+	loadOnce(tb, "mainnet", func() error {
+		return ckzg4844.LoadTrustedSetupFromReader(mainnet.TrustedSetup(), 0)
+	})
+}
+
+// LoadInsecureTrustedSetup loads a trusted setup generated from InsecureSecret
+// for the rest of the process. It is safe to call from every test; only the
+// first call does any work.
+func LoadInsecureTrustedSetup(tb testing.TB) {
+	tb.Helper()
+	loadOnce(tb, "insecure", func() error {
+		g1Monomial, g1Lagrange, g2Monomial, err := InsecureTrustedSetup(InsecureSecret)
+		if err != nil {
+			return err
+		}
+		return ckzg4844.LoadTrustedSetup(g1Monomial, g1Lagrange, g2Monomial, 0)
+	})
+}
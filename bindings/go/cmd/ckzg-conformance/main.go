@@ -0,0 +1,211 @@
+// Command ckzg-conformance runs the reference tests and a set of internal edge
+// cases against these bindings, and writes a machine-readable JSON report of
+// the results for each spec function and preset. The exit status is non-zero
+// if any test failed.
+//
+// Usage:
+//
+//	ckzg-conformance [-tests DIR] [-trusted-setup FILE] [-spec-version V] [-out FILE]
+//
+// Without -tests, the reference tests embedded with the ckzg_refvectors build
+// tag are run. Without -trusted-setup, the mainnet trusted setup is used; the
+// internal edge cases are only run with it, as their expected outputs were
+// computed with it.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"time"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/internal/fixtures"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/mainnet"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/refvectors"
+)
+
+// internalPreset is the preset reported for the internal edge cases.
+const internalPreset = "internal"
+
+type Report struct {
+	ModuleVersion string               `json:"module_version"`
+	SpecVersion   string               `json:"spec_version"`
+	GoVersion     string               `json:"go_version"`
+	Platform      string               `json:"platform"`
+	GeneratedAt   time.Time            `json:"generated_at"`
+	Passed        bool                 `json:"passed"`
+	Summary       []refvectors.Summary `json:"summary"`
+	Failures      []refvectors.Failure `json:"failures"`
+}
+
+// moduleVersion returns the version of the bindings module in this binary.
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	if info.Main.Path == "github.com/ethereum/c-kzg-4844/v2" {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == "github.com/ethereum/c-kzg-4844/v2" {
+			return dep.Version
+		}
+	}
+	return "unknown"
+}
+
+/*
+edgeCaseResults checks the named fixtures, which cover edge cases that the
+reference tests don't, against each spec function they exercise. The outputs
+are compared with the expected ones, which are committed with the fixtures
+and were computed with the mainnet trusted setup.
+*/
+func edgeCaseResults(expected *fixtures.ExpectedOutputs) []refvectors.Result {
+	var results []refvectors.Result
+	add := func(function, name string, err error) {
+		results = append(results, refvectors.Result{Function: function, Preset: internalPreset, Name: name, Err: err})
+	}
+	names := make([]string, 0, len(expected.Fixtures))
+	for name := range expected.Fixtures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		want := expected.Fixtures[name]
+		blob, _, err := fixtures.FixtureBlob(name)
+		if err != nil {
+			add("blob_to_kzg_commitment", name, err)
+			continue
+		}
+
+		commitment, err := ckzg4844.BlobToKZGCommitment(blob)
+		if err == nil && commitment != want.Commitment {
+			err = fmt.Errorf("commitment is %#x, expected %#x", commitment, want.Commitment)
+		}
+		add("blob_to_kzg_commitment", name, err)
+
+		proof, err := ckzg4844.ComputeBlobKZGProof(blob, ckzg4844.Bytes48(want.Commitment))
+		if err == nil && proof != want.Proof {
+			err = fmt.Errorf("proof is %#x, expected %#x", proof, want.Proof)
+		}
+		add("compute_blob_kzg_proof", name, err)
+
+		ok, err := ckzg4844.VerifyBlobKZGProof(blob, ckzg4844.Bytes48(want.Commitment), ckzg4844.Bytes48(want.Proof))
+		if err == nil && !ok {
+			err = fmt.Errorf("valid proof was rejected")
+		}
+		add("verify_blob_kzg_proof", name, err)
+
+		allCells, allProofs, err := ckzg4844.ComputeCellsAndKZGProofs(blob)
+		if err == nil && (fixtures.HashCells(allCells[:]) != want.CellsHash || fixtures.HashProofs(allProofs[:]) != want.CellProofsHash) {
+			err = fmt.Errorf("cells or cell proofs differ from the expected ones")
+		}
+		add("compute_cells_and_kzg_proofs", name, err)
+		if err != nil {
+			continue
+		}
+
+		cellIndices := fixtures.HalfCellIndices()
+		commitments := make([]ckzg4844.Bytes48, len(cellIndices))
+		cells := make([]ckzg4844.Cell, len(cellIndices))
+		proofs := make([]ckzg4844.Bytes48, len(cellIndices))
+		for i, cellIndex := range cellIndices {
+			commitments[i] = ckzg4844.Bytes48(want.Commitment)
+			cells[i] = allCells[cellIndex]
+			proofs[i] = ckzg4844.Bytes48(allProofs[cellIndex])
+		}
+		ok, err = ckzg4844.VerifyCellKZGProofBatch(commitments, cellIndices, cells, proofs)
+		if err == nil && !ok {
+			err = fmt.Errorf("valid cell proofs were rejected")
+		}
+		add("verify_cell_kzg_proof_batch", name, err)
+
+		recoveredCells, recoveredProofs, err := ckzg4844.RecoverCellsAndKZGProofs(cellIndices, cells)
+		if err == nil && (fixtures.HashCells(recoveredCells[:]) != want.CellsHash || fixtures.HashProofs(recoveredProofs[:]) != want.CellProofsHash) {
+			err = fmt.Errorf("recovered cells or cell proofs differ from the expected ones")
+		}
+		add("recover_cells_and_kzg_proofs", name, err)
+	}
+	return results
+}
+
+func buildReport(results []refvectors.Result, specVersion string) *Report {
+	summary, failures := refvectors.Summarize(results)
+	return &Report{
+		ModuleVersion: moduleVersion(),
+		SpecVersion:   specVersion,
+		GoVersion:     runtime.Version(),
+		Platform:      runtime.GOOS + "/" + runtime.GOARCH,
+		GeneratedAt:   time.Now().UTC(),
+		Passed:        len(failures) == 0,
+		Summary:       summary,
+		Failures:      failures,
+	}
+}
+
+func fatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(2)
+}
+
+func main() {
+	testsDir := flag.String("tests", "", "directory containing the reference tests (the embedded tests if empty)")
+	specVersion := flag.String("spec-version", "", "version of the reference tests, recorded in the report")
+	trustedSetup := flag.String("trusted-setup", "", "path to the trusted setup file (the mainnet setup if empty)")
+	out := flag.String("out", "-", "file to write the report to, or - for stdout")
+	flag.Parse()
+
+	var err error
+	if *trustedSetup == "" {
+		err = ckzg4844.LoadTrustedSetupFromReader(mainnet.TrustedSetup(), 0)
+	} else {
+		err = ckzg4844.LoadTrustedSetupFile(*trustedSetup, 0)
+	}
+	if err != nil {
+		fatalf("failed to load trusted setup: %v", err)
+	}
+	defer ckzg4844.FreeTrustedSetup()
+
+	results, err := refvectors.RunDir(*testsDir)
+	if errors.Is(err, refvectors.ErrNotEmbedded) {
+		fatalf("missing -tests, and the reference tests are not embedded in this build")
+	}
+	if err != nil {
+		fatalf("failed to run reference tests: %v", err)
+	}
+	expected, err := fixtures.CommittedOutputs()
+	if err != nil {
+		fatalf("failed to read the expected outputs of the edge cases: %v", err)
+	}
+	if expected.Setup == ckzg4844.TrustedSetupFingerprint() {
+		results = append(results, edgeCaseResults(expected)...)
+	} else {
+		fmt.Fprintln(os.Stderr, "skipping the internal edge cases, their outputs are only known for the mainnet trusted setup")
+	}
+	report := buildReport(results, *specVersion)
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		fatalf("failed to encode report: %v", err)
+	}
+	data = append(data, '\n')
+	if *out == "-" {
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = os.WriteFile(*out, data, 0o644)
+	}
+	if err != nil {
+		fatalf("failed to write report: %v", err)
+	}
+	if !report.Passed {
+		// Exit without the deferred free, the process is ending anyway.
+		os.Exit(1)
+	}
+}
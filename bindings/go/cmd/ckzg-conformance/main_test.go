@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/internal/fixtures"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/mainnet"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/refvectors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	if err := ckzg4844.LoadTrustedSetupFromReader(mainnet.TrustedSetup(), 0); err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	ckzg4844.FreeTrustedSetup()
+	os.Exit(code)
+}
+
+func TestEdgeCaseResults(t *testing.T) {
+	expected, err := fixtures.CommittedOutputs()
+	require.NoError(t, err)
+	require.Equal(t, ckzg4844.Bytes32(ckzg4844.TrustedSetupFingerprint()), expected.Setup)
+	results := edgeCaseResults(expected)
+	require.Len(t, results, 6*len(expected.Fixtures))
+	for _, result := range results {
+		require.True(t, result.Passed(), "%s %s: %v", result.Function, result.Name, result.Err)
+	}
+
+	// An expected value which the bindings don't produce fails the cases
+	// which check it.
+	want := expected.Fixtures["zero-blob@v1"]
+	want.Proof[0] ^= 1
+	expected.Fixtures = map[string]fixtures.Expected{"zero-blob@v1": want}
+	var failed []string
+	for _, result := range edgeCaseResults(expected) {
+		if !result.Passed() {
+			failed = append(failed, result.Function)
+		}
+	}
+	require.Equal(t, []string{"compute_blob_kzg_proof", "verify_blob_kzg_proof"}, failed)
+}
+
+func TestBuildReport(t *testing.T) {
+	report := buildReport([]refvectors.Result{
+		{Function: "verify_blob_kzg_proof", Preset: "mainnet", Name: "a"},
+		{Function: "verify_blob_kzg_proof", Preset: "mainnet", Name: "b", Err: errors.New("mismatch")},
+		{Function: "blob_to_kzg_commitment", Preset: internalPreset, Name: "c"},
+	}, "v1.0.0")
+	require.False(t, report.Passed)
+	require.Equal(t, "v1.0.0", report.SpecVersion)
+	require.Equal(t, []refvectors.Summary{
+		{Function: "blob_to_kzg_commitment", Preset: internalPreset, Passed: 1},
+		{Function: "verify_blob_kzg_proof", Preset: "mainnet", Passed: 1, Failed: 1},
+	}, report.Summary)
+	require.Equal(t, []refvectors.Failure{{Function: "verify_blob_kzg_proof", Preset: "mainnet", Name: "b", Error: "mismatch"}}, report.Failures)
+}
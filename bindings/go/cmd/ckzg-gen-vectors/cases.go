@@ -0,0 +1,689 @@
+package main
+
+import (
+	"encoding/hex"
+	"math/rand"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/internal/fixtures"
+)
+
+// functions lists the spec functions, in the order vectors are generated.
+var functions = []string{
+	"blob_to_kzg_commitment",
+	"compute_kzg_proof",
+	"compute_blob_kzg_proof",
+	"verify_kzg_proof",
+	"verify_blob_kzg_proof",
+	"verify_blob_kzg_proof_batch",
+	"compute_cells_and_kzg_proofs",
+	"recover_cells_and_kzg_proofs",
+	"verify_cell_kzg_proof_batch",
+}
+
+var (
+	invalidFieldElements = []string{ckzg4844.BLSModulus.String(), "0x" + repeatHex("ff", 32), "0x" + repeatHex("00", 31)}
+	invalidPoints        = []string{"0x" + repeatHex("01", 48), "0x" + repeatHex("ff", 48), "0x" + repeatHex("c0", 47)}
+)
+
+type namedCase struct {
+	name string
+	testCase
+}
+
+type (
+	blobInput struct {
+		Blob string `yaml:"blob" json:"blob"`
+	}
+	computeKZGProofInput struct {
+		Blob string `yaml:"blob" json:"blob"`
+		Z    string `yaml:"z" json:"z"`
+	}
+	computeBlobKZGProofInput struct {
+		Blob       string `yaml:"blob" json:"blob"`
+		Commitment string `yaml:"commitment" json:"commitment"`
+	}
+	verifyKZGProofInput struct {
+		Commitment string `yaml:"commitment" json:"commitment"`
+		Z          string `yaml:"z" json:"z"`
+		Y          string `yaml:"y" json:"y"`
+		Proof      string `yaml:"proof" json:"proof"`
+	}
+	verifyBlobKZGProofInput struct {
+		Blob       string `yaml:"blob" json:"blob"`
+		Commitment string `yaml:"commitment" json:"commitment"`
+		Proof      string `yaml:"proof" json:"proof"`
+	}
+	verifyBlobKZGProofBatchInput struct {
+		Blobs       []string `yaml:"blobs" json:"blobs"`
+		Commitments []string `yaml:"commitments" json:"commitments"`
+		Proofs      []string `yaml:"proofs" json:"proofs"`
+	}
+	recoverCellsAndKZGProofsInput struct {
+		CellIndices []uint64 `yaml:"cell_indices" json:"cell_indices"`
+		Cells       []string `yaml:"cells" json:"cells"`
+	}
+	verifyCellKZGProofBatchInput struct {
+		Commitments []string `yaml:"commitments" json:"commitments"`
+		CellIndices []uint64 `yaml:"cell_indices" json:"cell_indices"`
+		Cells       []string `yaml:"cells" json:"cells"`
+		Proofs      []string `yaml:"proofs" json:"proofs"`
+	}
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Helper Functions
+///////////////////////////////////////////////////////////////////////////////
+
+func repeatHex(s string, n int) string {
+	out := ""
+	for i := 0; i < n; i++ {
+		out += s
+	}
+	return out
+}
+
+func toHex(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+func cellsToHex(cells []ckzg4844.Cell) []string {
+	out := make([]string, len(cells))
+	for i := range cells {
+		out[i] = toHex(cells[i][:])
+	}
+	return out
+}
+
+func proofsToHex(proofs []ckzg4844.KZGProof) []string {
+	out := make([]string, len(proofs))
+	for i := range proofs {
+		out[i] = toHex(proofs[i][:])
+	}
+	return out
+}
+
+func parseBlob(s string) (*ckzg4844.Blob, bool) {
+	blob := new(ckzg4844.Blob)
+	return blob, blob.UnmarshalText([]byte(s)) == nil
+}
+
+func parseBlobs(strs []string) ([]ckzg4844.Blob, bool) {
+	blobs := make([]ckzg4844.Blob, len(strs))
+	for i, s := range strs {
+		if blobs[i].UnmarshalText([]byte(s)) != nil {
+			return nil, false
+		}
+	}
+	return blobs, true
+}
+
+func parseBytes32(s string) (ckzg4844.Bytes32, bool) {
+	var b ckzg4844.Bytes32
+	return b, b.UnmarshalText([]byte(s)) == nil
+}
+
+func parseBytes48(s string) (ckzg4844.Bytes48, bool) {
+	var b ckzg4844.Bytes48
+	return b, b.UnmarshalText([]byte(s)) == nil
+}
+
+func parseBytes48s(strs []string) ([]ckzg4844.Bytes48, bool) {
+	out := make([]ckzg4844.Bytes48, len(strs))
+	for i, s := range strs {
+		if out[i].UnmarshalText([]byte(s)) != nil {
+			return nil, false
+		}
+	}
+	return out, true
+}
+
+func parseCells(strs []string) ([]ckzg4844.Cell, bool) {
+	cells := make([]ckzg4844.Cell, len(strs))
+	for i, s := range strs {
+		if cells[i].UnmarshalText([]byte(s)) != nil {
+			return nil, false
+		}
+	}
+	return cells, true
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Generator
+///////////////////////////////////////////////////////////////////////////////
+
+// generator derives the inputs for every test case from a single seed.
+type generator struct {
+	rand  *rand.Rand
+	count int
+}
+
+func newGenerator(seed int64, count int) *generator {
+	return &generator{rand: rand.New(rand.NewSource(seed)), count: count}
+}
+
+func (g *generator) blob() *ckzg4844.Blob {
+	return fixtures.RandomBlob(g.rand.Int63())
+}
+
+func (g *generator) fieldElement() string {
+	fieldElement := fixtures.RandomFieldElement(g.rand)
+	return toHex(fieldElement[:])
+}
+
+// validBlobs returns the hex encoded blobs used for valid cases.
+func (g *generator) validBlobs() []string {
+	blobs := []string{toHex(new(ckzg4844.Blob)[:])}
+	for i := 0; i < g.count; i++ {
+		blobs = append(blobs, toHex(g.blob()[:]))
+	}
+	return blobs
+}
+
+// invalidBlobs returns hex encoded blobs which should be rejected.
+func (g *generator) invalidBlobs() []string {
+	blob := g.blob()
+	var blobs []string
+	for _, fieldElement := range invalidFieldElements[:2] {
+		invalid := *blob
+		offset := g.rand.Intn(ckzg4844.FieldElementsPerBlob) * ckzg4844.BytesPerFieldElement
+		elementBytes, _ := hex.DecodeString(fieldElement[2:])
+		copy(invalid[offset:], elementBytes)
+		blobs = append(blobs, toHex(invalid[:]))
+	}
+	// A blob which is one byte too short.
+	blobs = append(blobs, toHex(blob[:ckzg4844.BytesPerBlob-1]))
+	return blobs
+}
+
+func (g *generator) commitmentAndProof(blob *ckzg4844.Blob) (ckzg4844.KZGCommitment, ckzg4844.KZGProof) {
+	commitment, err := ckzg4844.BlobToKZGCommitment(blob)
+	if err != nil {
+		panic(err)
+	}
+	proof, err := ckzg4844.ComputeBlobKZGProof(blob, ckzg4844.Bytes48(commitment))
+	if err != nil {
+		panic(err)
+	}
+	return commitment, proof
+}
+
+func (g *generator) cases(function string) []namedCase {
+	switch function {
+	case "blob_to_kzg_commitment":
+		return g.blobToKZGCommitmentCases()
+	case "compute_kzg_proof":
+		return g.computeKZGProofCases()
+	case "compute_blob_kzg_proof":
+		return g.computeBlobKZGProofCases()
+	case "verify_kzg_proof":
+		return g.verifyKZGProofCases()
+	case "verify_blob_kzg_proof":
+		return g.verifyBlobKZGProofCases()
+	case "verify_blob_kzg_proof_batch":
+		return g.verifyBlobKZGProofBatchCases()
+	case "compute_cells_and_kzg_proofs":
+		return g.computeCellsAndKZGProofsCases()
+	case "recover_cells_and_kzg_proofs":
+		return g.recoverCellsAndKZGProofsCases()
+	case "verify_cell_kzg_proof_batch":
+		return g.verifyCellKZGProofBatchCases()
+	}
+	panic("unknown function: " + function)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Cases
+///////////////////////////////////////////////////////////////////////////////
+
+func evalBlobToKZGCommitment(in blobInput) any {
+	blob, ok := parseBlob(in.Blob)
+	if !ok {
+		return nil
+	}
+	commitment, err := ckzg4844.BlobToKZGCommitment(blob)
+	if err != nil {
+		return nil
+	}
+	return toHex(commitment[:])
+}
+
+func (g *generator) blobToKZGCommitmentCases() []namedCase {
+	var cases []namedCase
+	add := func(name string, in blobInput) {
+		cases = append(cases, namedCase{name, testCase{in, evalBlobToKZGCommitment(in)}})
+	}
+	for _, blob := range g.validBlobs() {
+		add("valid_blob", blobInput{blob})
+	}
+	for _, blob := range g.invalidBlobs() {
+		add("invalid_blob", blobInput{blob})
+	}
+	return cases
+}
+
+func evalComputeKZGProof(in computeKZGProofInput) any {
+	blob, ok := parseBlob(in.Blob)
+	if !ok {
+		return nil
+	}
+	z, ok := parseBytes32(in.Z)
+	if !ok {
+		return nil
+	}
+	proof, y, err := ckzg4844.ComputeKZGProof(blob, z)
+	if err != nil {
+		return nil
+	}
+	return []string{toHex(proof[:]), toHex(y[:])}
+}
+
+func (g *generator) computeKZGProofCases() []namedCase {
+	var cases []namedCase
+	add := func(name string, in computeKZGProofInput) {
+		cases = append(cases, namedCase{name, testCase{in, evalComputeKZGProof(in)}})
+	}
+	for _, blob := range g.validBlobs() {
+		add("valid_blob", computeKZGProofInput{blob, g.fieldElement()})
+	}
+	for _, blob := range g.invalidBlobs() {
+		add("invalid_blob", computeKZGProofInput{blob, g.fieldElement()})
+	}
+	blob := toHex(g.blob()[:])
+	for _, z := range invalidFieldElements {
+		add("invalid_z", computeKZGProofInput{blob, z})
+	}
+	return cases
+}
+
+func evalComputeBlobKZGProof(in computeBlobKZGProofInput) any {
+	blob, ok := parseBlob(in.Blob)
+	if !ok {
+		return nil
+	}
+	commitment, ok := parseBytes48(in.Commitment)
+	if !ok {
+		return nil
+	}
+	proof, err := ckzg4844.ComputeBlobKZGProof(blob, commitment)
+	if err != nil {
+		return nil
+	}
+	return toHex(proof[:])
+}
+
+func (g *generator) computeBlobKZGProofCases() []namedCase {
+	var cases []namedCase
+	add := func(name string, in computeBlobKZGProofInput) {
+		cases = append(cases, namedCase{name, testCase{in, evalComputeBlobKZGProof(in)}})
+	}
+	for _, blobHex := range g.validBlobs() {
+		blob, _ := parseBlob(blobHex)
+		commitment, _ := g.commitmentAndProof(blob)
+		add("valid_blob", computeBlobKZGProofInput{blobHex, toHex(commitment[:])})
+	}
+	for _, blob := range g.invalidBlobs() {
+		add("invalid_blob", computeBlobKZGProofInput{blob, toHex(make([]byte, 48))})
+	}
+	blob := g.blob()
+	for _, commitment := range invalidPoints {
+		add("invalid_commitment", computeBlobKZGProofInput{toHex(blob[:]), commitment})
+	}
+	return cases
+}
+
+func evalVerifyKZGProof(in verifyKZGProofInput) any {
+	commitment, ok := parseBytes48(in.Commitment)
+	if !ok {
+		return nil
+	}
+	z, ok := parseBytes32(in.Z)
+	if !ok {
+		return nil
+	}
+	y, ok := parseBytes32(in.Y)
+	if !ok {
+		return nil
+	}
+	proof, ok := parseBytes48(in.Proof)
+	if !ok {
+		return nil
+	}
+	valid, err := ckzg4844.VerifyKZGProof(commitment, z, y, proof)
+	if err != nil {
+		return nil
+	}
+	return valid
+}
+
+func (g *generator) verifyKZGProofCases() []namedCase {
+	var cases []namedCase
+	add := func(name string, in verifyKZGProofInput) {
+		cases = append(cases, namedCase{name, testCase{in, evalVerifyKZGProof(in)}})
+	}
+	var correct verifyKZGProofInput
+	for _, blobHex := range g.validBlobs() {
+		blob, _ := parseBlob(blobHex)
+		commitment, _ := g.commitmentAndProof(blob)
+		z := fixtures.RandomFieldElement(g.rand)
+		proof, y, err := ckzg4844.ComputeKZGProof(blob, z)
+		if err != nil {
+			panic(err)
+		}
+		correct = verifyKZGProofInput{toHex(commitment[:]), toHex(z[:]), toHex(y[:]), toHex(proof[:])}
+		add("correct_proof", correct)
+
+		incorrect := correct
+		incorrect.Y = g.fieldElement()
+		add("incorrect_proof", incorrect)
+	}
+	for _, point := range invalidPoints {
+		invalid := correct
+		invalid.Commitment = point
+		add("invalid_commitment", invalid)
+		invalid = correct
+		invalid.Proof = point
+		add("invalid_proof", invalid)
+	}
+	for _, fieldElement := range invalidFieldElements {
+		invalid := correct
+		invalid.Z = fieldElement
+		add("invalid_z", invalid)
+		invalid = correct
+		invalid.Y = fieldElement
+		add("invalid_y", invalid)
+	}
+	return cases
+}
+
+func evalVerifyBlobKZGProof(in verifyBlobKZGProofInput) any {
+	blob, ok := parseBlob(in.Blob)
+	if !ok {
+		return nil
+	}
+	commitment, ok := parseBytes48(in.Commitment)
+	if !ok {
+		return nil
+	}
+	proof, ok := parseBytes48(in.Proof)
+	if !ok {
+		return nil
+	}
+	valid, err := ckzg4844.VerifyBlobKZGProof(blob, commitment, proof)
+	if err != nil {
+		return nil
+	}
+	return valid
+}
+
+func (g *generator) verifyBlobKZGProofCases() []namedCase {
+	var cases []namedCase
+	add := func(name string, in verifyBlobKZGProofInput) {
+		cases = append(cases, namedCase{name, testCase{in, evalVerifyBlobKZGProof(in)}})
+	}
+	var correct verifyBlobKZGProofInput
+	for _, blobHex := range g.validBlobs() {
+		blob, _ := parseBlob(blobHex)
+		commitment, proof := g.commitmentAndProof(blob)
+		correct = verifyBlobKZGProofInput{blobHex, toHex(commitment[:]), toHex(proof[:])}
+		add("correct_proof", correct)
+
+		_, otherProof := g.commitmentAndProof(g.blob())
+		incorrect := correct
+		incorrect.Proof = toHex(otherProof[:])
+		add("incorrect_proof", incorrect)
+	}
+	for _, blob := range g.invalidBlobs() {
+		invalid := correct
+		invalid.Blob = blob
+		add("invalid_blob", invalid)
+	}
+	for _, point := range invalidPoints {
+		invalid := correct
+		invalid.Commitment = point
+		add("invalid_commitment", invalid)
+		invalid = correct
+		invalid.Proof = point
+		add("invalid_proof", invalid)
+	}
+	return cases
+}
+
+func evalVerifyBlobKZGProofBatch(in verifyBlobKZGProofBatchInput) any {
+	blobs, ok := parseBlobs(in.Blobs)
+	if !ok {
+		return nil
+	}
+	commitments, ok := parseBytes48s(in.Commitments)
+	if !ok {
+		return nil
+	}
+	proofs, ok := parseBytes48s(in.Proofs)
+	if !ok {
+		return nil
+	}
+	valid, err := ckzg4844.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+	if err != nil {
+		return nil
+	}
+	return valid
+}
+
+func (g *generator) verifyBlobKZGProofBatchCases() []namedCase {
+	var cases []namedCase
+	add := func(name string, in verifyBlobKZGProofBatchInput) {
+		cases = append(cases, namedCase{name, testCase{in, evalVerifyBlobKZGProofBatch(in)}})
+	}
+	all := verifyBlobKZGProofBatchInput{Blobs: []string{}, Commitments: []string{}, Proofs: []string{}}
+	for _, blobHex := range g.validBlobs() {
+		blob, _ := parseBlob(blobHex)
+		commitment, proof := g.commitmentAndProof(blob)
+		all.Blobs = append(all.Blobs, blobHex)
+		all.Commitments = append(all.Commitments, toHex(commitment[:]))
+		all.Proofs = append(all.Proofs, toHex(proof[:]))
+	}
+	for i := 0; i <= len(all.Blobs); i++ {
+		add("valid", verifyBlobKZGProofBatchInput{all.Blobs[:i], all.Commitments[:i], all.Proofs[:i]})
+	}
+
+	last := len(all.Blobs) - 1
+	withProof := func(proof string) verifyBlobKZGProofBatchInput {
+		proofs := append(append([]string{}, all.Proofs[:last]...), proof)
+		return verifyBlobKZGProofBatchInput{all.Blobs, all.Commitments, proofs}
+	}
+	// The proof of another blob, so the batch is well formed but invalid.
+	add("incorrect_proof", withProof(all.Proofs[last-1]))
+	for _, point := range invalidPoints {
+		add("invalid_proof", withProof(point))
+	}
+	for _, blob := range g.invalidBlobs() {
+		blobs := append(append([]string{}, all.Blobs[:last]...), blob)
+		add("invalid_blob", verifyBlobKZGProofBatchInput{blobs, all.Commitments, all.Proofs})
+	}
+	add("blob_length_different", verifyBlobKZGProofBatchInput{all.Blobs[:last], all.Commitments, all.Proofs})
+	add("commitment_length_different", verifyBlobKZGProofBatchInput{all.Blobs, all.Commitments[:last], all.Proofs})
+	add("proof_length_different", verifyBlobKZGProofBatchInput{all.Blobs, all.Commitments, all.Proofs[:last]})
+	return cases
+}
+
+func evalComputeCellsAndKZGProofs(in blobInput) any {
+	blob, ok := parseBlob(in.Blob)
+	if !ok {
+		return nil
+	}
+	cells, proofs, err := ckzg4844.ComputeCellsAndKZGProofs(blob)
+	if err != nil {
+		return nil
+	}
+	return [][]string{cellsToHex(cells[:]), proofsToHex(proofs[:])}
+}
+
+func (g *generator) computeCellsAndKZGProofsCases() []namedCase {
+	var cases []namedCase
+	add := func(name string, in blobInput) {
+		cases = append(cases, namedCase{name, testCase{in, evalComputeCellsAndKZGProofs(in)}})
+	}
+	for _, blob := range g.validBlobs() {
+		add("valid", blobInput{blob})
+	}
+	for _, blob := range g.invalidBlobs() {
+		add("invalid_blob", blobInput{blob})
+	}
+	return cases
+}
+
+func evalRecoverCellsAndKZGProofs(in recoverCellsAndKZGProofsInput) any {
+	cells, ok := parseCells(in.Cells)
+	if !ok {
+		return nil
+	}
+	recoveredCells, recoveredProofs, err := ckzg4844.RecoverCellsAndKZGProofs(in.CellIndices, cells)
+	if err != nil {
+		return nil
+	}
+	return [][]string{cellsToHex(recoveredCells[:]), proofsToHex(recoveredProofs[:])}
+}
+
+func (g *generator) recoverCellsAndKZGProofsCases() []namedCase {
+	var cases []namedCase
+	add := func(name string, in recoverCellsAndKZGProofsInput) {
+		cases = append(cases, namedCase{name, testCase{in, evalRecoverCellsAndKZGProofs(in)}})
+	}
+	blob := g.blob()
+	cells, _, err := ckzg4844.ComputeCellsAndKZGProofs(blob)
+	if err != nil {
+		panic(err)
+	}
+	subset := func(cellIndices []uint64) recoverCellsAndKZGProofsInput {
+		in := recoverCellsAndKZGProofsInput{CellIndices: cellIndices, Cells: []string{}}
+		for _, cellIndex := range cellIndices {
+			in.Cells = append(in.Cells, toHex(cells[cellIndex][:]))
+		}
+		return in
+	}
+
+	for i := 0; i < g.count; i++ {
+		perm := g.rand.Perm(ckzg4844.CellsPerExtBlob)
+		numCells := ckzg4844.CellsPerExtBlob/2 + g.rand.Intn(ckzg4844.CellsPerExtBlob/2+1)
+		var cellIndices []uint64
+		for _, cellIndex := range perm[:numCells] {
+			cellIndices = append(cellIndices, uint64(cellIndex))
+		}
+		add("valid_random_subset", subset(cellIndices))
+	}
+	add("valid_half_missing_every_other_cell", subset(fixtures.HalfCellIndices()))
+
+	halfMinusOne := fixtures.HalfCellIndices()[1:]
+	add("invalid_more_than_half_missing", subset(halfMinusOne))
+	add("invalid_all_cells_are_missing", subset([]uint64{}))
+
+	duplicate := fixtures.HalfCellIndices()
+	duplicate[1] = duplicate[0]
+	add("invalid_duplicate_cell_index", subset(duplicate))
+
+	outOfRange := subset(fixtures.HalfCellIndices())
+	outOfRange.CellIndices = append([]uint64{}, outOfRange.CellIndices...)
+	outOfRange.CellIndices[0] = ckzg4844.CellsPerExtBlob
+	add("invalid_cell_index", outOfRange)
+
+	mismatch := subset(fixtures.HalfCellIndices())
+	mismatch.Cells = mismatch.Cells[1:]
+	add("invalid_more_cell_indices_than_cells", mismatch)
+	return cases
+}
+
+func evalVerifyCellKZGProofBatch(in verifyCellKZGProofBatchInput) any {
+	commitments, ok := parseBytes48s(in.Commitments)
+	if !ok {
+		return nil
+	}
+	cells, ok := parseCells(in.Cells)
+	if !ok {
+		return nil
+	}
+	proofs, ok := parseBytes48s(in.Proofs)
+	if !ok {
+		return nil
+	}
+	valid, err := ckzg4844.VerifyCellKZGProofBatch(commitments, in.CellIndices, cells, proofs)
+	if err != nil {
+		return nil
+	}
+	return valid
+}
+
+func (g *generator) verifyCellKZGProofBatchCases() []namedCase {
+	var cases []namedCase
+	add := func(name string, in verifyCellKZGProofBatchInput) {
+		cases = append(cases, namedCase{name, testCase{in, evalVerifyCellKZGProofBatch(in)}})
+	}
+
+	type row struct {
+		commitment string
+		cells      [ckzg4844.CellsPerExtBlob]ckzg4844.Cell
+		proofs     [ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof
+	}
+	rows := make([]row, 2)
+	for i := range rows {
+		blob := g.blob()
+		commitment, _ := g.commitmentAndProof(blob)
+		cells, proofs, err := ckzg4844.ComputeCellsAndKZGProofs(blob)
+		if err != nil {
+			panic(err)
+		}
+		rows[i] = row{toHex(commitment[:]), cells, proofs}
+	}
+	sample := func(numCells int) verifyCellKZGProofBatchInput {
+		in := verifyCellKZGProofBatchInput{[]string{}, []uint64{}, []string{}, []string{}}
+		for i := 0; i < numCells; i++ {
+			r := rows[g.rand.Intn(len(rows))]
+			cellIndex := g.rand.Intn(ckzg4844.CellsPerExtBlob)
+			in.Commitments = append(in.Commitments, r.commitment)
+			in.CellIndices = append(in.CellIndices, uint64(cellIndex))
+			in.Cells = append(in.Cells, toHex(r.cells[cellIndex][:]))
+			in.Proofs = append(in.Proofs, toHex(r.proofs[cellIndex][:]))
+		}
+		return in
+	}
+	// clone returns a copy of in which can be modified without affecting in.
+	clone := func(in verifyCellKZGProofBatchInput) verifyCellKZGProofBatchInput {
+		return verifyCellKZGProofBatchInput{
+			append([]string{}, in.Commitments...),
+			append([]uint64{}, in.CellIndices...),
+			append([]string{}, in.Cells...),
+			append([]string{}, in.Proofs...),
+		}
+	}
+
+	add("valid_zero_cells", sample(0))
+	for i := 0; i < g.count; i++ {
+		add("valid", sample(1+g.rand.Intn(16)))
+	}
+
+	base := sample(4)
+	incorrect := clone(base)
+	incorrect.Cells[0] = toHex(rows[0].cells[(base.CellIndices[0]+1)%ckzg4844.CellsPerExtBlob][:])
+	add("incorrect_cell", incorrect)
+	incorrect = clone(base)
+	incorrect.Proofs[0] = toHex(rows[0].proofs[(base.CellIndices[0]+1)%ckzg4844.CellsPerExtBlob][:])
+	add("incorrect_proof", incorrect)
+
+	for _, point := range invalidPoints {
+		invalid := clone(base)
+		invalid.Commitments[0] = point
+		add("invalid_commitment", invalid)
+		invalid = clone(base)
+		invalid.Proofs[0] = point
+		add("invalid_proof", invalid)
+	}
+	invalid := clone(base)
+	invalid.CellIndices[0] = ckzg4844.CellsPerExtBlob
+	add("invalid_cell_index", invalid)
+	invalid = clone(base)
+	invalid.Cells = invalid.Cells[1:]
+	add("invalid_missing_cell", invalid)
+	invalid = clone(base)
+	invalid.Proofs = invalid.Proofs[1:]
+	add("invalid_missing_proof", invalid)
+	return cases
+}
@@ -0,0 +1,136 @@
+// Command ckzg-gen-vectors generates test vectors in the same format as the
+// consensus-spec reference tests, using these bindings to compute the expected
+// outputs. The vectors are derived from a seed, so running the command twice
+// with the same seed produces identical files.
+//
+// Usage:
+//
+//	ckzg-gen-vectors [-seed N] [-count N] [-format yaml|json] [-out DIR] [-trusted-setup FILE]
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/mainnet"
+	"gopkg.in/yaml.v3"
+)
+
+// testCase is a single test vector. A nil Output means the inputs are invalid.
+type testCase struct {
+	Input  any `yaml:"input" json:"input"`
+	Output any `yaml:"output" json:"output"`
+}
+
+// quoteHex single-quotes all hex strings, like the reference tests do, so
+// YAML parsers don't mistake them for integers.
+func quoteHex(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && strings.HasPrefix(node.Value, "0x") {
+		node.Style = yaml.SingleQuotedStyle
+	}
+	for _, child := range node.Content {
+		quoteHex(child)
+	}
+}
+
+func marshalYAML(tc testCase) ([]byte, error) {
+	var node yaml.Node
+	if err := node.Encode(tc); err != nil {
+		return nil, err
+	}
+	quoteHex(&node)
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeCase writes a test case to dir/function/kzg-mainnet/function_case_name_hash.
+func writeCase(dir, format, function, name string, tc testCase) error {
+	inputBytes, err := json.Marshal(tc.Input)
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(inputBytes)
+	caseName := fmt.Sprintf("%s_case_%s_%x", function, name, hash[:8])
+	caseDir := filepath.Join(dir, function, "kzg-mainnet", caseName)
+	if err := os.MkdirAll(caseDir, 0o755); err != nil {
+		return err
+	}
+
+	var data []byte
+	switch format {
+	case "yaml":
+		data, err = marshalYAML(tc)
+	case "json":
+		data, err = json.MarshalIndent(tc, "", "  ")
+		data = append(data, '\n')
+	default:
+		return fmt.Errorf("unknown format: %s", format)
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(caseDir, "data."+format), data, 0o644)
+}
+
+// generate writes the test vectors for every function to dir and returns how
+// many it wrote.
+func generate(dir, format string, seed int64, count int) (int, error) {
+	// The batch cases need at least two blobs, the zero blob and a random one.
+	if count < 1 {
+		return 0, fmt.Errorf("-count must be at least 1")
+	}
+	g := newGenerator(seed, count)
+	total := 0
+	for _, function := range functions {
+		for _, c := range g.cases(function) {
+			if err := writeCase(dir, format, function, c.name, c.testCase); err != nil {
+				return total, fmt.Errorf("failed to write %s case: %w", function, err)
+			}
+			total++
+		}
+	}
+	return total, nil
+}
+
+func main() {
+	seed := flag.Int64("seed", 0, "seed used to derive the random inputs")
+	count := flag.Int("count", 4, "number of random valid cases per function")
+	format := flag.String("format", "yaml", "output format: yaml or json")
+	out := flag.String("out", "vectors", "output directory")
+	trustedSetup := flag.String("trusted-setup", "", "path to the trusted setup file (the mainnet setup if empty)")
+	flag.Parse()
+
+	var err error
+	if *trustedSetup == "" {
+		err = ckzg4844.LoadTrustedSetupFromReader(mainnet.TrustedSetup(), 0)
+	} else {
+		err = ckzg4844.LoadTrustedSetupFile(*trustedSetup, 0)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load trusted setup: %v\n", err)
+		os.Exit(1)
+	}
+	defer ckzg4844.FreeTrustedSetup()
+
+	total, err := generate(*out, *format, *seed, *count)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("wrote %d test vectors to %s\n", total, *out)
+}
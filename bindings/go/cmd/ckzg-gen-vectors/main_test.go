@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/ckzgtest"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/refvectors"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerate(t *testing.T) {
+	ckzgtest.LoadTrustedSetup(t)
+	dir := t.TempDir()
+	total, err := generate(dir, "yaml", 1, 1)
+	require.NoError(t, err)
+
+	// The generated vectors pass against the bindings.
+	results, err := refvectors.Run(os.DirFS(dir))
+	require.NoError(t, err)
+	require.Len(t, results, total)
+	for _, result := range results {
+		require.NoError(t, result.Err, "%s/%s", result.Function, result.Name)
+	}
+
+	// The name of each case matches its expected output.
+	paths, err := filepath.Glob(filepath.Join(dir, "*", "*", "*", "data.yaml"))
+	require.NoError(t, err)
+	require.Len(t, paths, total)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var tc testCase
+		require.NoError(t, yaml.Unmarshal(data, &tc))
+		name := filepath.Base(filepath.Dir(path))
+		switch {
+		case strings.Contains(name, "_case_incorrect_"):
+			require.Equal(t, false, tc.Output, name)
+		case strings.Contains(name, "_case_invalid_"), strings.Contains(name, "_length_different_"):
+			require.Nil(t, tc.Output, name)
+		case strings.Contains(name, "_case_correct_"), strings.Contains(name, "_case_valid"):
+			require.NotNil(t, tc.Output, name)
+			require.NotEqual(t, false, tc.Output, name)
+		default:
+			t.Fatalf("unexpected case name %s", name)
+		}
+	}
+}
+
+func TestGenerateCount(t *testing.T) {
+	_, err := generate(t.TempDir(), "yaml", 1, 0)
+	require.Error(t, err)
+}
@@ -0,0 +1,256 @@
+// Command ckzg-prover computes commitments and proofs for blobs, so proving can
+// run isolated in its own process.
+//
+// In directory mode, it polls an input directory for *.blob files and, for
+// each one, writes the following files to the output directory:
+//
+//	<name>.commitment   the blob's commitment
+//	<name>.proof        the blob proof
+//	<name>.cell_proofs  the cell proofs, one per line
+//
+// Each value is 0x-prefixed hex. Blob files may contain the raw blob bytes or
+// their hex encoding. Writers should create blob files under another name and
+// rename them into place, so partially written files are never picked up.
+//
+// In stdin mode (-in -), a single blob is read from stdin and the results are
+// written to stdout as JSON.
+//
+// The mainnet trusted setup is embedded; -trusted-setup loads another one from
+// a file.
+//
+// Usage:
+//
+//	ckzg-prover -in DIR -out DIR [-interval D] [-once] [-delete] [-trusted-setup FILE]
+//	ckzg-prover -in - < blob
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/mainnet"
+)
+
+const blobExt = ".blob"
+
+type result struct {
+	Commitment ckzg4844.KZGCommitment
+	Proof      ckzg4844.KZGProof
+	CellProofs [ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof
+}
+
+// parseBlob accepts either raw blob bytes or the hex encoding of a blob.
+func parseBlob(data []byte) (*ckzg4844.Blob, error) {
+	blob := new(ckzg4844.Blob)
+	if len(data) == ckzg4844.BytesPerBlob {
+		copy(blob[:], data)
+		return blob, nil
+	}
+	if err := blob.UnmarshalText(bytes.TrimSpace(data)); err != nil {
+		return nil, fmt.Errorf("expected %d raw bytes or hex encoded blob: %w", ckzg4844.BytesPerBlob, err)
+	}
+	return blob, nil
+}
+
+func prove(blob *ckzg4844.Blob) (*result, error) {
+	var r result
+	var err error
+	r.Commitment, err = ckzg4844.BlobToKZGCommitment(blob)
+	if err != nil {
+		return nil, err
+	}
+	r.Proof, err = ckzg4844.ComputeBlobKZGProof(blob, ckzg4844.Bytes48(r.Commitment))
+	if err != nil {
+		return nil, err
+	}
+	if err := ckzg4844.ComputeCellsAndKZGProofsInto(nil, &r.CellProofs, blob); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func toHex(b []byte) string {
+	return fmt.Sprintf("0x%x", b)
+}
+
+func (r *result) cellProofsHex() []string {
+	out := make([]string, len(r.CellProofs))
+	for i := range r.CellProofs {
+		out[i] = toHex(r.CellProofs[i][:])
+	}
+	return out
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place,
+// so consumers never see partially written outputs.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func writeResult(outDir, name string, r *result) error {
+	files := []struct {
+		ext     string
+		content string
+	}{
+		// The commitment is written last, so its presence means all outputs are complete.
+		{".proof", toHex(r.Proof[:]) + "\n"},
+		{".cell_proofs", strings.Join(r.cellProofsHex(), "\n") + "\n"},
+		{".commitment", toHex(r.Commitment[:]) + "\n"},
+	}
+	for _, file := range files {
+		if err := writeFileAtomic(filepath.Join(outDir, name+file.ext), []byte(file.content)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// processFile proves a single blob file and writes its outputs.
+func processFile(path, outDir string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	blob, err := parseBlob(data)
+	if err != nil {
+		return err
+	}
+	r, err := prove(blob)
+	if err != nil {
+		return err
+	}
+	return writeResult(outDir, strings.TrimSuffix(filepath.Base(path), blobExt), r)
+}
+
+/*
+processDir proves every blob file in inDir which hasn't been proven yet. done
+maps the names of the blob files which were already processed to their
+modification times; entries of files which are no longer in inDir are
+dropped. With deleteInputs, blob files which were proven successfully are
+deleted; the others stay in done, so they are skipped until they change.
+*/
+func processDir(inDir, outDir string, done map[string]time.Time, deleteInputs bool) error {
+	entries, err := os.ReadDir(inDir)
+	if err != nil {
+		return err
+	}
+	present := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), blobExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		present[entry.Name()] = true
+		if modTime, ok := done[entry.Name()]; ok && modTime.Equal(info.ModTime()) {
+			continue
+		}
+		path := filepath.Join(inDir, entry.Name())
+		if err := processFile(path, outDir); err != nil {
+			// A bad input shouldn't stop the daemon; skip it until it changes.
+			log.Printf("failed to prove %s: %v", path, err)
+			done[entry.Name()] = info.ModTime()
+			continue
+		}
+		log.Printf("proved %s", path)
+		if deleteInputs {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			delete(present, entry.Name())
+			continue
+		}
+		done[entry.Name()] = info.ModTime()
+	}
+	for name := range done {
+		if !present[name] {
+			delete(done, name)
+		}
+	}
+	return nil
+}
+
+func runStdin() error {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return err
+	}
+	blob, err := parseBlob(data)
+	if err != nil {
+		return err
+	}
+	r, err := prove(blob)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Commitment string   `json:"commitment"`
+		Proof      string   `json:"proof"`
+		CellProofs []string `json:"cell_proofs"`
+	}{toHex(r.Commitment[:]), toHex(r.Proof[:]), r.cellProofsHex()})
+}
+
+func main() {
+	in := flag.String("in", "-", "input directory to watch for *.blob files, or - to read one blob from stdin")
+	out := flag.String("out", ".", "output directory")
+	interval := flag.Duration("interval", time.Second, "how often to poll the input directory")
+	once := flag.Bool("once", false, "process the blobs currently in the input directory and exit")
+	deleteInputs := flag.Bool("delete", false, "delete blob files once they are proven successfully")
+	trustedSetup := flag.String("trusted-setup", "", "path to the trusted setup file (the mainnet setup if empty)")
+	precompute := flag.Uint("precompute", 8, "precompute level for the trusted setup, which speeds up cell proofs")
+	flag.Parse()
+
+	var err error
+	if *trustedSetup == "" {
+		err = ckzg4844.LoadTrustedSetupFromReader(mainnet.TrustedSetup(), *precompute)
+	} else {
+		err = ckzg4844.LoadTrustedSetupFile(*trustedSetup, *precompute)
+	}
+	if err != nil {
+		log.Fatalf("failed to load trusted setup: %v", err)
+	}
+	defer ckzg4844.FreeTrustedSetup()
+
+	if *in == "-" {
+		if err := runStdin(); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		log.Fatal(err)
+	}
+	done := map[string]time.Time{}
+	for {
+		if err := processDir(*in, *out, done, *deleteInputs); err != nil {
+			log.Fatal(err)
+		}
+		if *once {
+			return
+		}
+		time.Sleep(*interval)
+	}
+}
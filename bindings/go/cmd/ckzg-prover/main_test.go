@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/ckzgtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessDir(t *testing.T) {
+	ckzgtest.LoadTrustedSetup(t)
+	inDir, outDir := t.TempDir(), t.TempDir()
+	blob := ckzgtest.RandomBlob(1)
+	require.NoError(t, os.WriteFile(filepath.Join(inDir, "good.blob"), blob[:], 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(inDir, "bad.blob"), []byte("not a blob"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(inDir, "other.txt"), nil, 0o644))
+
+	done := map[string]time.Time{}
+	require.NoError(t, processDir(inDir, outDir, done, true))
+
+	// Only the blob which was proven is deleted.
+	_, err := os.Stat(filepath.Join(inDir, "good.blob"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+	_, err = os.Stat(filepath.Join(inDir, "bad.blob"))
+	require.NoError(t, err)
+	require.Len(t, done, 1)
+	require.Contains(t, done, "bad.blob")
+
+	commitment, err := ckzg4844.BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(outDir, "good.commitment"))
+	require.NoError(t, err)
+	require.Equal(t, toHex(commitment[:])+"\n", string(data))
+	_, err = os.Stat(filepath.Join(outDir, "bad.commitment"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	// Files which disappear are forgotten.
+	require.NoError(t, os.Remove(filepath.Join(inDir, "bad.blob")))
+	require.NoError(t, processDir(inDir, outDir, done, true))
+	require.Empty(t, done)
+
+	// Without -delete, proven blobs are remembered and kept.
+	require.NoError(t, os.WriteFile(filepath.Join(inDir, "kept.blob"), blob[:], 0o644))
+	require.NoError(t, processDir(inDir, outDir, done, false))
+	require.Contains(t, done, "kept.blob")
+	_, err = os.Stat(filepath.Join(inDir, "kept.blob"))
+	require.NoError(t, err)
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	blst "github.com/supranational/blst/bindings/go"
+)
+
+const (
+	bytesPerG1 = 48
+	bytesPerG2 = 96
+
+	g1MonomialFile = "g1_monomial_bytes.bin"
+	g1LagrangeFile = "g1_lagrange_bytes.bin"
+	g2MonomialFile = "g2_monomial_bytes.bin"
+)
+
+var (
+	// blsModulus is the order of the BLS12-381 scalar field.
+	blsModulus = new(big.Int).SetBytes(ckzg4844.BLSModulus[:])
+	// primitiveRoot is the generator used to derive the roots of unity.
+	primitiveRoot = big.NewInt(7)
+)
+
+// setup holds a trusted setup as concatenated compressed points.
+type setup struct {
+	G1Monomial []byte
+	G1Lagrange []byte
+	G2Monomial []byte
+}
+
+func (s *setup) numG1() int { return len(s.G1Lagrange) / bytesPerG1 }
+func (s *setup) numG2() int { return len(s.G2Monomial) / bytesPerG2 }
+
+// check verifies that the point counts are consistent.
+func (s *setup) check() error {
+	if len(s.G1Monomial)%bytesPerG1 != 0 || len(s.G1Lagrange)%bytesPerG1 != 0 || len(s.G2Monomial)%bytesPerG2 != 0 {
+		return fmt.Errorf("points have the wrong length")
+	}
+	if len(s.G1Monomial) != len(s.G1Lagrange) {
+		return fmt.Errorf("%d g1 monomial points but %d g1 lagrange points", len(s.G1Monomial)/bytesPerG1, s.numG1())
+	}
+	return nil
+}
+
+func splitPoints(data []byte, size int) [][]byte {
+	var points [][]byte
+	for i := 0; i+size <= len(data); i += size {
+		points = append(points, data[i:i+size])
+	}
+	return points
+}
+
+func decodePoint(s string, size int) ([]byte, error) {
+	point, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(s), "0x"))
+	if err != nil {
+		return nil, err
+	}
+	if len(point) != size {
+		return nil, fmt.Errorf("expected %d byte point, got %d bytes", size, len(point))
+	}
+	return point, nil
+}
+
+// decodePoints decodes hex encoded points into concatenated points.
+func decodePoints(name string, points []string, size int) ([]byte, error) {
+	out := make([]byte, 0, len(points)*size)
+	for i, p := range points {
+		point, err := decodePoint(p, size)
+		if err != nil {
+			return nil, fmt.Errorf("%s point %d: %w", name, i, err)
+		}
+		out = append(out, point...)
+	}
+	return out, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Text Format
+///////////////////////////////////////////////////////////////////////////////
+
+// readText reads the format used by load_trusted_setup_file.
+func readText(r io.Reader) (*setup, error) {
+	var s setup
+	var err error
+	s.G1Monomial, s.G1Lagrange, s.G2Monomial, err = ckzg4844.ParseTrustedSetup(r)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func writeText(w io.Writer, s *setup) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%d\n%d\n", s.numG1(), s.numG2())
+	for _, points := range [][][]byte{
+		splitPoints(s.G1Lagrange, bytesPerG1),
+		splitPoints(s.G2Monomial, bytesPerG2),
+		splitPoints(s.G1Monomial, bytesPerG1),
+	} {
+		for _, point := range points {
+			fmt.Fprintf(bw, "%x\n", point)
+		}
+	}
+	return bw.Flush()
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// JSON Format
+///////////////////////////////////////////////////////////////////////////////
+
+// jsonSetup is the format of the trusted setups in the consensus specs.
+type jsonSetup struct {
+	G1Monomial []string `json:"g1_monomial"`
+	G1Lagrange []string `json:"g1_lagrange"`
+	G2Monomial []string `json:"g2_monomial"`
+}
+
+func readJSON(r io.Reader) (*setup, error) {
+	var js jsonSetup
+	if err := json.NewDecoder(r).Decode(&js); err != nil {
+		return nil, err
+	}
+	var s setup
+	var err error
+	if s.G1Monomial, err = decodePoints("g1_monomial", js.G1Monomial, bytesPerG1); err != nil {
+		return nil, err
+	}
+	if s.G1Lagrange, err = decodePoints("g1_lagrange", js.G1Lagrange, bytesPerG1); err != nil {
+		return nil, err
+	}
+	if s.G2Monomial, err = decodePoints("g2_monomial", js.G2Monomial, bytesPerG2); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func writeJSON(w io.Writer, s *setup) error {
+	encodeAll := func(data []byte, size int) []string {
+		var out []string
+		for _, point := range splitPoints(data, size) {
+			out = append(out, fmt.Sprintf("0x%x", point))
+		}
+		return out
+	}
+	js := jsonSetup{
+		G1Monomial: encodeAll(s.G1Monomial, bytesPerG1),
+		G1Lagrange: encodeAll(s.G1Lagrange, bytesPerG1),
+		G2Monomial: encodeAll(s.G2Monomial, bytesPerG2),
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(js)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Ceremony Transcript Format
+///////////////////////////////////////////////////////////////////////////////
+
+// transcript is the transcript of the KZG ceremony, which holds the powers of
+// tau of one setup per size. Only the fields needed for a setup are decoded;
+// the witness of the contributions isn't checked.
+type transcript struct {
+	Transcripts []struct {
+		NumG1Powers int `json:"numG1Powers"`
+		NumG2Powers int `json:"numG2Powers"`
+		PowersOfTau struct {
+			G1Powers []string `json:"G1Powers"`
+			G2Powers []string `json:"G2Powers"`
+		} `json:"powersOfTau"`
+	} `json:"transcripts"`
+}
+
+// readTranscript reads the setup with FieldElementsPerBlob G1 powers from a
+// ceremony transcript. The transcript only has the monomial points, so the
+// Lagrange points are computed from them.
+func readTranscript(r io.Reader) (*setup, error) {
+	var t transcript
+	if err := json.NewDecoder(r).Decode(&t); err != nil {
+		return nil, err
+	}
+	for _, powers := range t.Transcripts {
+		if powers.NumG1Powers != ckzg4844.FieldElementsPerBlob {
+			continue
+		}
+		if len(powers.PowersOfTau.G1Powers) != powers.NumG1Powers || len(powers.PowersOfTau.G2Powers) != powers.NumG2Powers {
+			return nil, fmt.Errorf("transcript with %d g1 powers has the wrong number of powers", powers.NumG1Powers)
+		}
+		var s setup
+		var err error
+		if s.G1Monomial, err = decodePoints("G1Powers", powers.PowersOfTau.G1Powers, bytesPerG1); err != nil {
+			return nil, err
+		}
+		if s.G2Monomial, err = decodePoints("G2Powers", powers.PowersOfTau.G2Powers, bytesPerG2); err != nil {
+			return nil, err
+		}
+		if s.G1Lagrange, err = lagrangePoints(s.G1Monomial); err != nil {
+			return nil, err
+		}
+		return &s, nil
+	}
+	return nil, fmt.Errorf("no transcript with %d g1 powers", ckzg4844.FieldElementsPerBlob)
+}
+
+// lagrangePoints computes the Lagrange points over the roots of unity, in
+// their natural order, from the monomial points. The i-th Lagrange point is
+// the inverse DFT of the monomial points at i.
+func lagrangePoints(monomial []byte) ([]byte, error) {
+	points := make([]*blst.P1, 0, len(monomial)/bytesPerG1)
+	for i, data := range splitPoints(monomial, bytesPerG1) {
+		affine := new(blst.P1Affine).Uncompress(data)
+		if affine == nil {
+			return nil, fmt.Errorf("G1Powers point %d: invalid point", i)
+		}
+		var point blst.P1
+		point.FromAffine(affine)
+		points = append(points, &point)
+	}
+
+	n := big.NewInt(int64(len(points)))
+	exp := new(big.Int).Sub(blsModulus, big.NewInt(1))
+	exp.Div(exp, n)
+	omega := new(big.Int).Exp(primitiveRoot, exp, blsModulus)
+	inverseOmega := new(big.Int).ModInverse(omega, blsModulus)
+	inverseN := scalarBytes(new(big.Int).ModInverse(n, blsModulus))
+
+	lagrange := make([]byte, 0, len(monomial))
+	for _, point := range fftG1(points, inverseOmega) {
+		lagrange = append(lagrange, point.Mult(inverseN).Compress()...)
+	}
+	return lagrange, nil
+}
+
+// fftG1 returns the DFT of points for the root of unity omega, whose order is
+// the number of points: the i-th output is the sum of points[j] * omega^(i*j).
+func fftG1(points []*blst.P1, omega *big.Int) []*blst.P1 {
+	n := len(points)
+	if n == 1 {
+		return points
+	}
+	even := make([]*blst.P1, n/2)
+	odd := make([]*blst.P1, n/2)
+	for i := range even {
+		even[i], odd[i] = points[2*i], points[2*i+1]
+	}
+	omegaSquared := new(big.Int).Mul(omega, omega)
+	omegaSquared.Mod(omegaSquared, blsModulus)
+	even, odd = fftG1(even, omegaSquared), fftG1(odd, omegaSquared)
+
+	out := make([]*blst.P1, n)
+	power := big.NewInt(1)
+	for i := range even {
+		term := odd[i].Mult(scalarBytes(power))
+		out[i] = even[i].Add(term)
+		out[i+n/2] = even[i].Sub(term)
+		power.Mul(power, omega).Mod(power, blsModulus)
+	}
+	return out
+}
+
+// scalarBytes converts a field element into the little-endian form blst
+// expects.
+func scalarBytes(x *big.Int) []byte {
+	out := make([]byte, 32)
+	x.FillBytes(out)
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Raw Format
+///////////////////////////////////////////////////////////////////////////////
+
+// readRaw reads a directory with one file of concatenated points per group,
+// which is the form LoadTrustedSetup takes.
+func readRaw(dir string) (*setup, error) {
+	var s setup
+	var err error
+	if s.G1Monomial, err = os.ReadFile(filepath.Join(dir, g1MonomialFile)); err != nil {
+		return nil, err
+	}
+	if s.G1Lagrange, err = os.ReadFile(filepath.Join(dir, g1LagrangeFile)); err != nil {
+		return nil, err
+	}
+	if s.G2Monomial, err = os.ReadFile(filepath.Join(dir, g2MonomialFile)); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func writeRaw(dir string, s *setup) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for name, data := range map[string][]byte{
+		g1MonomialFile: s.G1Monomial,
+		g1LagrangeFile: s.G1Lagrange,
+		g2MonomialFile: s.G2Monomial,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// textBytes returns the text encoding of s, which is used for checksums.
+func textBytes(s *setup) []byte {
+	var buf bytes.Buffer
+	if err := writeText(&buf, s); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
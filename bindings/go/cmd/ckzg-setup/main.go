@@ -0,0 +1,142 @@
+// Command ckzg-setup converts trusted setups between the text format read by
+// LoadTrustedSetupFile, the JSON format used by the consensus specs (with
+// g1_monomial, g1_lagrange and g2_monomial), and raw byte dumps (a directory
+// with g1_monomial_bytes.bin, g1_lagrange_bytes.bin, and
+// g2_monomial_bytes.bin) as taken by LoadTrustedSetup. It also reads the
+// transcript of the KZG ceremony, computing the Lagrange points from its powers
+// of tau. The setup is validated by loading it, and SHA-256 checksums are
+// printed so operators can compare setups regardless of their format.
+//
+// Usage:
+//
+//	ckzg-setup -in PATH [-from FORMAT] [-out PATH -to FORMAT] [-no-validate]
+//
+// Formats are text, json, raw, and transcript, which can only be read. If
+// -from is omitted, it is inferred from the input path, and transcript.json
+// is taken to be a ceremony transcript.
+package main
+
+import (
+	"crypto/sha256"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+)
+
+// inferFormat guesses the format of the setup at path.
+func inferFormat(path string) string {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return "raw"
+	}
+	if filepath.Base(path) == "transcript.json" {
+		return "transcript"
+	}
+	if filepath.Ext(path) == ".json" {
+		return "json"
+	}
+	return "text"
+}
+
+func read(path, format string) (*setup, error) {
+	if format == "raw" {
+		return readRaw(path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	switch format {
+	case "text":
+		return readText(f)
+	case "json":
+		return readJSON(f)
+	case "transcript":
+		return readTranscript(f)
+	}
+	return nil, fmt.Errorf("unknown format: %s", format)
+}
+
+func write(path, format string, s *setup) error {
+	switch format {
+	case "raw":
+		return writeRaw(path, s)
+	case "transcript":
+		return fmt.Errorf("the transcript format can only be read")
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case "text":
+		err = writeText(f, s)
+	case "json":
+		err = writeJSON(f, s)
+	default:
+		err = fmt.Errorf("unknown format: %s", format)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// validate loads the setup, which checks that every point is valid and that
+// the Lagrange points are actually in Lagrange form.
+func validate(s *setup) error {
+	if err := ckzg4844.LoadTrustedSetup(s.G1Monomial, s.G1Lagrange, s.G2Monomial, 0); err != nil {
+		return err
+	}
+	ckzg4844.FreeTrustedSetup()
+	return nil
+}
+
+func fatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
+
+func main() {
+	in := flag.String("in", "", "input trusted setup (a directory for the raw format)")
+	from := flag.String("from", "", "input format: text, json, raw, or transcript (inferred if empty)")
+	out := flag.String("out", "", "output path (a directory for the raw format)")
+	to := flag.String("to", "text", "output format: text, json, or raw")
+	noValidate := flag.Bool("no-validate", false, "skip loading the setup to validate it")
+	flag.Parse()
+
+	if *in == "" {
+		fatalf("missing -in")
+	}
+	if *from == "" {
+		*from = inferFormat(*in)
+	}
+	s, err := read(*in, *from)
+	if err != nil {
+		fatalf("failed to read %s setup: %v", *from, err)
+	}
+	if err := s.check(); err != nil {
+		fatalf("invalid setup: %v", err)
+	}
+	if !*noValidate {
+		if err := validate(s); err != nil {
+			fatalf("invalid setup: %v", err)
+		}
+	}
+
+	fmt.Printf("g1 points:   %d\n", s.numG1())
+	fmt.Printf("g2 points:   %d\n", s.numG2())
+	fmt.Printf("sha256:      %x (text format)\n", sha256.Sum256(textBytes(s)))
+	fmt.Printf("g1 monomial: %x\n", sha256.Sum256(s.G1Monomial))
+	fmt.Printf("g1 lagrange: %x\n", sha256.Sum256(s.G1Lagrange))
+	fmt.Printf("g2 monomial: %x\n", sha256.Sum256(s.G2Monomial))
+
+	if *out != "" {
+		if err := write(*out, *to, s); err != nil {
+			fatalf("failed to write %s setup: %v", *to, err)
+		}
+	}
+}
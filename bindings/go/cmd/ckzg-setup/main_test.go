@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const trustedSetupFile = "../../../../src/trusted_setup.txt"
+
+// TestRoundTrip converts the mainnet setup from text to JSON to raw and back to
+// text, which must give the original file.
+func TestRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "trusted_setup.json")
+	rawPath := filepath.Join(dir, "raw")
+	textPath := filepath.Join(dir, "trusted_setup.txt")
+
+	s, err := read(trustedSetupFile, inferFormat(trustedSetupFile))
+	require.NoError(t, err)
+	require.NoError(t, validate(s))
+	require.NoError(t, write(jsonPath, "json", s))
+
+	s, err = read(jsonPath, inferFormat(jsonPath))
+	require.NoError(t, err)
+	require.NoError(t, write(rawPath, "raw", s))
+
+	s, err = read(rawPath, inferFormat(rawPath))
+	require.NoError(t, err)
+	require.NoError(t, write(textPath, "text", s))
+
+	expected, err := os.ReadFile(trustedSetupFile)
+	require.NoError(t, err)
+	actual, err := os.ReadFile(textPath)
+	require.NoError(t, err)
+	require.True(t, bytes.Equal(expected, actual), "the round trip changed the setup")
+}
+
+// TestTranscript reads a ceremony transcript with the powers of tau of the
+// mainnet setup, whose computed Lagrange points must be those of the setup.
+func TestTranscript(t *testing.T) {
+	expected, err := read(trustedSetupFile, "text")
+	require.NoError(t, err)
+
+	encode := func(data []byte, size int) []string {
+		var out []string
+		for _, point := range splitPoints(data, size) {
+			out = append(out, fmt.Sprintf("0x%x", point))
+		}
+		return out
+	}
+	type powersOfTau struct {
+		G1Powers []string `json:"G1Powers"`
+		G2Powers []string `json:"G2Powers"`
+	}
+	type powers struct {
+		NumG1Powers int         `json:"numG1Powers"`
+		NumG2Powers int         `json:"numG2Powers"`
+		PowersOfTau powersOfTau `json:"powersOfTau"`
+	}
+	g1Powers := encode(expected.G1Monomial, bytesPerG1)
+	g2Powers := encode(expected.G2Monomial, bytesPerG2)
+	data, err := json.Marshal(map[string]any{
+		"transcripts": []powers{
+			// Setups of other sizes are skipped.
+			{2, 2, powersOfTau{g1Powers[:2], g2Powers[:2]}},
+			{len(g1Powers), len(g2Powers), powersOfTau{g1Powers, g2Powers}},
+		},
+		"participantIds": []string{},
+	})
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "transcript.json")
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	s, err := read(path, inferFormat(path))
+	require.NoError(t, err)
+	require.True(t, bytes.Equal(expected.G1Monomial, s.G1Monomial))
+	require.True(t, bytes.Equal(expected.G2Monomial, s.G2Monomial))
+	require.True(t, bytes.Equal(expected.G1Lagrange, s.G1Lagrange), "wrong lagrange points")
+	require.Error(t, write(filepath.Join(t.TempDir(), "out.json"), "transcript", s))
+}
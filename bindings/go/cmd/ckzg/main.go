@@ -0,0 +1,389 @@
+// Command ckzg computes and verifies KZG commitments, proofs, and cells with
+// these bindings, and runs the reference tests against them, so a build can be
+// checked and mismatching proofs debugged without writing Go code.
+//
+// Usage:
+//
+//	ckzg commit [-in FILE]
+//	ckzg prove [-in FILE] [-commitment HEX] [-z HEX]
+//	ckzg verify -commitment HEX -proof HEX [-in FILE | -z HEX -y HEX]
+//	ckzg cells [-in FILE]
+//	ckzg recover [-in FILE]
+//	ckzg test [-tests DIR] [-v]
+//
+// Blobs are read from FILE, or stdin if it is - or omitted, as raw bytes or
+// their hex encoding. The recover command reads a JSON object with the
+// cell_indices and cells of at least half of the extended blob, and the cells
+// command writes the cells and proofs in the same form. Values are 0x-prefixed
+// hex. Every command takes -trusted-setup and -precompute; the mainnet trusted
+// setup is used by default.
+//
+// Without -tests, the test command runs the reference tests embedded with the
+// ckzg_refvectors build tag, and fails if they aren't.
+//
+// The verify and test commands exit with status 1 if a proof or test fails.
+package main
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/mainnet"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/refvectors"
+)
+
+// errFailed is returned by commands which ran but found a bad proof or a
+// failed test.
+var errFailed = errors.New("failed")
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string, out io.Writer) error
+}
+
+var commands = []command{
+	{"commit", "compute the commitment to a blob", runCommit},
+	{"prove", "compute the blob proof, or the proof for an evaluation at -z", runProve},
+	{"verify", "verify a blob proof, or a proof for an evaluation at -z", runVerify},
+	{"cells", "compute the cells and cell proofs of a blob", runCells},
+	{"recover", "recover all cells and cell proofs from half of them", runRecover},
+	{"test", "run the reference tests", runTest},
+}
+
+// setupFlags are the flags of every command which select the trusted setup.
+type setupFlags struct {
+	trustedSetup string
+	precompute   uint
+}
+
+func newFlagSet(name string) (*flag.FlagSet, *setupFlags) {
+	fs := flag.NewFlagSet("ckzg "+name, flag.ExitOnError)
+	var s setupFlags
+	fs.StringVar(&s.trustedSetup, "trusted-setup", "", "path to the trusted setup file (the mainnet setup if empty)")
+	fs.UintVar(&s.precompute, "precompute", 0, "precompute level for the trusted setup, which speeds up cell proofs")
+	return fs, &s
+}
+
+func (s *setupFlags) load() error {
+	var err error
+	if s.trustedSetup == "" {
+		err = ckzg4844.LoadTrustedSetupFromReader(mainnet.TrustedSetup(), s.precompute)
+	} else {
+		err = ckzg4844.LoadTrustedSetupFile(s.trustedSetup, s.precompute)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load trusted setup: %w", err)
+	}
+	return nil
+}
+
+func readInput(path string) ([]byte, error) {
+	if path == "" || path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// readBlob reads a blob as raw bytes or their hex encoding.
+func readBlob(path string) (*ckzg4844.Blob, error) {
+	data, err := readInput(path)
+	if err != nil {
+		return nil, err
+	}
+	blob := new(ckzg4844.Blob)
+	if len(data) == ckzg4844.BytesPerBlob {
+		copy(blob[:], data)
+		return blob, nil
+	}
+	if err := blob.UnmarshalText(bytes.TrimSpace(data)); err != nil {
+		return nil, fmt.Errorf("expected %d raw bytes or hex encoded blob: %w", ckzg4844.BytesPerBlob, err)
+	}
+	return blob, nil
+}
+
+// parseHex decodes the flag named name into out, which must be set unless
+// optional.
+func parseHex(name, value string, out encoding.TextUnmarshaler, optional bool) (bool, error) {
+	if value == "" {
+		if optional {
+			return false, nil
+		}
+		return false, fmt.Errorf("missing -%s", name)
+	}
+	if err := out.UnmarshalText([]byte(value)); err != nil {
+		return false, fmt.Errorf("invalid -%s: %w", name, err)
+	}
+	return true, nil
+}
+
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// cellsJSON is the form in which cells are read and written.
+type cellsJSON struct {
+	CellIndices []uint64            `json:"cell_indices"`
+	Cells       []ckzg4844.Cell     `json:"cells"`
+	Proofs      []ckzg4844.KZGProof `json:"proofs,omitempty"`
+}
+
+func allCellsJSON(cells *[ckzg4844.CellsPerExtBlob]ckzg4844.Cell, proofs *[ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof) cellsJSON {
+	out := cellsJSON{
+		CellIndices: make([]uint64, ckzg4844.CellsPerExtBlob),
+		Cells:       cells[:],
+		Proofs:      proofs[:],
+	}
+	for i := range out.CellIndices {
+		out.CellIndices[i] = uint64(i)
+	}
+	return out
+}
+
+func runCommit(args []string, out io.Writer) error {
+	fs, setup := newFlagSet("commit")
+	in := fs.String("in", "-", "blob file, or - for stdin")
+	fs.Parse(args)
+
+	blob, err := readBlob(*in)
+	if err != nil {
+		return err
+	}
+	if err := setup.load(); err != nil {
+		return err
+	}
+	defer ckzg4844.FreeTrustedSetup()
+	commitment, err := ckzg4844.BlobToKZGCommitment(blob)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, commitment)
+	return nil
+}
+
+func runProve(args []string, out io.Writer) error {
+	fs, setup := newFlagSet("prove")
+	in := fs.String("in", "-", "blob file, or - for stdin")
+	commitmentHex := fs.String("commitment", "", "commitment to the blob (computed if empty)")
+	zHex := fs.String("z", "", "evaluation point, to compute a KZG proof instead of the blob proof")
+	fs.Parse(args)
+
+	var commitment ckzg4844.Bytes48
+	hasCommitment, err := parseHex("commitment", *commitmentHex, &commitment, true)
+	if err != nil {
+		return err
+	}
+	var z ckzg4844.Bytes32
+	hasZ, err := parseHex("z", *zHex, &z, true)
+	if err != nil {
+		return err
+	}
+	blob, err := readBlob(*in)
+	if err != nil {
+		return err
+	}
+	if err := setup.load(); err != nil {
+		return err
+	}
+	defer ckzg4844.FreeTrustedSetup()
+
+	if hasZ {
+		proof, y, err := ckzg4844.ComputeKZGProof(blob, z)
+		if err != nil {
+			return err
+		}
+		return writeJSON(out, struct {
+			Proof ckzg4844.KZGProof `json:"proof"`
+			Y     ckzg4844.Bytes32  `json:"y"`
+		}{proof, y})
+	}
+	if !hasCommitment {
+		c, err := ckzg4844.BlobToKZGCommitment(blob)
+		if err != nil {
+			return err
+		}
+		commitment = ckzg4844.Bytes48(c)
+	}
+	proof, err := ckzg4844.ComputeBlobKZGProof(blob, commitment)
+	if err != nil {
+		return err
+	}
+	return writeJSON(out, struct {
+		Commitment ckzg4844.Bytes48  `json:"commitment"`
+		Proof      ckzg4844.KZGProof `json:"proof"`
+	}{commitment, proof})
+}
+
+func runVerify(args []string, out io.Writer) error {
+	fs, setup := newFlagSet("verify")
+	in := fs.String("in", "-", "blob file, or - for stdin")
+	commitmentHex := fs.String("commitment", "", "commitment to the blob")
+	proofHex := fs.String("proof", "", "proof to verify")
+	zHex := fs.String("z", "", "evaluation point, to verify a KZG proof instead of a blob proof")
+	yHex := fs.String("y", "", "claimed evaluation at -z")
+	fs.Parse(args)
+
+	var commitment, proof ckzg4844.Bytes48
+	if _, err := parseHex("commitment", *commitmentHex, &commitment, false); err != nil {
+		return err
+	}
+	if _, err := parseHex("proof", *proofHex, &proof, false); err != nil {
+		return err
+	}
+	var z, y ckzg4844.Bytes32
+	hasZ, err := parseHex("z", *zHex, &z, true)
+	if err != nil {
+		return err
+	}
+	if _, err := parseHex("y", *yHex, &y, !hasZ); err != nil {
+		return err
+	}
+
+	var blob *ckzg4844.Blob
+	if !hasZ {
+		if blob, err = readBlob(*in); err != nil {
+			return err
+		}
+	}
+	if err := setup.load(); err != nil {
+		return err
+	}
+	defer ckzg4844.FreeTrustedSetup()
+	var ok bool
+	if hasZ {
+		ok, err = ckzg4844.VerifyKZGProof(commitment, z, y, proof)
+	} else {
+		ok, err = ckzg4844.VerifyBlobKZGProof(blob, commitment, proof)
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Fprintln(out, "invalid")
+		return errFailed
+	}
+	fmt.Fprintln(out, "valid")
+	return nil
+}
+
+func runCells(args []string, out io.Writer) error {
+	fs, setup := newFlagSet("cells")
+	in := fs.String("in", "-", "blob file, or - for stdin")
+	fs.Parse(args)
+
+	blob, err := readBlob(*in)
+	if err != nil {
+		return err
+	}
+	if err := setup.load(); err != nil {
+		return err
+	}
+	defer ckzg4844.FreeTrustedSetup()
+	cells := new([ckzg4844.CellsPerExtBlob]ckzg4844.Cell)
+	proofs := new([ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof)
+	if err := ckzg4844.ComputeCellsAndKZGProofsInto(cells, proofs, blob); err != nil {
+		return err
+	}
+	return writeJSON(out, allCellsJSON(cells, proofs))
+}
+
+func runRecover(args []string, out io.Writer) error {
+	fs, setup := newFlagSet("recover")
+	in := fs.String("in", "-", "JSON file with cell_indices and cells, or - for stdin")
+	fs.Parse(args)
+
+	data, err := readInput(*in)
+	if err != nil {
+		return err
+	}
+	var input cellsJSON
+	if err := json.Unmarshal(data, &input); err != nil {
+		return fmt.Errorf("invalid input: %w", err)
+	}
+	if err := setup.load(); err != nil {
+		return err
+	}
+	defer ckzg4844.FreeTrustedSetup()
+	cells := new([ckzg4844.CellsPerExtBlob]ckzg4844.Cell)
+	proofs := new([ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof)
+	if err := ckzg4844.RecoverCellsAndKZGProofsInto(cells, proofs, input.CellIndices, input.Cells); err != nil {
+		return err
+	}
+	return writeJSON(out, allCellsJSON(cells, proofs))
+}
+
+func runTest(args []string, out io.Writer) error {
+	fs, setup := newFlagSet("test")
+	testsDir := fs.String("tests", "", "directory containing the reference tests (the embedded tests if empty)")
+	verbose := fs.Bool("v", false, "print every test, not only the failed ones")
+	fs.Parse(args)
+
+	if err := setup.load(); err != nil {
+		return err
+	}
+	defer ckzg4844.FreeTrustedSetup()
+	results, err := refvectors.RunDir(*testsDir)
+	if errors.Is(err, refvectors.ErrNotEmbedded) {
+		return errors.New("missing -tests, and the reference tests are not embedded in this build")
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		name := result.Function + "/" + result.Preset + "/" + result.Name
+		if !result.Passed() {
+			fmt.Fprintf(out, "FAIL %s: %v\n", name, result.Err)
+		} else if *verbose {
+			fmt.Fprintf(out, "ok   %s\n", name)
+		}
+	}
+	summaries, failures := refvectors.Summarize(results)
+	for _, summary := range summaries {
+		fmt.Fprintf(out, "%-30s %-12s %5d passed %5d failed\n", summary.Function, summary.Preset, summary.Passed, summary.Failed)
+	}
+	if len(failures) > 0 {
+		return errFailed
+	}
+	return nil
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: ckzg COMMAND [flags]\n\nCommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", c.name, c.usage)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun ckzg COMMAND -h for the flags of a command.\n")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	for _, c := range commands {
+		if c.name != os.Args[1] {
+			continue
+		}
+		err := c.run(os.Args[2:], os.Stdout)
+		if errors.Is(err, errFailed) {
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ckzg %s: %v\n", c.name, err)
+			os.Exit(2)
+		}
+		return
+	}
+	usage()
+	os.Exit(2)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/ckzgtest"
+	"github.com/stretchr/testify/require"
+)
+
+// run runs a command and returns its output.
+func run(t *testing.T, c func([]string, io.Writer) error, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	err := c(args, &out)
+	return out.String(), err
+}
+
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	return path
+}
+
+func TestRoundTrip(t *testing.T) {
+	blob := ckzgtest.RandomBlob(1)
+	text, err := blob.MarshalText()
+	require.NoError(t, err)
+	// The raw bytes and the hex encoding are both accepted.
+	rawPath := writeFile(t, "blob.bin", blob[:])
+	hexPath := writeFile(t, "blob.hex", append(text, '\n'))
+
+	output, err := run(t, runCommit, "-in", rawPath)
+	require.NoError(t, err)
+	commitment := strings.TrimSpace(output)
+	output, err = run(t, runCommit, "-in", hexPath)
+	require.NoError(t, err)
+	require.Equal(t, commitment, strings.TrimSpace(output))
+
+	output, err = run(t, runProve, "-in", rawPath)
+	require.NoError(t, err)
+	var proved struct {
+		Commitment string `json:"commitment"`
+		Proof      string `json:"proof"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &proved))
+	require.Equal(t, commitment, proved.Commitment)
+
+	output, err = run(t, runVerify, "-in", hexPath, "-commitment", commitment, "-proof", proved.Proof)
+	require.NoError(t, err)
+	require.Equal(t, "valid\n", output)
+	// The commitment is a valid point, but not the proof.
+	output, err = run(t, runVerify, "-in", hexPath, "-commitment", commitment, "-proof", commitment)
+	require.ErrorIs(t, err, errFailed)
+	require.Equal(t, "invalid\n", output)
+
+	// An evaluation proof.
+	z := "0x" + strings.Repeat("00", 31) + "05"
+	output, err = run(t, runProve, "-in", rawPath, "-z", z)
+	require.NoError(t, err)
+	var evaluation struct {
+		Proof string `json:"proof"`
+		Y     string `json:"y"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &evaluation))
+	output, err = run(t, runVerify, "-commitment", commitment, "-proof", evaluation.Proof, "-z", z, "-y", evaluation.Y)
+	require.NoError(t, err)
+	require.Equal(t, "valid\n", output)
+
+	// Recovering the cells from every other one.
+	output, err = run(t, runCells, "-in", rawPath)
+	require.NoError(t, err)
+	var all cellsJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &all))
+	require.Len(t, all.Cells, ckzg4844.CellsPerExtBlob)
+	var half cellsJSON
+	for i := 1; i < ckzg4844.CellsPerExtBlob; i += 2 {
+		half.CellIndices = append(half.CellIndices, all.CellIndices[i])
+		half.Cells = append(half.Cells, all.Cells[i])
+	}
+	data, err := json.Marshal(half)
+	require.NoError(t, err)
+	recovered, err := run(t, runRecover, "-in", writeFile(t, "cells.json", data))
+	require.NoError(t, err)
+	require.Equal(t, output, recovered)
+}
+
+func TestReferenceTests(t *testing.T) {
+	output, err := run(t, runTest, "-tests", ckzgtest.ReferenceTestsDir())
+	require.NoError(t, err)
+	require.NotContains(t, output, "FAIL")
+	require.Contains(t, output, "verify_cell_kzg_proof_batch")
+
+	_, err = run(t, runTest, "-tests", t.TempDir())
+	require.ErrorContains(t, err, "no reference tests found")
+}
@@ -0,0 +1,480 @@
+package ckzg4844
+
+// #cgo CFLAGS: -I${SRCDIR}/../../src
+// #cgo CFLAGS: -I${SRCDIR}/blst_headers
+// #include <stdio.h>
+// #include <stdlib.h>
+// #include "ckzg.h"
+import "C"
+
+import (
+	"context"
+	"os"
+	"unsafe"
+)
+
+/*
+Context is a loaded trusted setup, with the KZG operations as methods. Several
+contexts, for example for networks with different setups, can be used side by
+side in one process, and libraries embedding these bindings can use their own
+context rather than sharing the package level setup.
+
+The package level functions use a default context, which is loaded with
+LoadTrustedSetup or LoadTrustedSetupFile. A Context must not be copied.
+*/
+type Context struct {
+	settings C.KZGSettings
+	loaded   bool
+	// snapshotRelease unmaps or frees the memory of a restored precompute
+	// snapshot, which the settings point into. It is nil if there is none.
+	snapshotRelease func()
+	// labels is the context whose pprof labels the profiler labels of
+	// operations are added to. It is nil unless c is from WithProfilerLabels.
+	labels context.Context
+	// shared is set if the settings belong to another context, which frees
+	// them.
+	shared bool
+}
+
+var defaultContext Context
+
+var _ Backend = (*Context)(nil)
+
+// NewContext loads a trusted setup like LoadTrustedSetup, into a new context.
+func NewContext(g1MonomialBytes, g1LagrangeBytes, g2MonomialBytes []byte, precompute uint) (*Context, error) {
+	c := new(Context)
+	if err := c.load(g1MonomialBytes, g1LagrangeBytes, g2MonomialBytes, precompute); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewContextFromFile loads a trusted setup like LoadTrustedSetupFile, into a
+// new context.
+func NewContextFromFile(trustedSetupFile string, precompute uint) (*Context, error) {
+	c := new(Context)
+	if err := c.loadFile(trustedSetupFile, precompute); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Context) load(g1MonomialBytes, g1LagrangeBytes, g2MonomialBytes []byte, precompute uint) error {
+	ret := C.load_trusted_setup(
+		&c.settings,
+		*(**C.uint8_t)(unsafe.Pointer(&g1MonomialBytes)),
+		(C.uint64_t)(len(g1MonomialBytes)),
+		*(**C.uint8_t)(unsafe.Pointer(&g1LagrangeBytes)),
+		(C.uint64_t)(len(g1LagrangeBytes)),
+		*(**C.uint8_t)(unsafe.Pointer(&g2MonomialBytes)),
+		(C.uint64_t)(len(g2MonomialBytes)),
+		(C.uint64_t)(precompute))
+	if ret == C.C_KZG_OK {
+		c.loaded = true
+		return c.loadSelfTest("LoadTrustedSetup")
+	}
+	return opError("LoadTrustedSetup", makeErrorFromRet(ret), nil)
+}
+
+func (c *Context) loadFile(trustedSetupFile string, precompute uint) error {
+	cTrustedSetupFile := C.CString(trustedSetupFile)
+	defer C.free(unsafe.Pointer(cTrustedSetupFile))
+	cMode := C.CString("r")
+	defer C.free(unsafe.Pointer(cMode))
+	fp, err := C.fopen(cTrustedSetupFile, cMode)
+	if fp == nil {
+		if err == nil {
+			err = ErrBadArgs
+		}
+		return &Error{Op: "LoadTrustedSetupFile", Err: &os.PathError{Op: "open", Path: trustedSetupFile, Err: err}}
+	}
+	ret := C.load_trusted_setup_file(&c.settings, fp, (C.uint64_t)(precompute))
+	C.fclose(fp)
+	if ret == C.C_KZG_OK {
+		c.loaded = true
+		return c.loadSelfTest("LoadTrustedSetupFile")
+	}
+	return opError("LoadTrustedSetupFile", makeErrorFromRet(ret), nil)
+}
+
+// Free frees the trusted setup of c, like FreeTrustedSetup. The context must
+// not be used afterwards.
+func (c *Context) Free() {
+	if !c.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	if c.shared {
+		panic("can't free a context from WithProfilerLabels")
+	}
+	c.releasePrecomputeSnapshot()
+	C.free_trusted_setup(&c.settings)
+	c.loaded = false
+}
+
+// BlobToKZGCommitment is BlobToKZGCommitment using the trusted setup of c.
+func (c *Context) BlobToKZGCommitment(blob *Blob) (KZGCommitment, error) {
+	if !c.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	if err := injectedError("BlobToKZGCommitment"); err != nil {
+		return KZGCommitment{}, err
+	}
+	if blob == nil {
+		return KZGCommitment{}, &Error{Op: "BlobToKZGCommitment", Err: ErrBadArgs}
+	}
+
+	var commitment KZGCommitment
+	var ret C.C_KZG_RET
+	c.instrument("BlobToKZGCommitment", 1, func() {
+		ret = C.blob_to_kzg_commitment(
+			(*C.KZGCommitment)(unsafe.Pointer(&commitment)),
+			(*C.Blob)(unsafe.Pointer(blob)),
+			&c.settings)
+	})
+
+	if ret != C.C_KZG_OK {
+		return KZGCommitment{}, opError("BlobToKZGCommitment", makeErrorFromRet(ret), func() error {
+			return checkFieldElements("blob", 0, blob[:])
+		})
+	}
+	return commitment, nil
+}
+
+// ComputeKZGProof is ComputeKZGProof using the trusted setup of c.
+func (c *Context) ComputeKZGProof(blob *Blob, zBytes Bytes32) (KZGProof, Bytes32, error) {
+	if !c.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	if err := injectedError("ComputeKZGProof"); err != nil {
+		return KZGProof{}, Bytes32{}, err
+	}
+	if blob == nil {
+		return KZGProof{}, Bytes32{}, &Error{Op: "ComputeKZGProof", Err: ErrBadArgs}
+	}
+
+	var proof, y = KZGProof{}, Bytes32{}
+	var ret C.C_KZG_RET
+	c.instrument("ComputeKZGProof", 1, func() {
+		ret = C.compute_kzg_proof(
+			(*C.KZGProof)(unsafe.Pointer(&proof)),
+			(*C.Bytes32)(unsafe.Pointer(&y)),
+			(*C.Blob)(unsafe.Pointer(blob)),
+			(*C.Bytes32)(unsafe.Pointer(&zBytes)),
+			&c.settings)
+	})
+
+	if ret != C.C_KZG_OK {
+		return KZGProof{}, Bytes32{}, opError("ComputeKZGProof", makeErrorFromRet(ret), func() error {
+			return firstError(
+				func() error { return checkFieldElements("blob", 0, blob[:]) },
+				func() error { return checkScalar("z", &zBytes) })
+		})
+	}
+	return proof, y, nil
+}
+
+// ComputeBlobKZGProof is ComputeBlobKZGProof using the trusted setup of c.
+func (c *Context) ComputeBlobKZGProof(blob *Blob, commitmentBytes Bytes48) (KZGProof, error) {
+	if !c.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	if err := injectedError("ComputeBlobKZGProof"); err != nil {
+		return KZGProof{}, err
+	}
+	if blob == nil {
+		return KZGProof{}, &Error{Op: "ComputeBlobKZGProof", Err: ErrBadArgs}
+	}
+	var proof KZGProof
+	var ret C.C_KZG_RET
+	c.instrument("ComputeBlobKZGProof", 1, func() {
+		ret = C.compute_blob_kzg_proof(
+			(*C.KZGProof)(unsafe.Pointer(&proof)),
+			(*C.Blob)(unsafe.Pointer(blob)),
+			(*C.Bytes48)(unsafe.Pointer(&commitmentBytes)),
+			&c.settings)
+	})
+
+	if ret != C.C_KZG_OK {
+		return KZGProof{}, opError("ComputeBlobKZGProof", makeErrorFromRet(ret), func() error {
+			return firstError(
+				func() error { return checkFieldElements("blob", 0, blob[:]) },
+				func() error { return checkPoint("commitment", 0, &commitmentBytes) })
+		})
+	}
+	return proof, nil
+}
+
+// VerifyKZGProof is VerifyKZGProof using the trusted setup of c.
+func (c *Context) VerifyKZGProof(commitmentBytes Bytes48, zBytes, yBytes Bytes32, proofBytes Bytes48) (bool, error) {
+	if !c.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	if err := injectedError("VerifyKZGProof"); err != nil {
+		return false, err
+	}
+	var result C.bool
+	var ret C.C_KZG_RET
+	c.instrument("VerifyKZGProof", 1, func() {
+		ret = C.verify_kzg_proof(
+			&result,
+			(*C.Bytes48)(unsafe.Pointer(&commitmentBytes)),
+			(*C.Bytes32)(unsafe.Pointer(&zBytes)),
+			(*C.Bytes32)(unsafe.Pointer(&yBytes)),
+			(*C.Bytes48)(unsafe.Pointer(&proofBytes)),
+			&c.settings)
+	})
+
+	if ret != C.C_KZG_OK {
+		return false, opError("VerifyKZGProof", makeErrorFromRet(ret), func() error {
+			return firstError(
+				func() error { return checkPoint("commitment", 0, &commitmentBytes) },
+				func() error { return checkScalar("z", &zBytes) },
+				func() error { return checkScalar("y", &yBytes) },
+				func() error { return checkPoint("proof", 0, &proofBytes) })
+		})
+	}
+	return bool(result) && !injectedRejection(commitmentBytes), nil
+}
+
+// VerifyBlobKZGProof is VerifyBlobKZGProof using the trusted setup of c.
+func (c *Context) VerifyBlobKZGProof(blob *Blob, commitmentBytes, proofBytes Bytes48) (bool, error) {
+	if !c.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	if err := injectedError("VerifyBlobKZGProof"); err != nil {
+		return false, err
+	}
+	if blob == nil {
+		return false, &Error{Op: "VerifyBlobKZGProof", Err: ErrBadArgs}
+	}
+
+	var result C.bool
+	var ret C.C_KZG_RET
+	c.instrument("VerifyBlobKZGProof", 1, func() {
+		ret = C.verify_blob_kzg_proof(
+			&result,
+			(*C.Blob)(unsafe.Pointer(blob)),
+			(*C.Bytes48)(unsafe.Pointer(&commitmentBytes)),
+			(*C.Bytes48)(unsafe.Pointer(&proofBytes)),
+			&c.settings)
+	})
+
+	if ret != C.C_KZG_OK {
+		return false, opError("VerifyBlobKZGProof", makeErrorFromRet(ret), func() error {
+			return firstError(
+				func() error { return checkFieldElements("blob", 0, blob[:]) },
+				func() error { return checkPoint("commitment", 0, &commitmentBytes) },
+				func() error { return checkPoint("proof", 0, &proofBytes) })
+		})
+	}
+	return bool(result) && !injectedRejection(commitmentBytes), nil
+}
+
+// VerifyBlobKZGProofBatch is VerifyBlobKZGProofBatch using the trusted setup of c.
+func (c *Context) VerifyBlobKZGProofBatch(blobs []Blob, commitmentsBytes, proofsBytes []Bytes48) (bool, error) {
+	if !c.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	if err := injectedError("VerifyBlobKZGProofBatch"); err != nil {
+		return false, err
+	}
+	if err := checkBlobBatchLengths("VerifyBlobKZGProofBatch", blobs, commitmentsBytes, proofsBytes); err != nil {
+		return false, err
+	}
+	ok, err := verifyChunked(len(blobs), blobBatchItemBytes, func(start, end int) (bool, error) {
+		return c.verifyBlobKZGProofBatch(blobs[start:end], commitmentsBytes[start:end], proofsBytes[start:end])
+	})
+	if err != nil {
+		return false, opError("VerifyBlobKZGProofBatch", err, func() error {
+			return diagnoseBlobBatch(blobs, commitmentsBytes, proofsBytes)
+		})
+	}
+	return ok, nil
+}
+
+// verifyBlobKZGProofBatch verifies a batch in a single call.
+func (c *Context) verifyBlobKZGProofBatch(blobs []Blob, commitmentsBytes, proofsBytes []Bytes48) (bool, error) {
+	var result C.bool
+	var ret C.C_KZG_RET
+	c.instrument("VerifyBlobKZGProofBatch", len(blobs), func() {
+		ret = C.verify_blob_kzg_proof_batch(
+			&result,
+			*(**C.Blob)(unsafe.Pointer(&blobs)),
+			*(**C.Bytes48)(unsafe.Pointer(&commitmentsBytes)),
+			*(**C.Bytes48)(unsafe.Pointer(&proofsBytes)),
+			(C.uint64_t)(len(blobs)),
+			&c.settings)
+	})
+
+	if ret != C.C_KZG_OK {
+		return false, makeErrorFromRet(ret)
+	}
+	return bool(result) && !injectedRejection(commitmentsBytes...), nil
+}
+
+// ComputeCellsAndKZGProofs is ComputeCellsAndKZGProofs using the trusted setup of c.
+func (c *Context) ComputeCellsAndKZGProofs(blob *Blob) ([CellsPerExtBlob]Cell, [CellsPerExtBlob]KZGProof, error) {
+	if !c.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	if err := injectedError("ComputeCellsAndKZGProofs"); err != nil {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, err
+	}
+
+	cells := [CellsPerExtBlob]Cell{}
+	proofs := [CellsPerExtBlob]KZGProof{}
+	var ret C.C_KZG_RET
+	c.instrument("ComputeCellsAndKZGProofs", 1, func() {
+		ret = C.compute_cells_and_kzg_proofs(
+			(*C.Cell)(unsafe.Pointer(&cells)),
+			(*C.KZGProof)(unsafe.Pointer(&proofs)),
+			(*C.Blob)(unsafe.Pointer(blob)),
+			&c.settings)
+	})
+
+	if ret != C.C_KZG_OK {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, opError("ComputeCellsAndKZGProofs", makeErrorFromRet(ret), func() error {
+			return checkFieldElements("blob", 0, blob[:])
+		})
+	}
+	return cells, proofs, nil
+}
+
+// ComputeCellsAndKZGProofsInto is ComputeCellsAndKZGProofsInto using the trusted setup of c.
+func (c *Context) ComputeCellsAndKZGProofsInto(cells *[CellsPerExtBlob]Cell, proofs *[CellsPerExtBlob]KZGProof, blob *Blob) error {
+	if !c.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	if err := injectedError("ComputeCellsAndKZGProofs"); err != nil {
+		return err
+	}
+	if blob == nil || (cells == nil && proofs == nil) {
+		return &Error{Op: "ComputeCellsAndKZGProofs", Err: ErrBadArgs}
+	}
+
+	var ret C.C_KZG_RET
+	c.instrument("ComputeCellsAndKZGProofs", 1, func() {
+		ret = C.compute_cells_and_kzg_proofs(
+			(*C.Cell)(unsafe.Pointer(cells)),
+			(*C.KZGProof)(unsafe.Pointer(proofs)),
+			(*C.Blob)(unsafe.Pointer(blob)),
+			&c.settings)
+	})
+
+	if ret != C.C_KZG_OK {
+		return opError("ComputeCellsAndKZGProofs", makeErrorFromRet(ret), func() error {
+			return checkFieldElements("blob", 0, blob[:])
+		})
+	}
+	return nil
+}
+
+// RecoverCellsAndKZGProofs is RecoverCellsAndKZGProofs using the trusted setup of c.
+func (c *Context) RecoverCellsAndKZGProofs(cellIndices []uint64, cells []Cell) ([CellsPerExtBlob]Cell, [CellsPerExtBlob]KZGProof, error) {
+	if !c.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	if err := injectedError("RecoverCellsAndKZGProofs"); err != nil {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, err
+	}
+	if len(cellIndices) != len(cells) {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, lengthError("RecoverCellsAndKZGProofs", "cellIndices", len(cellIndices), len(cells))
+	}
+
+	recoveredCells := [CellsPerExtBlob]Cell{}
+	recoveredProofs := [CellsPerExtBlob]KZGProof{}
+	var ret C.C_KZG_RET
+	c.instrument("RecoverCellsAndKZGProofs", len(cells), func() {
+		ret = C.recover_cells_and_kzg_proofs(
+			(*C.Cell)(unsafe.Pointer(&recoveredCells)),
+			(*C.KZGProof)(unsafe.Pointer(&recoveredProofs)),
+			*(**C.uint64_t)(unsafe.Pointer(&cellIndices)),
+			*(**C.Cell)(unsafe.Pointer(&cells)),
+			(C.uint64_t)(len(cells)),
+			&c.settings)
+	})
+
+	if ret != C.C_KZG_OK {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, opError("RecoverCellsAndKZGProofs", makeErrorFromRet(ret), func() error {
+			return diagnoseRecovery(cellIndices, cells)
+		})
+	}
+	return recoveredCells, recoveredProofs, nil
+}
+
+// RecoverCellsAndKZGProofsInto is RecoverCellsAndKZGProofsInto using the trusted setup of c.
+func (c *Context) RecoverCellsAndKZGProofsInto(recoveredCells *[CellsPerExtBlob]Cell, recoveredProofs *[CellsPerExtBlob]KZGProof, cellIndices []uint64, cells []Cell) error {
+	if !c.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	if err := injectedError("RecoverCellsAndKZGProofs"); err != nil {
+		return err
+	}
+	if recoveredCells == nil {
+		return &Error{Op: "RecoverCellsAndKZGProofs", Err: ErrBadArgs}
+	}
+	if len(cellIndices) != len(cells) {
+		return lengthError("RecoverCellsAndKZGProofs", "cellIndices", len(cellIndices), len(cells))
+	}
+
+	var ret C.C_KZG_RET
+	c.instrument("RecoverCellsAndKZGProofs", len(cells), func() {
+		ret = C.recover_cells_and_kzg_proofs(
+			(*C.Cell)(unsafe.Pointer(recoveredCells)),
+			(*C.KZGProof)(unsafe.Pointer(recoveredProofs)),
+			*(**C.uint64_t)(unsafe.Pointer(&cellIndices)),
+			*(**C.Cell)(unsafe.Pointer(&cells)),
+			(C.uint64_t)(len(cells)),
+			&c.settings)
+	})
+
+	if ret != C.C_KZG_OK {
+		return opError("RecoverCellsAndKZGProofs", makeErrorFromRet(ret), func() error {
+			return diagnoseRecovery(cellIndices, cells)
+		})
+	}
+	return nil
+}
+
+// VerifyCellKZGProofBatch is VerifyCellKZGProofBatch using the trusted setup of c.
+func (c *Context) VerifyCellKZGProofBatch(commitmentsBytes []Bytes48, cellIndices []uint64, cells []Cell, proofsBytes []Bytes48) (bool, error) {
+	if !c.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	if err := injectedError("VerifyCellKZGProofBatch"); err != nil {
+		return false, err
+	}
+	if err := checkCellBatchLengths("VerifyCellKZGProofBatch", commitmentsBytes, cellIndices, cells, proofsBytes); err != nil {
+		return false, err
+	}
+	ok, err := verifyChunked(len(cells), cellBatchItemBytes, func(start, end int) (bool, error) {
+		return c.verifyCellKZGProofBatch(commitmentsBytes[start:end], cellIndices[start:end], cells[start:end], proofsBytes[start:end])
+	})
+	if err != nil {
+		return false, opError("VerifyCellKZGProofBatch", err, func() error {
+			return diagnoseCellBatch(commitmentsBytes, cellIndices, cells, proofsBytes)
+		})
+	}
+	return ok, nil
+}
+
+// verifyCellKZGProofBatch verifies a batch in a single call.
+func (c *Context) verifyCellKZGProofBatch(commitmentsBytes []Bytes48, cellIndices []uint64, cells []Cell, proofsBytes []Bytes48) (bool, error) {
+	var result C.bool
+	var ret C.C_KZG_RET
+	c.instrument("VerifyCellKZGProofBatch", len(cells), func() {
+		ret = C.verify_cell_kzg_proof_batch(
+			&result,
+			*(**C.Bytes48)(unsafe.Pointer(&commitmentsBytes)),
+			*(**C.uint64_t)(unsafe.Pointer(&cellIndices)),
+			*(**C.Cell)(unsafe.Pointer(&cells)),
+			*(**C.Bytes48)(unsafe.Pointer(&proofsBytes)),
+			(C.uint64_t)(len(cells)),
+			&c.settings)
+	})
+
+	if ret != C.C_KZG_OK {
+		return false, makeErrorFromRet(ret)
+	}
+	return bool(result) && !injectedRejection(commitmentsBytes...), nil
+}
@@ -0,0 +1,44 @@
+package ckzg4844
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContext(t *testing.T) {
+	c, err := NewContextFromFile("../../src/trusted_setup.txt", 0)
+	require.NoError(t, err)
+
+	blob := selfTestBlob()
+	commitment, err := c.BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+	expected, err := BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+	require.Equal(t, expected, commitment)
+	require.Equal(t, TrustedSetupFingerprint(), c.Fingerprint())
+	proof, err := c.ComputeBlobKZGProof(blob, Bytes48(commitment))
+	require.NoError(t, err)
+	ok, err := VerifyBlobKZGProof(blob, Bytes48(commitment), Bytes48(proof))
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	c.Free()
+	require.Panics(t, func() { _, _ = c.BlobToKZGCommitment(blob) })
+	// The default context is unaffected.
+	_, err = BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+
+	_, err = NewContext(nil, nil, nil, 0)
+	require.ErrorIs(t, err, ErrBadArgs)
+}
+
+func TestContextMissingFile(t *testing.T) {
+	_, err := NewContextFromFile("testdata/nonexistent.txt", 0)
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	var opErr *Error
+	require.ErrorAs(t, err, &opErr)
+	require.Equal(t, "LoadTrustedSetupFile", opErr.Op)
+	require.Equal(t, "LoadTrustedSetupFile: open testdata/nonexistent.txt: no such file or directory", err.Error())
+}
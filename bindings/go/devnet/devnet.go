@@ -0,0 +1,131 @@
+// Package devnet provides an INSECURE trusted setup generated from a publicly
+// known secret, for devnets and for debugging blob issues between clients:
+// every participant using the same secret produces identical commitments and
+// proofs for identical blobs, which makes problems reproducible without the
+// mainnet setup. Anyone who knows the secret can forge proofs, so it must
+// never be used on a public network.
+//
+// Loading the setup requires passing InsecureAcknowledgement, so that it can't
+// happen by accident.
+package devnet
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	blst "github.com/supranational/blst/bindings/go"
+)
+
+const (
+	// NumG1Points is the number of G1 points in a trusted setup.
+	NumG1Points = ckzg4844.FieldElementsPerBlob
+	// NumG2Points is the number of G2 points in a trusted setup.
+	NumG2Points = 65
+	// DefaultSecret is the secret of the devnet setup unless agreed otherwise.
+	DefaultSecret = 1337
+
+	// InsecureAcknowledgement must be passed to LoadInsecureTrustedSetup.
+	InsecureAcknowledgement = "I understand that this trusted setup is insecure"
+)
+
+// ErrInvalidSecret is returned for a secret which doesn't give a usable setup,
+// which is 0 or a root of unity of the domain.
+var ErrInvalidSecret = errors.New("invalid trusted setup secret")
+
+// ErrNotAcknowledged is returned by LoadInsecureTrustedSetup when it isn't
+// passed InsecureAcknowledgement.
+var ErrNotAcknowledged = errors.New("the devnet trusted setup is insecure and wasn't acknowledged as such")
+
+var (
+	// blsModulus is the order of the BLS12-381 scalar field.
+	blsModulus = new(big.Int).SetBytes(ckzg4844.BLSModulus[:])
+	// primitiveRoot is the generator used to derive the roots of unity.
+	primitiveRoot = big.NewInt(7)
+)
+
+// scalarBytes converts a field element into the little-endian form blst expects.
+func scalarBytes(x *big.Int) []byte {
+	out := make([]byte, 32)
+	x.FillBytes(out)
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
+
+/*
+InsecureTrustedSetup generates a trusted setup from a publicly known secret.
+The returned byte slices are in the form expected by ckzg4844.LoadTrustedSetup.
+Anyone who knows the secret can forge proofs.
+
+The secret must not be 0, which makes every point but the first the identity,
+or a root of unity of the domain (s^NumG1Points = 1), for which the Lagrange
+points aren't defined. ErrInvalidSecret is returned for those.
+*/
+func InsecureTrustedSetup(secret uint64) (g1MonomialBytes, g1LagrangeBytes, g2MonomialBytes []byte, err error) {
+	s := new(big.Int).SetUint64(secret)
+	n := big.NewInt(NumG1Points)
+
+	// The root of unity for the domain of size NumG1Points.
+	exp := new(big.Int).Sub(blsModulus, big.NewInt(1))
+	exp.Div(exp, n)
+	omega := new(big.Int).Exp(primitiveRoot, exp, blsModulus)
+
+	// Lagrange basis polynomials evaluated at s are:
+	//     L_i(s) = omega^i * (s^n - 1) / (n * (s - omega^i))
+	sn := new(big.Int).Exp(s, n, blsModulus)
+	if s.Sign() == 0 || sn.Cmp(big.NewInt(1)) == 0 {
+		return nil, nil, nil, fmt.Errorf("%w: %d is 0 or a root of unity", ErrInvalidSecret, secret)
+	}
+	numerator := new(big.Int).Sub(sn, big.NewInt(1))
+	numerator.Mod(numerator, blsModulus)
+	nInv := new(big.Int).ModInverse(n, blsModulus)
+	numerator.Mul(numerator, nInv).Mod(numerator, blsModulus)
+
+	g1 := blst.P1Generator()
+	g1MonomialBytes = make([]byte, 0, NumG1Points*48)
+	g1LagrangeBytes = make([]byte, 0, NumG1Points*48)
+	power := big.NewInt(1)
+	root := big.NewInt(1)
+	for i := 0; i < NumG1Points; i++ {
+		g1MonomialBytes = append(g1MonomialBytes, g1.Mult(scalarBytes(power)).Compress()...)
+
+		denominator := new(big.Int).Sub(s, root)
+		denominator.Mod(denominator, blsModulus)
+		li := new(big.Int).ModInverse(denominator, blsModulus)
+		li.Mul(li, root).Mod(li, blsModulus)
+		li.Mul(li, numerator).Mod(li, blsModulus)
+		g1LagrangeBytes = append(g1LagrangeBytes, g1.Mult(scalarBytes(li)).Compress()...)
+
+		power.Mul(power, s).Mod(power, blsModulus)
+		root.Mul(root, omega).Mod(root, blsModulus)
+	}
+
+	g2 := blst.P2Generator()
+	g2MonomialBytes = make([]byte, 0, NumG2Points*96)
+	power.SetInt64(1)
+	for i := 0; i < NumG2Points; i++ {
+		g2MonomialBytes = append(g2MonomialBytes, g2.Mult(scalarBytes(power)).Compress()...)
+		power.Mul(power, s).Mod(power, blsModulus)
+	}
+	return g1MonomialBytes, g1LagrangeBytes, g2MonomialBytes, nil
+}
+
+/*
+LoadInsecureTrustedSetup loads the trusted setup generated from secret in place
+of a real one. acknowledgement must be InsecureAcknowledgement, otherwise
+ErrNotAcknowledged is returned and nothing is loaded. Secrets rejected by
+InsecureTrustedSetup aren't loaded either.
+*/
+func LoadInsecureTrustedSetup(acknowledgement string, secret uint64, precompute uint) error {
+	if acknowledgement != InsecureAcknowledgement {
+		return ErrNotAcknowledged
+	}
+	g1Monomial, g1Lagrange, g2Monomial, err := InsecureTrustedSetup(secret)
+	if err != nil {
+		return err
+	}
+	return ckzg4844.LoadTrustedSetup(g1Monomial, g1Lagrange, g2Monomial, precompute)
+}
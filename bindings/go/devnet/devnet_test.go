@@ -0,0 +1,70 @@
+package devnet_test
+
+import (
+	"testing"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/ckzgtest"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/devnet"
+	"github.com/stretchr/testify/require"
+	blst "github.com/supranational/blst/bindings/go"
+)
+
+func TestLoadInsecureTrustedSetup(t *testing.T) {
+	require.ErrorIs(t, devnet.LoadInsecureTrustedSetup("yes", devnet.DefaultSecret, 0), devnet.ErrNotAcknowledged)
+	require.NoError(t, devnet.LoadInsecureTrustedSetup(devnet.InsecureAcknowledgement, devnet.DefaultSecret, 0))
+	defer ckzg4844.FreeTrustedSetup()
+
+	blob := ckzgtest.RandomBlob(1)
+	ckzgtest.RequireRoundTrip(t, blob)
+}
+
+func TestInsecureTrustedSetup(t *testing.T) {
+	g1Monomial, g1Lagrange, g2Monomial, err := devnet.InsecureTrustedSetup(devnet.DefaultSecret)
+	require.NoError(t, err)
+	require.Len(t, g1Monomial, devnet.NumG1Points*48)
+	require.Len(t, g1Lagrange, devnet.NumG1Points*48)
+	require.Len(t, g2Monomial, devnet.NumG2Points*96)
+	// The first monomial points are the generators, s^0 = 1.
+	require.Equal(t, blst.P1Generator().Compress(), g1Monomial[:48])
+	require.Equal(t, blst.P2Generator().Compress(), g2Monomial[:96])
+}
+
+func TestInvalidSecrets(t *testing.T) {
+	// 1 is a root of unity, and 0 makes every point but the first the
+	// identity.
+	for _, secret := range []uint64{0, 1} {
+		_, _, _, err := devnet.InsecureTrustedSetup(secret)
+		require.ErrorIs(t, err, devnet.ErrInvalidSecret, "secret %d", secret)
+		err = devnet.LoadInsecureTrustedSetup(devnet.InsecureAcknowledgement, secret, 0)
+		require.ErrorIs(t, err, devnet.ErrInvalidSecret, "secret %d", secret)
+	}
+}
+
+func TestContexts(t *testing.T) {
+	// The mainnet setup is the default, next to a devnet context.
+	ckzgtest.LoadTrustedSetup(t)
+	g1Monomial, g1Lagrange, g2Monomial, err := devnet.InsecureTrustedSetup(devnet.DefaultSecret)
+	require.NoError(t, err)
+	c, err := ckzg4844.NewContext(g1Monomial, g1Lagrange, g2Monomial, 0)
+	require.NoError(t, err)
+	defer c.Free()
+	// The known answers are only checked for the mainnet setup.
+	require.NoError(t, c.SelfTest())
+
+	blob := ckzgtest.RandomBlob(2)
+	commitment, err := c.BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+	proof, err := c.ComputeBlobKZGProof(blob, ckzg4844.Bytes48(commitment))
+	require.NoError(t, err)
+	mainnetCommitment, err := ckzg4844.BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+	require.NotEqual(t, mainnetCommitment, commitment)
+
+	ok, err := c.VerifyBlobKZGProof(blob, ckzg4844.Bytes48(commitment), ckzg4844.Bytes48(proof))
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = ckzg4844.VerifyBlobKZGProof(blob, ckzg4844.Bytes48(commitment), ckzg4844.Bytes48(proof))
+	require.NoError(t, err)
+	require.False(t, ok)
+}
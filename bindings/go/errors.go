@@ -0,0 +1,266 @@
+package ckzg4844
+
+// #cgo CFLAGS: -I${SRCDIR}/../../src
+// #cgo CFLAGS: -I${SRCDIR}/blst_headers
+// #include "ckzg.h"
+import "C"
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// ErrVerificationFailed means proofs were checked and at least one of them
+// doesn't verify. It is returned by the functions which report the outcome of
+// a verification as an error, such as GoVerifyBlobKZGProofBatch, rather than
+// as a bool.
+var ErrVerificationFailed = errors.New("verification failed")
+
+/*
+Error is the error of a failed KZG operation. Err is the detail known about the
+failure: one of the typed errors below when the bindings could tell which
+argument was at fault, or ErrBadArgs, ErrError or ErrMalloc otherwise. The
+typed errors all match ErrBadArgs with errors.Is.
+
+When the C library rejects arguments, it doesn't say which one, so the bindings
+check them in Go to find out. This only happens on failure and costs nothing
+otherwise.
+*/
+type Error struct {
+	// Op is the name of the function which failed, for example
+	// "VerifyBlobKZGProofBatch".
+	Op  string
+	Err error
+}
+
+func (e *Error) Error() string {
+	return e.Op + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ErrInvalidFieldElement means a field element isn't canonical, that is, not
+// less than the BLS modulus.
+type ErrInvalidFieldElement struct {
+	// Argument is "blob", "cell", "z" or "y".
+	Argument string
+	// Index is the position of the blob or cell in a batch.
+	Index int
+	// Element is the position of the field element in the blob or cell.
+	Element int
+}
+
+func (e ErrInvalidFieldElement) Error() string {
+	switch e.Argument {
+	case "blob", "cell":
+		return fmt.Sprintf("%v: field element %d of %s %d isn't canonical", ErrBadArgs, e.Element, e.Argument, e.Index)
+	}
+	return fmt.Sprintf("%v: %s isn't a canonical field element", ErrBadArgs, e.Argument)
+}
+
+func (e ErrInvalidFieldElement) Is(target error) bool {
+	return target == ErrBadArgs
+}
+
+// ErrInvalidPoint means a commitment or proof isn't the encoding of a point in
+// the G1 subgroup.
+type ErrInvalidPoint struct {
+	// Argument is "commitment" or "proof".
+	Argument string
+	// Index is the position of the commitment or proof in a batch.
+	Index int
+}
+
+func (e ErrInvalidPoint) Error() string {
+	return fmt.Sprintf("%v: %s %d isn't a valid G1 point", ErrBadArgs, e.Argument, e.Index)
+}
+
+func (e ErrInvalidPoint) Is(target error) bool {
+	return target == ErrBadArgs
+}
+
+// ErrInvalidCellIndex means a cell index is out of range, or repeated where
+// indices must be unique.
+type ErrInvalidCellIndex struct {
+	// Index is the position of the cell index in its argument.
+	Index     int
+	CellIndex uint64
+}
+
+func (e ErrInvalidCellIndex) Error() string {
+	return fmt.Sprintf("%v: cell index %d at %d is out of range or repeated", ErrBadArgs, e.CellIndex, e.Index)
+}
+
+func (e ErrInvalidCellIndex) Is(target error) bool {
+	return target == ErrBadArgs
+}
+
+// ErrLengthMismatch means an argument doesn't have the length the others
+// require.
+type ErrLengthMismatch struct {
+	Argument string
+	Length   int
+	Expected int
+	// Multiple is set if the length must be a multiple of Expected, rather
+	// than equal to it.
+	Multiple bool
+}
+
+func (e ErrLengthMismatch) Error() string {
+	if e.Multiple {
+		return fmt.Sprintf("%v: %s has length %d, expected a multiple of %d", ErrBadArgs, e.Argument, e.Length, e.Expected)
+	}
+	return fmt.Sprintf("%v: %s has length %d, expected %d", ErrBadArgs, e.Argument, e.Length, e.Expected)
+}
+
+func (e ErrLengthMismatch) Is(target error) bool {
+	return target == ErrBadArgs
+}
+
+// opError wraps err, returned by op, in an Error. If err is ErrBadArgs, the
+// detail is what diagnose finds. An Error returned by a nested call is
+// diagnosed again, so that indices refer to op's arguments.
+func opError(op string, err error, diagnose func() error) error {
+	var e *Error
+	if errors.As(err, &e) {
+		err = e.Err
+	}
+	if errors.Is(err, ErrBadArgs) && diagnose != nil {
+		if detail := diagnose(); detail != nil {
+			err = detail
+		}
+	}
+	return &Error{Op: op, Err: err}
+}
+
+func lengthError(op, argument string, length, expected int) error {
+	return &Error{Op: op, Err: ErrLengthMismatch{Argument: argument, Length: length, Expected: expected}}
+}
+
+// checkCellBatchLengths checks that the arguments of a cell batch of op have
+// the same lengths.
+func checkCellBatchLengths(op string, commitmentsBytes []Bytes48, cellIndices []uint64, cells []Cell, proofsBytes []Bytes48) error {
+	switch {
+	case len(commitmentsBytes) != len(cells):
+		return lengthError(op, "commitments", len(commitmentsBytes), len(cells))
+	case len(cellIndices) != len(cells):
+		return lengthError(op, "cellIndices", len(cellIndices), len(cells))
+	case len(proofsBytes) != len(cells):
+		return lengthError(op, "proofs", len(proofsBytes), len(cells))
+	}
+	return nil
+}
+
+// checkBlobBatchLengths checks that the arguments of a blob batch of op have
+// the same lengths.
+func checkBlobBatchLengths(op string, blobs []Blob, commitmentsBytes, proofsBytes []Bytes48) error {
+	switch {
+	case len(commitmentsBytes) != len(blobs):
+		return lengthError(op, "commitments", len(commitmentsBytes), len(blobs))
+	case len(proofsBytes) != len(blobs):
+		return lengthError(op, "proofs", len(proofsBytes), len(blobs))
+	}
+	return nil
+}
+
+// checkFieldElements checks the field elements of data, the index-th blob or
+// cell of a batch.
+func checkFieldElements(argument string, index int, data []byte) error {
+	for i := 0; i < len(data)/BytesPerFieldElement; i++ {
+		element := data[i*BytesPerFieldElement : (i+1)*BytesPerFieldElement]
+		if bytes.Compare(element, BLSModulus[:]) >= 0 {
+			return ErrInvalidFieldElement{Argument: argument, Index: index, Element: i}
+		}
+	}
+	return nil
+}
+
+func checkScalar(argument string, b *Bytes32) error {
+	if bytes.Compare(b[:], BLSModulus[:]) >= 0 {
+		return ErrInvalidFieldElement{Argument: argument}
+	}
+	return nil
+}
+
+func checkPoint(argument string, index int, b *Bytes48) error {
+	var point C.g1_t
+	if C.bytes_to_kzg_commitment(&point, (*C.Bytes48)(unsafe.Pointer(b))) != C.C_KZG_OK {
+		return ErrInvalidPoint{Argument: argument, Index: index}
+	}
+	return nil
+}
+
+// firstError returns the first of the checks to fail.
+func firstError(checks ...func() error) error {
+	for _, check := range checks {
+		if err := check(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func diagnoseBlobBatch(blobs []Blob, commitmentsBytes, proofsBytes []Bytes48) error {
+	for i := range blobs {
+		err := firstError(
+			func() error { return checkFieldElements("blob", i, blobs[i][:]) },
+			func() error { return checkPoint("commitment", i, &commitmentsBytes[i]) },
+			func() error { return checkPoint("proof", i, &proofsBytes[i]) })
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func diagnoseCellBatch(commitmentsBytes []Bytes48, cellIndices []uint64, cells []Cell, proofsBytes []Bytes48) error {
+	for i := range cells {
+		if cellIndices[i] >= CellsPerExtBlob {
+			return ErrInvalidCellIndex{Index: i, CellIndex: cellIndices[i]}
+		}
+		err := firstError(
+			func() error { return checkPoint("commitment", i, &commitmentsBytes[i]) },
+			func() error { return checkFieldElements("cell", i, cells[i][:]) },
+			func() error { return checkPoint("proof", i, &proofsBytes[i]) })
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+CheckCellKZGProofBatch checks the arguments of VerifyCellKZGProofBatch without
+verifying the proofs: their lengths, the cell indices, that the commitments
+and proofs are valid points, and that the cells hold canonical field elements.
+It returns the error VerifyCellKZGProofBatch would return for them, or nil, so
+malformed inputs can be rejected before they are batched with others.
+*/
+func CheckCellKZGProofBatch(commitmentsBytes []Bytes48, cellIndices []uint64, cells []Cell, proofsBytes []Bytes48) error {
+	const op = "CheckCellKZGProofBatch"
+	if err := checkCellBatchLengths(op, commitmentsBytes, cellIndices, cells, proofsBytes); err != nil {
+		return err
+	}
+	if err := diagnoseCellBatch(commitmentsBytes, cellIndices, cells, proofsBytes); err != nil {
+		return &Error{Op: op, Err: err}
+	}
+	return nil
+}
+
+func diagnoseRecovery(cellIndices []uint64, cells []Cell) error {
+	var seen [CellsPerExtBlob]bool
+	for i, cellIndex := range cellIndices {
+		if cellIndex >= CellsPerExtBlob || seen[cellIndex] {
+			return ErrInvalidCellIndex{Index: i, CellIndex: cellIndex}
+		}
+		seen[cellIndex] = true
+		if err := checkFieldElements("cell", i, cells[i][:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package ckzg4844
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrors(t *testing.T) {
+	defer SetBatchChunking(BatchChunking{})
+
+	blob := selfTestBlob()
+	commitment, err := BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+	proof, err := ComputeBlobKZGProof(blob, Bytes48(commitment))
+	require.NoError(t, err)
+	blobs := []Blob{*blob, *blob, *blob, *blob}
+	commitments := []Bytes48{Bytes48(commitment), Bytes48(commitment), Bytes48(commitment), Bytes48(commitment)}
+	proofs := []Bytes48{Bytes48(proof), Bytes48(proof), Bytes48(proof), Bytes48(proof)}
+
+	// A non-canonical field element in the third blob, found in one chunk
+	// and in shards of the batch.
+	blobs[2][5*BytesPerFieldElement] = 0xff
+	for _, chunking := range []BatchChunking{{}, {MemoryBudget: blobBatchItemBytes}} {
+		SetBatchChunking(chunking)
+		_, err = VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+		require.ErrorIs(t, err, ErrBadArgs)
+		var opErr *Error
+		require.ErrorAs(t, err, &opErr)
+		require.Equal(t, "VerifyBlobKZGProofBatch", opErr.Op)
+		var fieldErr ErrInvalidFieldElement
+		require.ErrorAs(t, err, &fieldErr)
+		require.Equal(t, ErrInvalidFieldElement{Argument: "blob", Index: 2, Element: 5}, fieldErr)
+	}
+	SetBatchChunking(BatchChunking{})
+	_, err = VerifyBlobKZGProofBatchParallel(blobs, commitments, proofs, ParallelOptions{Workers: 4})
+	var fieldErr ErrInvalidFieldElement
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, 2, fieldErr.Index)
+	blobs[2] = *blob
+
+	// An invalid proof.
+	proofs[3] = Bytes48{}
+	_, err = VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+	var pointErr ErrInvalidPoint
+	require.ErrorAs(t, err, &pointErr)
+	require.Equal(t, ErrInvalidPoint{Argument: "proof", Index: 3}, pointErr)
+	proofs[3] = Bytes48(proof)
+
+	// Mismatched lengths.
+	_, err = VerifyBlobKZGProofBatch(blobs, commitments[:3], proofs)
+	var lengthErr ErrLengthMismatch
+	require.ErrorAs(t, err, &lengthErr)
+	require.Equal(t, ErrLengthMismatch{Argument: "commitments", Length: 3, Expected: 4}, lengthErr)
+
+	// An out of range cell index.
+	cells, cellProofs, err := ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+	_, err = VerifyCellKZGProofBatch(
+		[]Bytes48{Bytes48(commitment), Bytes48(commitment)},
+		[]uint64{0, CellsPerExtBlob},
+		[]Cell{cells[0], cells[1]},
+		[]Bytes48{Bytes48(cellProofs[0]), Bytes48(cellProofs[1])})
+	var indexErr ErrInvalidCellIndex
+	require.ErrorAs(t, err, &indexErr)
+	require.Equal(t, ErrInvalidCellIndex{Index: 1, CellIndex: CellsPerExtBlob}, indexErr)
+
+	// The same arguments checked without verifying.
+	cellCommitments := []Bytes48{Bytes48(commitment), Bytes48(commitment)}
+	cellIndices := []uint64{0, 1}
+	cellsToCheck := []Cell{cells[0], cells[1]}
+	cellProofsToCheck := []Bytes48{Bytes48(cellProofs[0]), Bytes48(cellProofs[1])}
+	require.NoError(t, CheckCellKZGProofBatch(cellCommitments, cellIndices, cellsToCheck, cellProofsToCheck))
+	cellIndices[1] = CellsPerExtBlob
+	err = CheckCellKZGProofBatch(cellCommitments, cellIndices, cellsToCheck, cellProofsToCheck)
+	require.ErrorAs(t, err, &indexErr)
+	require.Equal(t, ErrInvalidCellIndex{Index: 1, CellIndex: CellsPerExtBlob}, indexErr)
+	cellIndices[1] = 1
+	cellProofsToCheck[0] = Bytes48{}
+	err = CheckCellKZGProofBatch(cellCommitments, cellIndices, cellsToCheck, cellProofsToCheck)
+	require.ErrorAs(t, err, &pointErr)
+	require.Equal(t, ErrInvalidPoint{Argument: "proof"}, pointErr)
+	err = CheckCellKZGProofBatch(cellCommitments, cellIndices[:1], cellsToCheck, cellProofsToCheck)
+	require.ErrorAs(t, err, &lengthErr)
+
+	// A repeated cell index in recovery.
+	cellIndices, partialCells := getPartialCells(cells, 2)
+	cellIndices[1] = cellIndices[0]
+	_, _, err = RecoverCellsAndKZGProofs(cellIndices, partialCells)
+	require.ErrorAs(t, err, &indexErr)
+	require.Equal(t, 1, indexErr.Index)
+
+	// A non-canonical evaluation point.
+	var z Bytes32
+	for i := range z {
+		z[i] = 0xff
+	}
+	_, _, err = ComputeKZGProof(blob, z)
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, ErrInvalidFieldElement{Argument: "z"}, fieldErr)
+	require.Equal(t, "ComputeKZGProof: bad arguments: z isn't a canonical field element", err.Error())
+
+	// The C library failing otherwise is left as is.
+	err = opError("Op", ErrMalloc, func() error { return errors.New("unused") })
+	require.ErrorIs(t, err, ErrMalloc)
+}
+
+func TestBLSModulus(t *testing.T) {
+	modulus, _ := new(big.Int).SetString("52435875175126190479447740508185965837690552500527637822603658699938581184513", 10)
+	require.Equal(t, modulus, new(big.Int).SetBytes(BLSModulus[:]))
+
+	z := BLSModulus
+	require.Error(t, checkScalar("z", &z))
+	z[len(z)-1]--
+	require.NoError(t, checkScalar("z", &z))
+}
+
+func TestLengthMismatchOps(t *testing.T) {
+	ctx := context.Background()
+	g := new(testGroup)
+	blobs := make([]Blob, 2)
+	cells := make([]Cell, 2)
+	one := make([]Bytes48, 1)
+	two := make([]Bytes48, 2)
+	pool := NewVerifierPool(VerifierPoolConfig{Workers: 1})
+	defer pool.Close()
+	for op, call := range map[string]func() error{
+		"GoBlobToKZGCommitments": func() error {
+			return GoBlobToKZGCommitments(ctx, g, blobs, make([]KZGCommitment, 1))
+		},
+		"GoComputeBlobKZGProofs": func() error {
+			return GoComputeBlobKZGProofs(ctx, g, blobs, two, make([]KZGProof, 1))
+		},
+		"GoVerifyBlobKZGProofBatch": func() error {
+			return GoVerifyBlobKZGProofBatch(ctx, g, blobs, one, two, 1)
+		},
+		"GoVerifyCellKZGProofBatch": func() error {
+			return GoVerifyCellKZGProofBatch(ctx, g, two, []uint64{0, 1}, cells, one, 1)
+		},
+		"VerifyBlobKZGProofBatch": func() error {
+			_, err := NewVerificationCache(1, 0).VerifyBlobKZGProofBatch(blobs, one, two)
+			return err
+		},
+		"SubmitCells": func() error {
+			return pool.SubmitCells(two, []uint64{0}, cells, two, func(bool, error) {})
+		},
+	} {
+		err := call()
+		var lengthErr ErrLengthMismatch
+		require.ErrorAs(t, err, &lengthErr, op)
+		require.ErrorIs(t, err, ErrBadArgs, op)
+		var opErr *Error
+		require.ErrorAs(t, err, &opErr, op)
+		require.Equal(t, op, opErr.Op)
+	}
+	require.NoError(t, g.Wait())
+}
@@ -0,0 +1,96 @@
+package ckzg4844
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+/*
+FaultInjector makes operations fail on purpose, so that software built on these
+bindings can test its error paths (penalizing peers, dropping blocks, retrying)
+without crafting cryptographically invalid data. It is a testing hook; never
+install one in production.
+
+Operations are identified by the name of the Go function, for example
+"BlobToKZGCommitment" or "VerifyCellKZGProofBatch". The ...Into variants share
+the name of the function they are a variant of.
+*/
+type FaultInjector struct {
+	mu       sync.Mutex
+	calls    map[string]int
+	failures map[string]map[int]error
+	rejected map[Bytes48]bool
+}
+
+var faultInjector atomic.Pointer[FaultInjector]
+
+// NewFaultInjector returns a FaultInjector which doesn't inject any faults yet.
+func NewFaultInjector() *FaultInjector {
+	return &FaultInjector{
+		calls:    map[string]int{},
+		failures: map[string]map[int]error{},
+		rejected: map[Bytes48]bool{},
+	}
+}
+
+// SetFaultInjector installs fi for all operations. Passing nil removes the
+// current fault injector.
+func SetFaultInjector(fi *FaultInjector) {
+	faultInjector.Store(fi)
+}
+
+// FailNthCall makes the nth call (starting at 1) to op return err. If err is
+// nil, ErrError is returned.
+func (fi *FaultInjector) FailNthCall(op string, n int, err error) {
+	if err == nil {
+		err = ErrError
+	}
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	if fi.failures[op] == nil {
+		fi.failures[op] = map[int]error{}
+	}
+	fi.failures[op][n] = err
+}
+
+// RejectCommitment makes every verification involving commitment return false.
+func (fi *FaultInjector) RejectCommitment(commitment Bytes48) {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	fi.rejected[commitment] = true
+}
+
+// Calls returns how many times op has been called since fi was created.
+func (fi *FaultInjector) Calls(op string) int {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	return fi.calls[op]
+}
+
+// injectedError counts a call to op and returns the error to inject, if any.
+func injectedError(op string) error {
+	fi := faultInjector.Load()
+	if fi == nil {
+		return nil
+	}
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	fi.calls[op]++
+	return fi.failures[op][fi.calls[op]]
+}
+
+// injectedRejection reports whether a verification of commitments should fail.
+func injectedRejection(commitments ...Bytes48) bool {
+	fi := faultInjector.Load()
+	if fi == nil {
+		return false
+	}
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	for _, commitment := range commitments {
+		if fi.rejected[commitment] {
+			return true
+		}
+	}
+	return false
+}
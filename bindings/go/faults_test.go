@@ -0,0 +1,58 @@
+package ckzg4844
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultInjectorFailNthCall(t *testing.T) {
+	fi := NewFaultInjector()
+	errCustom := errors.New("custom")
+	fi.FailNthCall("BlobToKZGCommitment", 2, nil)
+	fi.FailNthCall("BlobToKZGCommitment", 3, errCustom)
+	SetFaultInjector(fi)
+	defer SetFaultInjector(nil)
+
+	blob := selfTestBlob()
+	_, err := BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+	_, err = BlobToKZGCommitment(blob)
+	require.ErrorIs(t, err, ErrError)
+	_, err = BlobToKZGCommitment(blob)
+	require.ErrorIs(t, err, errCustom)
+	_, err = BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+	require.Equal(t, 4, fi.Calls("BlobToKZGCommitment"))
+}
+
+func TestFaultInjectorRejectCommitment(t *testing.T) {
+	blob := selfTestBlob()
+	commitment, err := BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+	proof, err := ComputeBlobKZGProof(blob, Bytes48(commitment))
+	require.NoError(t, err)
+	cells, cellProofs, err := ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+
+	fi := NewFaultInjector()
+	fi.RejectCommitment(Bytes48(commitment))
+	SetFaultInjector(fi)
+	defer SetFaultInjector(nil)
+
+	ok, err := VerifyBlobKZGProof(blob, Bytes48(commitment), Bytes48(proof))
+	require.NoError(t, err)
+	require.False(t, ok)
+	ok, err = VerifyBlobKZGProofBatch([]Blob{*blob}, []Bytes48{Bytes48(commitment)}, []Bytes48{Bytes48(proof)})
+	require.NoError(t, err)
+	require.False(t, ok)
+	ok, err = VerifyCellKZGProofBatch([]Bytes48{Bytes48(commitment)}, []uint64{0}, []Cell{cells[0]}, []Bytes48{Bytes48(cellProofs[0])})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	SetFaultInjector(nil)
+	ok, err = VerifyBlobKZGProof(blob, Bytes48(commitment), Bytes48(proof))
+	require.NoError(t, err)
+	require.True(t, ok)
+}
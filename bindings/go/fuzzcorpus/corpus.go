@@ -0,0 +1,134 @@
+// Package fuzzcorpus contains utilities for maintaining fuzzing corpora for
+// the bindings: reading inputs in Go's corpus file format or as raw bytes,
+// deduplicating and minimizing crash inputs, and replaying a corpus through
+// every unmarshal and verify entry point.
+package fuzzcorpus
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// goCorpusHeader is the first line of files in Go's native fuzzing corpus.
+const goCorpusHeader = "go test fuzz v1\n"
+
+var ErrUnsupportedInput = errors.New("unsupported corpus file")
+
+/*
+DecodeInput decodes a corpus file. Files in Go's corpus format must hold a
+single []byte value, like:
+
+	go test fuzz v1
+	[]byte("\x00\x01")
+
+Anything else, such as libFuzzer or cargo-fuzz corpora, is treated as raw bytes.
+*/
+func DecodeInput(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte(goCorpusHeader)) {
+		return data, nil
+	}
+	value := bytes.TrimSpace(data[len(goCorpusHeader):])
+	if !bytes.HasPrefix(value, []byte("[]byte(")) || !bytes.HasSuffix(value, []byte(")")) {
+		return nil, fmt.Errorf("%w: expected a single []byte value", ErrUnsupportedInput)
+	}
+	literal := value[len("[]byte(") : len(value)-1]
+	s, err := strconv.Unquote(string(literal))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedInput, err)
+	}
+	return []byte(s), nil
+}
+
+// EncodeInput encodes input in Go's corpus format.
+func EncodeInput(input []byte) []byte {
+	return []byte(goCorpusHeader + "[]byte(" + strconv.Quote(string(input)) + ")\n")
+}
+
+// ReadInput reads and decodes the corpus file at path.
+func ReadInput(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeInput(data)
+}
+
+// inputFiles returns the regular files in dir, sorted by name.
+func inputFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Dedupe removes files in dir whose decoded input is identical to that of an
+// earlier file (by name), and returns the paths of the removed files.
+func Dedupe(dir string) ([]string, error) {
+	paths, err := inputFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[[32]byte]bool{}
+	var removed []string
+	for _, path := range paths {
+		input, err := ReadInput(path)
+		if err != nil {
+			return removed, fmt.Errorf("%s: %w", path, err)
+		}
+		hash := sha256.Sum256(input)
+		if !seen[hash] {
+			seen[hash] = true
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return removed, err
+		}
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
+
+/*
+Minimize returns the smallest input it can find, by repeatedly removing chunks
+of input, for which fails still returns true. It is a simplified form of delta
+debugging; fails must be deterministic, and fails(input) must be true.
+*/
+func Minimize(input []byte, fails func([]byte) bool) []byte {
+	current := append([]byte{}, input...)
+	for chunk := len(current) / 2; chunk >= 1; chunk /= 2 {
+		for start := 0; start+chunk <= len(current); {
+			candidate := append(append([]byte{}, current[:start]...), current[start+chunk:]...)
+			if fails(candidate) {
+				current = candidate
+				continue
+			}
+			start += chunk
+		}
+	}
+	return current
+}
+
+// WriteInput writes input to dir in Go's corpus format, named by its hash like
+// the go tool does, and returns the path.
+func WriteInput(dir string, input []byte) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(input)
+	path := filepath.Join(dir, fmt.Sprintf("%x", hash[:8]))
+	return path, os.WriteFile(path, EncodeInput(input), 0o644)
+}
@@ -0,0 +1,104 @@
+package fuzzcorpus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/ckzgtest"
+)
+
+func TestDecodeInput(t *testing.T) {
+	input := []byte("\x00\x01hello\xff")
+	decoded, err := DecodeInput(EncodeInput(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded, input) {
+		t.Fatalf("expected %q, got %q", input, decoded)
+	}
+
+	raw := []byte("raw libFuzzer input")
+	decoded, err = DecodeInput(raw)
+	if err != nil || !bytes.Equal(decoded, raw) {
+		t.Fatalf("raw input was not passed through: %q, %v", decoded, err)
+	}
+
+	if _, err := DecodeInput([]byte(goCorpusHeader + "string(\"x\")\n")); err == nil {
+		t.Fatal("expected an error for a non-[]byte value")
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string][]byte{
+		"a": EncodeInput([]byte("same")),
+		"b": []byte("same"),
+		"c": []byte("different"),
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	removed, err := Dedupe(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 1 || filepath.Base(removed[0]) != "b" {
+		t.Fatalf("unexpected removed files: %v", removed)
+	}
+}
+
+func TestMinimize(t *testing.T) {
+	input := []byte("aaaaXbbbbYcccc")
+	fails := func(b []byte) bool {
+		return bytes.Contains(b, []byte("X")) && bytes.Contains(b, []byte("Y"))
+	}
+	if minimized := Minimize(input, fails); string(minimized) != "XY" {
+		t.Fatalf("expected XY, got %q", minimized)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	ckzgtest.LoadTrustedSetup(t)
+	dir := t.TempDir()
+	blob := ckzgtest.RandomBlob(0)
+	// Half of the cells of the blob with their indices, which recover it.
+	cells, _, err := ckzg4844.ComputeCellsAndKZGProofs(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recovery := []byte{ckzg4844.CellsPerExtBlob / 2}
+	for i := 0; i < ckzg4844.CellsPerExtBlob/2; i++ {
+		recovery = binary.LittleEndian.AppendUint64(recovery, uint64(i))
+	}
+	for i := 0; i < ckzg4844.CellsPerExtBlob/2; i++ {
+		recovery = append(recovery, cells[i][:]...)
+	}
+	for _, input := range [][]byte{nil, []byte("0x1234"), []byte(`"0x1234"`), blob[:], recovery, bytes.Repeat([]byte{0xff}, 5000)} {
+		if _, err := WriteInput(dir, input); err != nil {
+			t.Fatal(err)
+		}
+	}
+	failures, err := Replay(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failures) != 0 {
+		t.Fatalf("unexpected failures: %v", failures)
+	}
+}
+
+func FuzzReplayInput(f *testing.F) {
+	ckzgtest.LoadTrustedSetup(f)
+	f.Add([]byte{})
+	f.Add([]byte("0x00"))
+	f.Fuzz(func(t *testing.T, input []byte) {
+		if err := ReplayInput(input); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
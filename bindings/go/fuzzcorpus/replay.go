@@ -0,0 +1,200 @@
+package fuzzcorpus
+
+import (
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+)
+
+// reader carves fixed-size values out of a fuzz input, padding with zeros once
+// the input is exhausted.
+type reader struct {
+	data []byte
+}
+
+func (r *reader) read(out []byte) {
+	n := copy(out, r.data)
+	r.data = r.data[n:]
+	for i := n; i < len(out); i++ {
+		out[i] = 0
+	}
+}
+
+// count reads the length of a batch of at most limit items.
+func (r *reader) count(limit int) int {
+	var b [1]byte
+	r.read(b[:])
+	return int(b[0]) % (limit + 1)
+}
+
+func (r *reader) uint64() uint64 {
+	var b [8]byte
+	r.read(b[:])
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+// PanicError is returned by ReplayInput when an entry point panicked.
+type PanicError struct {
+	EntryPoint string
+	Value      any
+	Stack      []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s panicked: %v", e.EntryPoint, e.Value)
+}
+
+// call runs fn, converting a panic into a PanicError.
+func call(entryPoint string, fn func()) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{EntryPoint: entryPoint, Value: v, Stack: debug.Stack()}
+		}
+	}()
+	fn()
+	return nil
+}
+
+// unmarshaler is implemented by every type which can be unmarshaled.
+type unmarshaler interface {
+	encoding.TextUnmarshaler
+	json.Unmarshaler
+}
+
+// entryPoint is an entry point of the bindings called with a fuzz input.
+type entryPoint struct {
+	name string
+	fn   func()
+}
+
+/*
+ReplayInput feeds input to every unmarshal and verify entry point, and to the
+cell computation and recovery. The input is passed as-is to the UnmarshalText
+and UnmarshalJSON methods, and carved into arguments for the other functions,
+with a leading count byte for the batches. Errors returned by the bindings are
+expected for most inputs and are ignored; only panics are reported. A trusted
+setup must be loaded.
+
+Crashes inside the C library can't be recovered from and will still take down
+the process, which is what a fuzzer needs to see.
+*/
+func ReplayInput(input []byte) error {
+	var entryPoints []entryPoint
+	for _, value := range []struct {
+		name string
+		new  func() unmarshaler
+	}{
+		{"Bytes32", func() unmarshaler { return new(ckzg4844.Bytes32) }},
+		{"Bytes48", func() unmarshaler { return new(ckzg4844.Bytes48) }},
+		{"KZGCommitment", func() unmarshaler { return new(ckzg4844.KZGCommitment) }},
+		{"KZGProof", func() unmarshaler { return new(ckzg4844.KZGProof) }},
+		{"Blob", func() unmarshaler { return new(ckzg4844.Blob) }},
+		{"Cell", func() unmarshaler { return new(ckzg4844.Cell) }},
+	} {
+		value := value
+		entryPoints = append(entryPoints,
+			entryPoint{value.name + ".UnmarshalText", func() { _ = value.new().UnmarshalText(input) }},
+			entryPoint{value.name + ".UnmarshalJSON", func() { _ = value.new().UnmarshalJSON(input) }})
+	}
+	entryPoints = append(entryPoints, []entryPoint{
+		{"VerifyKZGProof", func() {
+			r := reader{input}
+			var commitment, proof ckzg4844.Bytes48
+			var z, y ckzg4844.Bytes32
+			r.read(commitment[:])
+			r.read(z[:])
+			r.read(y[:])
+			r.read(proof[:])
+			_, _ = ckzg4844.VerifyKZGProof(commitment, z, y, proof)
+		}},
+		{"VerifyBlobKZGProof", func() {
+			r := reader{input}
+			var commitment, proof ckzg4844.Bytes48
+			blob := new(ckzg4844.Blob)
+			r.read(commitment[:])
+			r.read(proof[:])
+			r.read(blob[:])
+			_, _ = ckzg4844.VerifyBlobKZGProof(blob, commitment, proof)
+		}},
+		{"VerifyBlobKZGProofBatch", func() {
+			r := reader{input}
+			blobs := make([]ckzg4844.Blob, 1)
+			commitments := make([]ckzg4844.Bytes48, 1)
+			proofs := make([]ckzg4844.Bytes48, 1)
+			r.read(commitments[0][:])
+			r.read(proofs[0][:])
+			r.read(blobs[0][:])
+			_, _ = ckzg4844.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+		}},
+		{"VerifyCellKZGProofBatch", func() {
+			r := reader{input}
+			n := r.count(ckzg4844.CellsPerExtBlob)
+			commitments := make([]ckzg4844.Bytes48, n)
+			cellIndices := make([]uint64, n)
+			cells := make([]ckzg4844.Cell, n)
+			proofs := make([]ckzg4844.Bytes48, n)
+			for i := 0; i < n; i++ {
+				r.read(commitments[i][:])
+				r.read(proofs[i][:])
+				cellIndices[i] = r.uint64()
+				r.read(cells[i][:])
+			}
+			_, _ = ckzg4844.VerifyCellKZGProofBatch(commitments, cellIndices, cells, proofs)
+		}},
+		{"ComputeCellsAndKZGProofs", func() {
+			r := reader{input}
+			blob := new(ckzg4844.Blob)
+			r.read(blob[:])
+			_, _, _ = ckzg4844.ComputeCellsAndKZGProofs(blob)
+		}},
+		{"RecoverCellsAndKZGProofs", func() {
+			r := reader{input}
+			n := r.count(ckzg4844.CellsPerExtBlob)
+			cellIndices := make([]uint64, n)
+			for i := range cellIndices {
+				cellIndices[i] = r.uint64()
+			}
+			cells := make([]ckzg4844.Cell, n)
+			for i := range cells {
+				r.read(cells[i][:])
+			}
+			_, _, _ = ckzg4844.RecoverCellsAndKZGProofs(cellIndices, cells)
+		}},
+	}...)
+	for _, entryPoint := range entryPoints {
+		if err := call(entryPoint.name, entryPoint.fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReplayResult is the outcome of replaying one corpus file.
+type ReplayResult struct {
+	Path string
+	Err  error
+}
+
+// Replay replays every file in dir through ReplayInput and returns the files
+// which failed to decode or made an entry point panic.
+func Replay(dir string) ([]ReplayResult, error) {
+	paths, err := inputFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	var failures []ReplayResult
+	for _, path := range paths {
+		input, err := ReadInput(path)
+		if err == nil {
+			err = ReplayInput(input)
+		}
+		if err != nil {
+			failures = append(failures, ReplayResult{Path: path, Err: err})
+		}
+	}
+	return failures, nil
+}
@@ -0,0 +1,122 @@
+package ckzg4844
+
+import (
+	"context"
+)
+
+/*
+Group runs functions concurrently. *errgroup.Group from golang.org/x/sync
+implements it; use its SetLimit method to bound the parallelism.
+
+The Go... helpers below schedule their work on a Group owned by the caller
+instead of starting goroutines of their own, so that KZG work is part of the
+caller's concurrency structure: it is bounded by the group's limit, and it
+stops early when the group's context is canceled. The caller waits for the
+results with the group's Wait method.
+*/
+type Group interface {
+	Go(f func() error)
+}
+
+// goChunks schedules f on g for each chunk of n items, skipping the work once
+// ctx is done.
+func goChunks(ctx context.Context, g Group, n, chunkSize int, f func(start, end int) error) {
+	if chunkSize <= 0 {
+		chunkSize = 1
+	}
+	for start := 0; start < n; start += chunkSize {
+		start, end := start, start+chunkSize
+		if end > n {
+			end = n
+		}
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			return f(start, end)
+		})
+	}
+}
+
+/*
+GoBlobToKZGCommitments schedules computing the commitment to each blob on g,
+storing the commitment to blobs[i] in commitments[i]. The commitments are only
+valid once g's Wait method returned nil.
+*/
+func GoBlobToKZGCommitments(ctx context.Context, g Group, blobs []Blob, commitments []KZGCommitment) error {
+	if len(commitments) != len(blobs) {
+		return lengthError("GoBlobToKZGCommitments", "commitments", len(commitments), len(blobs))
+	}
+	goChunks(ctx, g, len(blobs), 1, func(i, _ int) error {
+		commitment, err := BlobToKZGCommitment(&blobs[i])
+		commitments[i] = commitment
+		return err
+	})
+	return nil
+}
+
+/*
+GoComputeBlobKZGProofs schedules computing the proof of each blob on g, storing
+the proof of blobs[i] in proofs[i]. The proofs are only valid once g's Wait
+method returned nil.
+*/
+func GoComputeBlobKZGProofs(ctx context.Context, g Group, blobs []Blob, commitmentsBytes []Bytes48, proofs []KZGProof) error {
+	switch {
+	case len(commitmentsBytes) != len(blobs):
+		return lengthError("GoComputeBlobKZGProofs", "commitments", len(commitmentsBytes), len(blobs))
+	case len(proofs) != len(blobs):
+		return lengthError("GoComputeBlobKZGProofs", "proofs", len(proofs), len(blobs))
+	}
+	goChunks(ctx, g, len(blobs), 1, func(i, _ int) error {
+		proof, err := ComputeBlobKZGProof(&blobs[i], commitmentsBytes[i])
+		proofs[i] = proof
+		return err
+	})
+	return nil
+}
+
+/*
+GoVerifyBlobKZGProofBatch splits the batch into chunks of chunkSize blobs and
+schedules verifying each chunk on g. A chunk which doesn't verify makes its
+function return ErrVerificationFailed, so g's Wait method returns nil only if
+the whole batch verifies.
+*/
+func GoVerifyBlobKZGProofBatch(ctx context.Context, g Group, blobs []Blob, commitmentsBytes, proofsBytes []Bytes48, chunkSize int) error {
+	if err := checkBlobBatchLengths("GoVerifyBlobKZGProofBatch", blobs, commitmentsBytes, proofsBytes); err != nil {
+		return err
+	}
+	goChunks(ctx, g, len(blobs), chunkSize, func(start, end int) error {
+		ok, err := VerifyBlobKZGProofBatch(blobs[start:end], commitmentsBytes[start:end], proofsBytes[start:end])
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrVerificationFailed
+		}
+		return nil
+	})
+	return nil
+}
+
+/*
+GoVerifyCellKZGProofBatch splits the batch into chunks of chunkSize cells and
+schedules verifying each chunk on g. A chunk which doesn't verify makes its
+function return ErrVerificationFailed, so g's Wait method returns nil only if
+the whole batch verifies.
+*/
+func GoVerifyCellKZGProofBatch(ctx context.Context, g Group, commitmentsBytes []Bytes48, cellIndices []uint64, cells []Cell, proofsBytes []Bytes48, chunkSize int) error {
+	if err := checkCellBatchLengths("GoVerifyCellKZGProofBatch", commitmentsBytes, cellIndices, cells, proofsBytes); err != nil {
+		return err
+	}
+	goChunks(ctx, g, len(cells), chunkSize, func(start, end int) error {
+		ok, err := VerifyCellKZGProofBatch(commitmentsBytes[start:end], cellIndices[start:end], cells[start:end], proofsBytes[start:end])
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrVerificationFailed
+		}
+		return nil
+	})
+	return nil
+}
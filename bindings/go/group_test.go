@@ -0,0 +1,75 @@
+package ckzg4844
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testGroup is a minimal errgroup.Group.
+type testGroup struct {
+	wg   sync.WaitGroup
+	once sync.Once
+	err  error
+}
+
+func (g *testGroup) Go(f func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := f(); err != nil {
+			g.once.Do(func() { g.err = err })
+		}
+	}()
+}
+
+func (g *testGroup) Wait() error {
+	g.wg.Wait()
+	return g.err
+}
+
+func TestGroupHelpers(t *testing.T) {
+	ctx := context.Background()
+	blobs := []Blob{*selfTestBlob(), {}, *selfTestBlob()}
+	blobs[2][31] = 1
+
+	g := &testGroup{}
+	commitments := make([]KZGCommitment, len(blobs))
+	require.NoError(t, GoBlobToKZGCommitments(ctx, g, blobs, commitments))
+	require.NoError(t, g.Wait())
+	commitmentsBytes := make([]Bytes48, len(blobs))
+	for i := range commitments {
+		expected, err := BlobToKZGCommitment(&blobs[i])
+		require.NoError(t, err)
+		require.Equal(t, expected, commitments[i])
+		commitmentsBytes[i] = Bytes48(commitments[i])
+	}
+
+	g = &testGroup{}
+	proofs := make([]KZGProof, len(blobs))
+	require.NoError(t, GoComputeBlobKZGProofs(ctx, g, blobs, commitmentsBytes, proofs))
+	require.NoError(t, g.Wait())
+	proofsBytes := make([]Bytes48, len(blobs))
+	for i := range proofs {
+		proofsBytes[i] = Bytes48(proofs[i])
+	}
+
+	g = &testGroup{}
+	require.NoError(t, GoVerifyBlobKZGProofBatch(ctx, g, blobs, commitmentsBytes, proofsBytes, 2))
+	require.NoError(t, g.Wait())
+
+	proofsBytes[2] = proofsBytes[0]
+	g = &testGroup{}
+	require.NoError(t, GoVerifyBlobKZGProofBatch(ctx, g, blobs, commitmentsBytes, proofsBytes, 2))
+	require.ErrorIs(t, g.Wait(), ErrVerificationFailed)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	g = &testGroup{}
+	require.NoError(t, GoBlobToKZGCommitments(canceled, g, blobs, commitments))
+	require.ErrorIs(t, g.Wait(), context.Canceled)
+
+	require.ErrorIs(t, GoVerifyCellKZGProofBatch(ctx, g, commitmentsBytes, nil, nil, nil, 1), ErrBadArgs)
+}
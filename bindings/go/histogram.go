@@ -0,0 +1,190 @@
+package ckzg4844
+
+import (
+	"math"
+	"math/bits"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Histograms have eight buckets per power of two, so a recorded latency is off
+// by at most 12.5%.
+const (
+	histogramSubBucketBits = 3
+	histogramSubBuckets    = 1 << histogramSubBucketBits
+	histogramBuckets       = (64 - histogramSubBucketBits + 1) * histogramSubBuckets
+)
+
+var (
+	latencyHistograms     atomic.Bool
+	latencyHistogramsByOp sync.Map // string -> *latencyHistogram
+)
+
+/*
+EnableLatencyHistograms makes every KZG operation record how long its cgo call
+took in a per-operation histogram, see LatencyHistograms. Embedders can use
+them to notice slow or throttled hardware before latencies approach slot
+deadlines. It is disabled by default.
+*/
+func EnableLatencyHistograms(enabled bool) {
+	latencyHistograms.Store(enabled)
+}
+
+type latencyHistogram struct {
+	count   atomic.Uint64
+	sum     atomic.Uint64
+	min     atomic.Uint64
+	max     atomic.Uint64
+	buckets [histogramBuckets]atomic.Uint64
+}
+
+func newLatencyHistogram() *latencyHistogram {
+	h := &latencyHistogram{}
+	h.min.Store(math.MaxUint64)
+	return h
+}
+
+func histogramBucket(ns uint64) int {
+	if ns < histogramSubBuckets {
+		return int(ns)
+	}
+	exp := bits.Len64(ns) - 1
+	sub := (ns >> (exp - histogramSubBucketBits)) & (histogramSubBuckets - 1)
+	return (exp-histogramSubBucketBits+1)*histogramSubBuckets + int(sub)
+}
+
+// histogramBucketMax returns the largest latency in bucket i, in nanoseconds.
+func histogramBucketMax(i int) uint64 {
+	if i < histogramSubBuckets {
+		return uint64(i)
+	}
+	exp := i/histogramSubBuckets + histogramSubBucketBits - 1
+	sub := uint64(i % histogramSubBuckets)
+	width := uint64(1) << (exp - histogramSubBucketBits)
+	return (histogramSubBuckets+sub)*width + width - 1
+}
+
+func (h *latencyHistogram) record(d time.Duration) {
+	ns := uint64(0)
+	if d > 0 {
+		ns = uint64(d)
+	}
+	h.count.Add(1)
+	h.sum.Add(ns)
+	h.buckets[histogramBucket(ns)].Add(1)
+	for old := h.min.Load(); ns < old && !h.min.CompareAndSwap(old, ns); old = h.min.Load() {
+	}
+	for old := h.max.Load(); ns > old && !h.max.CompareAndSwap(old, ns); old = h.max.Load() {
+	}
+}
+
+func histogramFor(op string) *latencyHistogram {
+	if h, ok := latencyHistogramsByOp.Load(op); ok {
+		return h.(*latencyHistogram)
+	}
+	h, _ := latencyHistogramsByOp.LoadOrStore(op, newLatencyHistogram())
+	return h.(*latencyHistogram)
+}
+
+// LatencyBucket is a histogram bucket counting latencies up to Max which are
+// larger than the Max of the previous bucket.
+type LatencyBucket struct {
+	Max   time.Duration
+	Count uint64
+}
+
+// LatencyHistogram is a snapshot of the latencies recorded for an operation.
+type LatencyHistogram struct {
+	Operation string
+	Count     uint64
+	Sum       time.Duration
+	Min       time.Duration
+	Max       time.Duration
+	// Buckets holds the non-empty buckets, in increasing order.
+	Buckets []LatencyBucket
+}
+
+// Mean returns the average latency.
+func (h LatencyHistogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(h.Count)
+}
+
+// Quantile returns an upper bound of the q-quantile of the latencies, for
+// example Quantile(0.99) for the 99th percentile.
+func (h LatencyHistogram) Quantile(q float64) time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(h.Count)))
+	if rank == 0 {
+		rank = 1
+	}
+	var seen uint64
+	for _, bucket := range h.Buckets {
+		seen += bucket.Count
+		if seen >= rank {
+			if bucket.Max > h.Max {
+				return h.Max
+			}
+			return bucket.Max
+		}
+	}
+	return h.Max
+}
+
+func (h *latencyHistogram) snapshot(op string) LatencyHistogram {
+	s := LatencyHistogram{
+		Operation: op,
+		Count:     h.count.Load(),
+		Sum:       time.Duration(h.sum.Load()),
+		Max:       time.Duration(h.max.Load()),
+	}
+	if s.Count > 0 {
+		s.Min = time.Duration(h.min.Load())
+	}
+	for i := range h.buckets {
+		if count := h.buckets[i].Load(); count > 0 {
+			s.Buckets = append(s.Buckets, LatencyBucket{Max: time.Duration(histogramBucketMax(i)), Count: count})
+		}
+	}
+	return s
+}
+
+// LatencyHistograms returns snapshots of the latency histograms of all
+// operations which recorded latencies, sorted by operation name.
+func LatencyHistograms() []LatencyHistogram {
+	var snapshots []LatencyHistogram
+	latencyHistogramsByOp.Range(func(op, h any) bool {
+		snapshots = append(snapshots, h.(*latencyHistogram).snapshot(op.(string)))
+		return true
+	})
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Operation < snapshots[j].Operation
+	})
+	return snapshots
+}
+
+// ResetLatencyHistograms discards all recorded latencies.
+func ResetLatencyHistograms() {
+	latencyHistogramsByOp.Range(func(op, _ any) bool {
+		latencyHistogramsByOp.Delete(op)
+		return true
+	})
+}
+
+// instrument runs f, the cgo call of an operation of c, with the profiler
+// labels applied and records its latency, if enabled.
+func (c *Context) instrument(op string, batchSize int, f func()) {
+	if !latencyHistograms.Load() {
+		profileLabels(c.labels, op, batchSize, f)
+		return
+	}
+	start := time.Now()
+	profileLabels(c.labels, op, batchSize, f)
+	histogramFor(op).record(time.Since(start))
+}
@@ -0,0 +1,49 @@
+package ckzg4844
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogramBuckets(t *testing.T) {
+	for _, ns := range []uint64{0, 1, 7, 8, 9, 15, 16, 17, 1000, 123456789, 1 << 40, 1<<63 + 12345} {
+		i := histogramBucket(ns)
+		require.Less(t, i, histogramBuckets)
+		require.LessOrEqual(t, ns, histogramBucketMax(i), "ns=%d", ns)
+		if i > 0 {
+			require.Greater(t, ns, histogramBucketMax(i-1), "ns=%d", ns)
+		}
+	}
+	require.Equal(t, uint64(1<<64-1), histogramBucketMax(histogramBuckets-1))
+}
+
+func TestLatencyHistograms(t *testing.T) {
+	EnableLatencyHistograms(true)
+	defer EnableLatencyHistograms(false)
+	ResetLatencyHistograms()
+	defer ResetLatencyHistograms()
+
+	blob := selfTestBlob()
+	for i := 0; i < 3; i++ {
+		_, err := BlobToKZGCommitment(blob)
+		require.NoError(t, err)
+	}
+
+	histograms := LatencyHistograms()
+	require.Len(t, histograms, 1)
+	h := histograms[0]
+	require.Equal(t, "BlobToKZGCommitment", h.Operation)
+	require.Equal(t, uint64(3), h.Count)
+	require.Greater(t, h.Min, time.Duration(0))
+	require.LessOrEqual(t, h.Min, h.Mean())
+	require.LessOrEqual(t, h.Mean(), h.Max)
+	require.LessOrEqual(t, h.Quantile(0.5), h.Max)
+	require.Equal(t, h.Max, h.Quantile(1))
+	var count uint64
+	for _, bucket := range h.Buckets {
+		count += bucket.Count
+	}
+	require.Equal(t, h.Count, count)
+}
@@ -0,0 +1,86 @@
+package fixtures
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/json"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+)
+
+// expectedJSON is the committed ExpectedOutputs of the named fixtures. Run the
+// tests of this package with -update to regenerate it.
+//
+//go:embed expected.json
+var expectedJSON []byte
+
+// Expected holds the outputs of a named fixture. The cells and the cell proofs
+// are identified by the SHA-256 hash of their concatenation.
+type Expected struct {
+	Commitment     ckzg4844.KZGCommitment `json:"commitment"`
+	Proof          ckzg4844.KZGProof      `json:"proof"`
+	CellsHash      ckzg4844.Bytes32       `json:"cells_hash"`
+	CellProofsHash ckzg4844.Bytes32       `json:"cell_proofs_hash"`
+}
+
+// ExpectedOutputs holds the outputs of every version of every named fixture,
+// by versioned name like "zero-blob@v1", and the fingerprint of the trusted
+// setup which they were computed with.
+type ExpectedOutputs struct {
+	Setup    ckzg4844.Bytes32    `json:"setup"`
+	Fixtures map[string]Expected `json:"fixtures"`
+}
+
+// CommittedOutputs returns the committed outputs of the named fixtures, which
+// were computed with the mainnet trusted setup.
+func CommittedOutputs() (*ExpectedOutputs, error) {
+	outputs := new(ExpectedOutputs)
+	if err := json.Unmarshal(expectedJSON, outputs); err != nil {
+		return nil, err
+	}
+	return outputs, nil
+}
+
+// ComputeOutputs computes the outputs of the named fixtures with the loaded
+// trusted setup.
+func ComputeOutputs() (*ExpectedOutputs, error) {
+	outputs := &ExpectedOutputs{
+		Setup:    ckzg4844.TrustedSetupFingerprint(),
+		Fixtures: map[string]Expected{},
+	}
+	for _, name := range fixtureVersions() {
+		fixture, err := Fixture(name)
+		if err != nil {
+			return nil, err
+		}
+		outputs.Fixtures[name] = Expected{
+			Commitment:     fixture.Commitment,
+			Proof:          fixture.Proof,
+			CellsHash:      HashCells(fixture.Cells[:]),
+			CellProofsHash: HashProofs(fixture.CellProofs[:]),
+		}
+	}
+	return outputs, nil
+}
+
+// HashCells returns the SHA-256 hash of the concatenation of cells.
+func HashCells(cells []ckzg4844.Cell) ckzg4844.Bytes32 {
+	h := sha256.New()
+	for i := range cells {
+		h.Write(cells[i][:])
+	}
+	var hash ckzg4844.Bytes32
+	h.Sum(hash[:0])
+	return hash
+}
+
+// HashProofs returns the SHA-256 hash of the concatenation of proofs.
+func HashProofs(proofs []ckzg4844.KZGProof) ckzg4844.Bytes32 {
+	h := sha256.New()
+	for i := range proofs {
+		h.Write(proofs[i][:])
+	}
+	var hash ckzg4844.Bytes32
+	h.Sum(hash[:0])
+	return hash
+}
@@ -0,0 +1,85 @@
+// Package fixtures generates the blobs and fixtures shared by the tests, the
+// test helpers in ckzgtest, and the commands. Unlike ckzgtest, it doesn't
+// import testing, so commands can use it without linking the testing package.
+package fixtures
+
+import (
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+)
+
+var ErrNoRepoRoot = errors.New("not inside the c-kzg-4844 source tree")
+
+/*
+RepoRoot returns the root directory of the c-kzg-4844 source tree which
+contains the working directory. It is meant for tests, which go test runs in
+the directory of their package, both in a checkout and in the module cache.
+Unlike a path derived from the source file, it also works with -trimpath.
+*/
+func RepoRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "src", "ckzg.h")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ErrNoRepoRoot
+		}
+		dir = parent
+	}
+}
+
+// TrustedSetupPath returns the path to the mainnet trusted setup file.
+func TrustedSetupPath() (string, error) {
+	root, err := RepoRoot()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "src", "trusted_setup.txt"), nil
+}
+
+// ReferenceTestsDir returns the directory containing the reference tests.
+func ReferenceTestsDir() (string, error) {
+	root, err := RepoRoot()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "tests"), nil
+}
+
+// RandomFieldElement returns a random canonical field element.
+func RandomFieldElement(r *rand.Rand) ckzg4844.Bytes32 {
+	// Leaving the first byte as zero guarantees it's a canonical field element.
+	var fieldElement ckzg4844.Bytes32
+	r.Read(fieldElement[1:])
+	return fieldElement
+}
+
+// RandomBlob returns a blob of random canonical field elements derived from seed.
+func RandomBlob(seed int64) *ckzg4844.Blob {
+	r := rand.New(rand.NewSource(seed))
+	blob := new(ckzg4844.Blob)
+	for i := 0; i < ckzg4844.BytesPerBlob; i += ckzg4844.BytesPerFieldElement {
+		fieldElement := RandomFieldElement(r)
+		copy(blob[i:i+ckzg4844.BytesPerFieldElement], fieldElement[:])
+	}
+	return blob
+}
+
+// HalfCellIndices returns the indices of every other cell, which is the
+// minimum needed for recovery.
+func HalfCellIndices() []uint64 {
+	cellIndices := make([]uint64, 0, ckzg4844.CellsPerExtBlob/2)
+	for i := uint64(0); i < ckzg4844.CellsPerExtBlob; i += 2 {
+		cellIndices = append(cellIndices, i)
+	}
+	return cellIndices
+}
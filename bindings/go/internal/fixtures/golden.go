@@ -0,0 +1,143 @@
+package fixtures
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+)
+
+// GoldenSeed is the seed of the golden fixtures in testdata/golden, which are
+// computed with the mainnet trusted setup. Run the tests of this package with
+// -update to regenerate them.
+const GoldenSeed = 0
+
+// NumRandomGoldenFixtures is the number of random blobs in the golden fixtures.
+const NumRandomGoldenFixtures = 3
+
+// GoldenFixture holds a blob and everything derived from it.
+type GoldenFixture struct {
+	Name       string
+	Version    int
+	Blob       *ckzg4844.Blob
+	Commitment ckzg4844.KZGCommitment
+	Proof      ckzg4844.KZGProof
+	Cells      *[ckzg4844.CellsPerExtBlob]ckzg4844.Cell
+	CellProofs *[ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof
+}
+
+// NewGoldenFixture computes the commitment, proof, cells, and cell proofs for blob.
+func NewGoldenFixture(name string, blob *ckzg4844.Blob) (*GoldenFixture, error) {
+	commitment, err := ckzg4844.BlobToKZGCommitment(blob)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := ckzg4844.ComputeBlobKZGProof(blob, ckzg4844.Bytes48(commitment))
+	if err != nil {
+		return nil, err
+	}
+	fixture := &GoldenFixture{
+		Name:       name,
+		Version:    FixturesVersion,
+		Blob:       blob,
+		Commitment: commitment,
+		Proof:      proof,
+		Cells:      new([ckzg4844.CellsPerExtBlob]ckzg4844.Cell),
+		CellProofs: new([ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof),
+	}
+	if err := ckzg4844.ComputeCellsAndKZGProofsInto(fixture.Cells, fixture.CellProofs, blob); err != nil {
+		return nil, err
+	}
+	return fixture, nil
+}
+
+// GoldenFixtures returns the zero blob fixture followed by
+// NumRandomGoldenFixtures random blob fixtures derived from seed.
+func GoldenFixtures(seed int64) ([]*GoldenFixture, error) {
+	fixture, err := NewGoldenFixture("zero", new(ckzg4844.Blob))
+	if err != nil {
+		return nil, err
+	}
+	fixtures := []*GoldenFixture{fixture}
+	for i := 0; i < NumRandomGoldenFixtures; i++ {
+		fixture, err := NewGoldenFixture(fmt.Sprintf("random_%d", i), RandomBlob(seed+int64(i)))
+		if err != nil {
+			return nil, err
+		}
+		fixtures = append(fixtures, fixture)
+	}
+	return fixtures, nil
+}
+
+// hexLines formats each chunk of data as a 0x-prefixed hex line.
+func hexLines(data []byte, chunkSize int) string {
+	var sb strings.Builder
+	for i := 0; i < len(data); i += chunkSize {
+		sb.WriteString("0x")
+		sb.WriteString(hex.EncodeToString(data[i : i+chunkSize]))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+/*
+WriteGoldenFixtures writes the fixtures from GoldenFixtures to dir, one
+directory per fixture. Every file has one hex value per line (blobs are split
+into field elements), so the output is stable and a diff of two runs points
+directly at the values that changed. setup.txt holds the fingerprint of the
+trusted setup which the values were computed with:
+
+	dir/seed.txt
+	dir/setup.txt
+	dir/<name>/blob.txt
+	dir/<name>/commitment.txt
+	dir/<name>/proof.txt
+	dir/<name>/cells.txt
+	dir/<name>/cell_proofs.txt
+*/
+func WriteGoldenFixtures(dir string, seed int64) error {
+	fixtures, err := GoldenFixtures(seed)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "seed.txt"), []byte(fmt.Sprintf("%d\n", seed)), 0o644); err != nil {
+		return err
+	}
+	fingerprint := ckzg4844.TrustedSetupFingerprint()
+	if err := os.WriteFile(filepath.Join(dir, "setup.txt"), []byte(hexLines(fingerprint[:], len(fingerprint))), 0o644); err != nil {
+		return err
+	}
+	for _, fixture := range fixtures {
+		fixtureDir := filepath.Join(dir, fixture.Name)
+		if err := os.MkdirAll(fixtureDir, 0o755); err != nil {
+			return err
+		}
+		var cells, cellProofs []byte
+		for i := range fixture.Cells {
+			cells = append(cells, fixture.Cells[i][:]...)
+			cellProofs = append(cellProofs, fixture.CellProofs[i][:]...)
+		}
+		files := []struct {
+			name    string
+			content string
+		}{
+			{"blob.txt", hexLines(fixture.Blob[:], ckzg4844.BytesPerFieldElement)},
+			{"commitment.txt", hexLines(fixture.Commitment[:], ckzg4844.BytesPerCommitment)},
+			{"proof.txt", hexLines(fixture.Proof[:], ckzg4844.BytesPerProof)},
+			{"cells.txt", hexLines(cells, ckzg4844.BytesPerCell)},
+			{"cell_proofs.txt", hexLines(cellProofs, ckzg4844.BytesPerProof)},
+		}
+		for _, file := range files {
+			if err := os.WriteFile(filepath.Join(fixtureDir, file.name), []byte(file.content), 0o644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "regenerate the golden fixtures in testdata/golden and expected.json")
+
+const goldenDir = "testdata/golden"
+
+func TestMain(m *testing.M) {
+	trustedSetupPath, err := TrustedSetupPath()
+	if err != nil {
+		panic(err)
+	}
+	if err := ckzg4844.LoadTrustedSetupFile(trustedSetupPath, 0); err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	ckzg4844.FreeTrustedSetup()
+	os.Exit(code)
+}
+
+// TestGoldenFixtures regenerates the golden fixtures and compares them with
+// the committed ones, so that any change in behavior shows up as a diff.
+func TestGoldenFixtures(t *testing.T) {
+	if *update {
+		require.NoError(t, os.RemoveAll(goldenDir))
+		require.NoError(t, WriteGoldenFixtures(goldenDir, GoldenSeed))
+	}
+	dir := t.TempDir()
+	require.NoError(t, WriteGoldenFixtures(dir, GoldenSeed))
+
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, path)
+		}
+		return err
+	})
+	require.NoError(t, err)
+	committed, err := filepath.Glob(filepath.Join(goldenDir, "*", "*.txt"))
+	require.NoError(t, err)
+	// Every fixture directory holds five files, next to seed.txt and setup.txt.
+	require.Len(t, committed, len(files)-2, "fixtures were added or removed, run the tests with -update")
+
+	for _, path := range files {
+		name, err := filepath.Rel(dir, path)
+		require.NoError(t, err)
+		expected, err := os.ReadFile(filepath.Join(goldenDir, name))
+		require.NoError(t, err)
+		actual, err := os.ReadFile(path)
+		require.NoError(t, err)
+		if string(expected) != string(actual) {
+			t.Errorf("%s differs from the golden fixture, run the tests with -update if the change is intended", name)
+		}
+	}
+}
+
+// TestExpectedOutputs recomputes the outputs of the named fixtures and compares
+// them with the committed ones.
+func TestExpectedOutputs(t *testing.T) {
+	outputs, err := ComputeOutputs()
+	require.NoError(t, err)
+	if *update {
+		data, err := json.MarshalIndent(outputs, "", "  ")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile("expected.json", append(data, '\n'), 0o644))
+	}
+	committed, err := CommittedOutputs()
+	require.NoError(t, err)
+	require.Equal(t, outputs, committed, "the outputs of the named fixtures changed, run the tests with -update if the change is intended")
+}
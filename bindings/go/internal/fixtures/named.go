@@ -0,0 +1,152 @@
+package fixtures
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+)
+
+// FixturesVersion is the current version of the named fixtures. It is bumped
+// whenever the contents of an existing fixture change, so regression tests can
+// pin the version they were written against.
+const FixturesVersion = 1
+
+var ErrUnknownFixture = errors.New("unknown fixture")
+
+// blsModulus is ckzg4844.BLSModulus as an integer.
+var blsModulus = new(big.Int).SetBytes(ckzg4844.BLSModulus[:])
+
+// setFieldElement sets the i-th field element of blob to x.
+func setFieldElement(blob *ckzg4844.Blob, i int, x *big.Int) {
+	x.FillBytes(blob[i*ckzg4844.BytesPerFieldElement : (i+1)*ckzg4844.BytesPerFieldElement])
+}
+
+// namedBlobs maps each fixture name to the versions of its blob.
+var namedBlobs = map[string]map[int]func() *ckzg4844.Blob{
+	"zero-blob": {
+		1: func() *ckzg4844.Blob {
+			return new(ckzg4844.Blob)
+		},
+	},
+	"max-value-elements": {
+		1: func() *ckzg4844.Blob {
+			blob := new(ckzg4844.Blob)
+			maxElement := new(big.Int).Sub(blsModulus, big.NewInt(1))
+			for i := 0; i < ckzg4844.FieldElementsPerBlob; i++ {
+				setFieldElement(blob, i, maxElement)
+			}
+			return blob
+		},
+	},
+	"single-nonzero": {
+		1: func() *ckzg4844.Blob {
+			blob := new(ckzg4844.Blob)
+			setFieldElement(blob, 0, big.NewInt(1))
+			return blob
+		},
+	},
+	"last-nonzero": {
+		1: func() *ckzg4844.Blob {
+			blob := new(ckzg4844.Blob)
+			setFieldElement(blob, ckzg4844.FieldElementsPerBlob-1, big.NewInt(1))
+			return blob
+		},
+	},
+	"ascending-elements": {
+		1: func() *ckzg4844.Blob {
+			blob := new(ckzg4844.Blob)
+			for i := 0; i < ckzg4844.FieldElementsPerBlob; i++ {
+				setFieldElement(blob, i, big.NewInt(int64(i)))
+			}
+			return blob
+		},
+	},
+	"random": {
+		1: func() *ckzg4844.Blob {
+			return RandomBlob(0)
+		},
+	},
+}
+
+// FixtureNames returns the names of all named fixtures, sorted.
+func FixtureNames() []string {
+	names := make([]string, 0, len(namedBlobs))
+	for name := range namedBlobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+/*
+FixtureBlob returns the blob of the named fixture and its version. The name
+may have a version suffix, like "zero-blob@v1"; without one, the highest
+version of the fixture is returned. The available names are:
+
+  - zero-blob: every field element is zero
+  - max-value-elements: every field element is the largest canonical value
+  - single-nonzero: the first field element is one, the rest are zero
+  - last-nonzero: the last field element is one, the rest are zero
+  - ascending-elements: the i-th field element is i
+  - random: random canonical field elements from a fixed seed
+*/
+func FixtureBlob(name string) (*ckzg4844.Blob, int, error) {
+	version := -1
+	if base, suffix, found := strings.Cut(name, "@v"); found {
+		v, err := strconv.Atoi(suffix)
+		if err != nil || v < 0 {
+			return nil, 0, fmt.Errorf("%w: %s", ErrUnknownFixture, name)
+		}
+		name, version = base, v
+	}
+	versions, ok := namedBlobs[name]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: %s", ErrUnknownFixture, name)
+	}
+	if version == -1 {
+		for v := range versions {
+			if v > version {
+				version = v
+			}
+		}
+	}
+	newBlob, ok := versions[version]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: %s has no version %d", ErrUnknownFixture, name, version)
+	}
+	return newBlob(), version, nil
+}
+
+// Fixture returns the named fixture, see FixtureBlob, computed with the loaded
+// trusted setup.
+func Fixture(name string) (*GoldenFixture, error) {
+	blob, version, err := FixtureBlob(name)
+	if err != nil {
+		return nil, err
+	}
+	base, _, _ := strings.Cut(name, "@v")
+	fixture, err := NewGoldenFixture(base, blob)
+	if err != nil {
+		return nil, err
+	}
+	fixture.Version = version
+	return fixture, nil
+}
+
+// fixtureVersions returns the names of every version of every named fixture,
+// like "zero-blob@v1", sorted.
+func fixtureVersions() []string {
+	var names []string
+	for _, name := range FixtureNames() {
+		for version := range namedBlobs[name] {
+			names = append(names, fmt.Sprintf("%s@v%d", name, version))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
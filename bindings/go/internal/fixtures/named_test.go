@@ -0,0 +1,33 @@
+package fixtures
+
+import (
+	"errors"
+	"testing"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixtureVersions(t *testing.T) {
+	// A fixture whose blob changed in version 3, with a gap at version 2.
+	namedBlobs["versioned"] = map[int]func() *ckzg4844.Blob{
+		1: func() *ckzg4844.Blob { return new(ckzg4844.Blob) },
+		3: func() *ckzg4844.Blob { return RandomBlob(3) },
+	}
+	defer delete(namedBlobs, "versioned")
+
+	latest, err := Fixture("versioned")
+	require.NoError(t, err)
+	require.Equal(t, 3, latest.Version)
+	require.Equal(t, RandomBlob(3), latest.Blob)
+
+	first, err := Fixture("versioned@v1")
+	require.NoError(t, err)
+	require.Equal(t, 1, first.Version)
+	require.Equal(t, new(ckzg4844.Blob), first.Blob)
+
+	for _, name := range []string{"versioned@v2", "versioned@v0", "versioned@v-1", "unknown"} {
+		_, err := Fixture(name)
+		require.True(t, errors.Is(err, ErrUnknownFixture), name)
+	}
+}
@@ -0,0 +1,147 @@
+// Package kzg is an options-based surface over the bindings. Every operation
+// takes a variadic list of Options, such as
+//
+//	ok, err := kzg.VerifyBlobs(blobs, commitments, proofs, kzg.WithParallelism(8), kzg.WithStrictValidation())
+//
+// so that new modes can be added as options rather than as new function
+// variants.
+//
+// Unless a Backend is given with WithBackend, a trusted setup must be loaded.
+package kzg
+
+import (
+	"errors"
+	"unsafe"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+)
+
+type (
+	Blob          = ckzg4844.Blob
+	Bytes32       = ckzg4844.Bytes32
+	Bytes48       = ckzg4844.Bytes48
+	Cell          = ckzg4844.Cell
+	KZGCommitment = ckzg4844.KZGCommitment
+	KZGProof      = ckzg4844.KZGProof
+)
+
+type config struct {
+	backend     ckzg4844.Backend
+	parallelism int
+	strict      bool
+	cache       *ckzg4844.VerificationCache
+}
+
+// Option configures an operation.
+type Option func(*config)
+
+func newConfig(opts []Option) *config {
+	c := &config{backend: ckzg4844.NativeBackend{}, parallelism: 1}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithBackend performs the operation with backend instead of the C library.
+func WithBackend(backend ckzg4844.Backend) Option {
+	return func(c *config) {
+		c.backend = backend
+	}
+}
+
+// WithParallelism splits batch verifications into up to n batches which are
+// verified concurrently. The default is 1.
+func WithParallelism(n int) Option {
+	return func(c *config) {
+		if n < 1 {
+			n = 1
+		}
+		c.parallelism = n
+	}
+}
+
+/*
+WithStrictValidation makes verifications return ckzg4844.ErrBadArgs for inputs
+which aren't validly encoded, such as a commitment which isn't a point or a
+field element which isn't canonical. By default such inputs simply don't
+verify, which is what gossip validation usually needs.
+*/
+func WithStrictValidation() Option {
+	return func(c *config) {
+		c.strict = true
+	}
+}
+
+// WithCache skips blob verifications which are in cache, and adds blobs which
+// verify to it.
+func WithCache(cache *ckzg4844.VerificationCache) Option {
+	return func(c *config) {
+		c.cache = cache
+	}
+}
+
+// result applies the validation mode to the result of a verification.
+func (c *config) result(ok bool, err error) (bool, error) {
+	if !c.strict && errors.Is(err, ckzg4844.ErrBadArgs) {
+		return false, nil
+	}
+	return ok, err
+}
+
+func (c *config) verifyOptions() ckzg4844.VerifyOptions {
+	return ckzg4844.VerifyOptions{
+		Parallelism:       c.parallelism,
+		ValidateEncodings: c.strict,
+		Backend:           c.backend,
+	}
+}
+
+// Commit returns the commitment to blob.
+func Commit(blob *Blob, opts ...Option) (KZGCommitment, error) {
+	return newConfig(opts).backend.BlobToKZGCommitment(blob)
+}
+
+// Prove returns the blob proof of blob for its commitment.
+func Prove(blob *Blob, commitment Bytes48, opts ...Option) (KZGProof, error) {
+	return newConfig(opts).backend.ComputeBlobKZGProof(blob, commitment)
+}
+
+// ComputeCells returns the cells of blob and their proofs.
+func ComputeCells(blob *Blob, opts ...Option) ([ckzg4844.CellsPerExtBlob]Cell, [ckzg4844.CellsPerExtBlob]KZGProof, error) {
+	return newConfig(opts).backend.ComputeCellsAndKZGProofs(blob)
+}
+
+// RecoverCells recovers all cells and proofs of a blob from at least half of
+// its cells.
+func RecoverCells(cellIndices []uint64, cells []Cell, opts ...Option) ([ckzg4844.CellsPerExtBlob]Cell, [ckzg4844.CellsPerExtBlob]KZGProof, error) {
+	return newConfig(opts).backend.RecoverCellsAndKZGProofs(cellIndices, cells)
+}
+
+// Verify verifies the proof of blob.
+func Verify(blob *Blob, commitment, proof Bytes48, opts ...Option) (bool, error) {
+	if blob == nil {
+		return newConfig(opts).result(false, &ckzg4844.Error{Op: "VerifyBlobKZGProof", Err: ckzg4844.ErrBadArgs})
+	}
+	// The blob is used in place rather than copied into a new slice.
+	return VerifyBlobs(unsafe.Slice(blob, 1), []Bytes48{commitment}, []Bytes48{proof}, opts...)
+}
+
+// VerifyBlobs verifies the proofs of blobs, returning true only if all of
+// them verify.
+func VerifyBlobs(blobs []Blob, commitments, proofs []Bytes48, opts ...Option) (bool, error) {
+	c := newConfig(opts)
+	verify := ckzg4844.VerifyBlobKZGProofBatchWithOptions
+	if c.cache != nil {
+		verify = c.cache.VerifyBlobKZGProofBatchWithOptions
+	}
+	result, err := verify(blobs, commitments, proofs, c.verifyOptions())
+	return result.OK, err
+}
+
+// VerifyCells verifies cell proofs, returning true only if all of them verify.
+func VerifyCells(commitments []Bytes48, cellIndices []uint64, cells []Cell, proofs []Bytes48, opts ...Option) (bool, error) {
+	c := newConfig(opts)
+	result, err := ckzg4844.VerifyCellKZGProofBatchWithOptions(commitments, cellIndices, cells, proofs, c.verifyOptions())
+	return result.OK, err
+}
@@ -0,0 +1,97 @@
+package kzg
+
+import (
+	"testing"
+	"time"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/ckzgtest"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/simulation"
+	"github.com/stretchr/testify/require"
+)
+
+func makeBlobs(t *testing.T, n int, opts ...Option) ([]Blob, []Bytes48, []Bytes48) {
+	var blobs []Blob
+	var commitments, proofs []Bytes48
+	for i := 0; i < n; i++ {
+		blob := ckzgtest.RandomBlob(int64(i))
+		commitment, err := Commit(blob, opts...)
+		require.NoError(t, err)
+		proof, err := Prove(blob, Bytes48(commitment), opts...)
+		require.NoError(t, err)
+		blobs = append(blobs, *blob)
+		commitments = append(commitments, Bytes48(commitment))
+		proofs = append(proofs, Bytes48(proof))
+	}
+	return blobs, commitments, proofs
+}
+
+func TestVerifyBlobs(t *testing.T) {
+	ckzgtest.LoadTrustedSetup(t)
+	blobs, commitments, proofs := makeBlobs(t, 5)
+
+	for _, parallelism := range []int{1, 2, 8} {
+		ok, err := VerifyBlobs(blobs, commitments, proofs, WithParallelism(parallelism))
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	proofs[4] = proofs[0]
+	ok, err := VerifyBlobs(blobs, commitments, proofs, WithParallelism(2))
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// An invalid encoding only fails strict validation.
+	var invalid Bytes48
+	ok, err = Verify(&blobs[0], invalid, proofs[0])
+	require.NoError(t, err)
+	require.False(t, ok)
+	_, err = Verify(&blobs[0], invalid, proofs[0], WithStrictValidation())
+	require.ErrorIs(t, err, ckzg4844.ErrBadArgs)
+
+	// Mismatched lengths fail either way, with and without a cache.
+	var lengthErr ckzg4844.ErrLengthMismatch
+	_, err = VerifyBlobs(blobs, commitments[:1], proofs)
+	require.ErrorAs(t, err, &lengthErr)
+	_, err = VerifyBlobs(blobs, commitments, proofs[:1], WithCache(ckzg4844.NewVerificationCache(1, 0)))
+	require.ErrorAs(t, err, &lengthErr)
+	_, err = VerifyCells(commitments, []uint64{0}, make([]Cell, len(commitments)), proofs)
+	require.ErrorAs(t, err, &lengthErr)
+	require.Equal(t, "cellIndices", lengthErr.Argument)
+}
+
+func TestVerifyBlobsCache(t *testing.T) {
+	ckzgtest.LoadTrustedSetup(t)
+	blobs, commitments, proofs := makeBlobs(t, 2)
+	cache := ckzg4844.NewVerificationCache(16, time.Minute)
+
+	ok, err := Verify(&blobs[0], commitments[0], proofs[0], WithCache(cache))
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = VerifyBlobs(blobs, commitments, proofs, WithCache(cache))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 2, cache.Len())
+	hits, _ := cache.Stats()
+	require.Equal(t, uint64(1), hits)
+}
+
+func TestVerifyCellsWithBackend(t *testing.T) {
+	backend := WithBackend(simulation.Backend{})
+	blob := ckzgtest.RandomBlob(1)
+	commitment, err := Commit(blob, backend)
+	require.NoError(t, err)
+	cells, proofs, err := ComputeCells(blob, backend)
+	require.NoError(t, err)
+
+	var commitments, proofsBytes []Bytes48
+	var indices []uint64
+	for i := range cells {
+		commitments = append(commitments, Bytes48(commitment))
+		indices = append(indices, uint64(i))
+		proofsBytes = append(proofsBytes, Bytes48(proofs[i]))
+	}
+	ok, err := VerifyCells(commitments, indices, cells[:], proofsBytes, backend, WithParallelism(4))
+	require.NoError(t, err)
+	require.True(t, ok)
+}
@@ -0,0 +1,85 @@
+package ckzg4844
+
+import (
+	"context"
+	"runtime/pprof"
+	"strconv"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Keys of the pprof labels attached to KZG operations.
+const (
+	ProfilerLabelOperation = "ckzg_operation"
+	ProfilerLabelBatchSize = "ckzg_batch_size"
+)
+
+var profilerLabels atomic.Bool
+
+/*
+EnableProfilerLabels makes every KZG operation attach pprof labels with the
+operation name and batch size for the duration of the cgo call, so that CPU
+profiles attribute time to specific operations rather than runtime.cgocall.
+
+It is disabled by default. The labels are applied with pprof.Do, on top of the
+labels of the context passed to WithProfilerLabels. Operations called without
+it don't know the labels of the calling goroutine, so those are replaced for
+the duration of the cgo call; callers with labels that should stay attached to
+the operation must use WithProfilerLabels. Either way, the labels the
+goroutine had are restored once the operation returns.
+*/
+func EnableProfilerLabels(enabled bool) {
+	profilerLabels.Store(enabled)
+}
+
+/*
+WithProfilerLabels returns a Context which shares the trusted setup of the
+default context, and whose operations add their profiler labels to the pprof
+labels of ctx rather than replacing the labels of the calling goroutine.
+Goroutines which carry their own labels, for example from pprof.Do, should
+call operations through it with the context holding those labels.
+*/
+func WithProfilerLabels(ctx context.Context) *Context {
+	return defaultContext.WithProfilerLabels(ctx)
+}
+
+/*
+WithProfilerLabels is WithProfilerLabels for the trusted setup of c. The
+returned Context must not be freed and must not be used after c is freed, and
+precompute snapshots can't be restored into it.
+*/
+func (c *Context) WithProfilerLabels(ctx context.Context) *Context {
+	if !c.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	return &Context{settings: c.settings, loaded: true, labels: ctx, shared: true}
+}
+
+// profileLabels runs f with the goroutine labelled with op and batchSize, on
+// top of the labels of ctx.
+func profileLabels(ctx context.Context, op string, batchSize int, f func()) {
+	if !profilerLabels.Load() {
+		f()
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// pprof.Do restores the labels of ctx rather than those of the goroutine.
+	saved := runtimeGetProfLabel()
+	defer runtimeSetProfLabel(saved)
+	labels := pprof.Labels(ProfilerLabelOperation, op, ProfilerLabelBatchSize, strconv.Itoa(batchSize))
+	pprof.Do(ctx, labels, func(context.Context) { f() })
+}
+
+// runtimeGetProfLabel returns the pprof labels of the calling goroutine. The
+// runtime keeps it linkable, see go.dev/issue/67401.
+//
+//go:linkname runtimeGetProfLabel runtime/pprof.runtime_getProfLabel
+func runtimeGetProfLabel() unsafe.Pointer
+
+// runtimeSetProfLabel sets the pprof labels of the calling goroutine to labels
+// returned by runtimeGetProfLabel.
+//
+//go:linkname runtimeSetProfLabel runtime/pprof.runtime_setProfLabel
+func runtimeSetProfLabel(labels unsafe.Pointer)
@@ -0,0 +1,70 @@
+package ckzg4844
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"runtime/pprof"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfilerLabels(t *testing.T) {
+	EnableProfilerLabels(true)
+	defer EnableProfilerLabels(false)
+
+	var buf bytes.Buffer
+	require.NoError(t, pprof.StartCPUProfile(&buf))
+	blob := selfTestBlob()
+	for start := time.Now(); time.Since(start) < 500*time.Millisecond; {
+		_, _, err := ComputeCellsAndKZGProofs(blob)
+		require.NoError(t, err)
+	}
+	pprof.StopCPUProfile()
+
+	r, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	profile, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Contains(t, string(profile), ProfilerLabelOperation)
+	require.Contains(t, string(profile), "ComputeCellsAndKZGProofs")
+}
+
+func TestProfilerLabelsNest(t *testing.T) {
+	EnableProfilerLabels(true)
+	defer EnableProfilerLabels(false)
+
+	blob := selfTestBlob()
+	pprof.Do(context.Background(), pprof.Labels("caller", "test"), func(ctx context.Context) {
+		var buf bytes.Buffer
+		EnableLatencyHistograms(true)
+		defer EnableLatencyHistograms(false)
+		_, err := WithProfilerLabels(ctx).BlobToKZGCommitment(blob)
+		require.NoError(t, err)
+
+		// The labels of the caller are still set once the operation returns.
+		require.NoError(t, pprof.Lookup("goroutine").WriteTo(&buf, 1))
+		require.Contains(t, buf.String(), `"caller":"test"`)
+	})
+	require.Panics(t, func() { WithProfilerLabels(context.Background()).Free() })
+}
+
+func TestProfilerLabelsRestore(t *testing.T) {
+	EnableProfilerLabels(true)
+	defer EnableProfilerLabels(false)
+
+	blob := selfTestBlob()
+	pprof.Do(context.Background(), pprof.Labels("caller", "test"), func(ctx context.Context) {
+		// Without WithProfilerLabels, the operation doesn't know the labels of
+		// the caller, but mustn't clear them.
+		_, err := BlobToKZGCommitment(blob)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, pprof.Lookup("goroutine").WriteTo(&buf, 1))
+		require.Contains(t, buf.String(), `"caller":"test"`)
+	})
+}
@@ -0,0 +1,135 @@
+package ckzg4844
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrQueueFull is returned instead of waiting when a Limiter or a VerifierPool
+// has no room left to queue an operation.
+var ErrQueueFull = errors.New("verification queue is full")
+
+// LimiterConfig configures a Limiter. Zero values mean no limit.
+type LimiterConfig struct {
+	// MaxConcurrent is the number of operations which may run at once.
+	MaxConcurrent int
+	// MaxQueuedBytes is the total size of the inputs of the operations which
+	// may wait for their turn. Operations which would exceed it are rejected
+	// with ErrQueueFull instead of waiting. An operation is always queued
+	// when nothing else is waiting, so that larger inputs can still run.
+	MaxQueuedBytes int64
+}
+
+// LimiterStats is a snapshot of the state of a Limiter.
+type LimiterStats struct {
+	// Running is the number of operations currently running.
+	Running int
+	// Queued is the number of operations waiting to run.
+	Queued int
+	// QueuedBytes is the total size of the inputs of the waiting operations.
+	QueuedBytes int64
+	// Rejected is the number of operations rejected with ErrQueueFull.
+	Rejected uint64
+}
+
+/*
+Limiter bounds how much verification work runs at once and how much may wait,
+so that a flood of gossiped sidecars can't occupy every core and starve other
+work on the host, such as producing attestations. Work which doesn't fit in the
+queue is rejected immediately rather than piling up.
+*/
+type Limiter struct {
+	config LimiterConfig
+	slots  chan struct{}
+
+	mu    sync.Mutex
+	stats LimiterStats
+}
+
+// NewLimiter returns a Limiter with the given limits.
+func NewLimiter(config LimiterConfig) *Limiter {
+	l := &Limiter{config: config}
+	if config.MaxConcurrent > 0 {
+		l.slots = make(chan struct{}, config.MaxConcurrent)
+	}
+	return l
+}
+
+/*
+Acquire waits until an operation whose inputs are size bytes may run, and
+returns a function which must be called once it has finished. It returns
+ErrQueueFull if the operation can't be queued, or the error of ctx if ctx is
+done before the operation may run.
+*/
+func (l *Limiter) Acquire(ctx context.Context, size int64) (func(), error) {
+	l.mu.Lock()
+	if l.config.MaxQueuedBytes > 0 && l.stats.QueuedBytes+size > l.config.MaxQueuedBytes && l.stats.Queued > 0 {
+		l.stats.Rejected++
+		l.mu.Unlock()
+		return nil, ErrQueueFull
+	}
+	l.stats.Queued++
+	l.stats.QueuedBytes += size
+	l.mu.Unlock()
+
+	var err error
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stats.Queued--
+	l.stats.QueuedBytes -= size
+	if err != nil {
+		return nil, err
+	}
+	l.stats.Running++
+	var once sync.Once
+	return func() {
+		once.Do(l.release)
+	}, nil
+}
+
+func (l *Limiter) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+	l.mu.Lock()
+	l.stats.Running--
+	l.mu.Unlock()
+}
+
+// Stats returns the current state of the limiter.
+func (l *Limiter) Stats() LimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stats
+}
+
+// VerifyBlobKZGProofBatch calls VerifyBlobKZGProofBatch once the limiter
+// admits it.
+func (l *Limiter) VerifyBlobKZGProofBatch(ctx context.Context, blobs []Blob, commitmentsBytes, proofsBytes []Bytes48) (bool, error) {
+	release, err := l.Acquire(ctx, int64(len(blobs))*BytesPerBlob)
+	if err != nil {
+		return false, err
+	}
+	defer release()
+	return VerifyBlobKZGProofBatch(blobs, commitmentsBytes, proofsBytes)
+}
+
+// VerifyCellKZGProofBatch calls VerifyCellKZGProofBatch once the limiter
+// admits it.
+func (l *Limiter) VerifyCellKZGProofBatch(ctx context.Context, commitmentsBytes []Bytes48, cellIndices []uint64, cells []Cell, proofsBytes []Bytes48) (bool, error) {
+	release, err := l.Acquire(ctx, int64(len(cells))*BytesPerCell)
+	if err != nil {
+		return false, err
+	}
+	defer release()
+	return VerifyCellKZGProofBatch(commitmentsBytes, cellIndices, cells, proofsBytes)
+}
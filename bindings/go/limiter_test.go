@@ -0,0 +1,57 @@
+package ckzg4844
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	l := NewLimiter(LimiterConfig{MaxConcurrent: 1, MaxQueuedBytes: 100})
+	ctx := context.Background()
+
+	release, err := l.Acquire(ctx, 1000)
+	require.NoError(t, err)
+	require.Equal(t, LimiterStats{Running: 1}, l.Stats())
+
+	// An operation is queued while nothing else waits, even if it's too large.
+	acquired := make(chan func())
+	go func() {
+		release, err := l.Acquire(ctx, 1000)
+		if err != nil {
+			t.Error(err)
+		}
+		acquired <- release
+	}()
+	require.Eventually(t, func() bool { return l.Stats().Queued == 1 }, time.Second, time.Millisecond)
+	_, err = l.Acquire(ctx, 1)
+	require.ErrorIs(t, err, ErrQueueFull)
+	require.Equal(t, LimiterStats{Running: 1, Queued: 1, QueuedBytes: 1000, Rejected: 1}, l.Stats())
+
+	release()
+	release = <-acquired
+	require.Equal(t, LimiterStats{Running: 1, Rejected: 1}, l.Stats())
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = l.Acquire(canceled, 1)
+	require.ErrorIs(t, err, context.Canceled)
+	release()
+	require.Equal(t, LimiterStats{Rejected: 1}, l.Stats())
+}
+
+func TestLimiterVerify(t *testing.T) {
+	blob := selfTestBlob()
+	commitment, err := BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+	proof, err := ComputeBlobKZGProof(blob, Bytes48(commitment))
+	require.NoError(t, err)
+
+	l := NewLimiter(LimiterConfig{MaxConcurrent: 2})
+	ok, err := l.VerifyBlobKZGProofBatch(context.Background(), []Blob{*blob}, []Bytes48{Bytes48(commitment)}, []Bytes48{Bytes48(proof)})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, LimiterStats{}, l.Stats())
+}
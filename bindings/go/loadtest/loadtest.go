@@ -0,0 +1,287 @@
+// Package loadtest drives a configurable mix of verification work through the
+// bindings at fixed rates and reports the sustained throughput and latency
+// distribution of each operation. It is meant for validating that hardware
+// can keep up with a target blob count before it is needed on mainnet.
+//
+// A trusted setup must be loaded before calling Run.
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/internal/fixtures"
+)
+
+const (
+	OpVerifyBlobKZGProof      = "VerifyBlobKZGProof"
+	OpVerifyCellKZGProofBatch = "VerifyCellKZGProofBatch"
+)
+
+// MaxRate is the highest rate of an operation, one call per nanosecond, since
+// calls are scheduled by a time.Ticker.
+const MaxRate = int(time.Second)
+
+var ErrInvalidConfig = errors.New("invalid load test config")
+
+// Config describes the load to generate.
+type Config struct {
+	// BlobVerificationsPerSecond is the rate of VerifyBlobKZGProof calls, at
+	// most MaxRate. Zero disables them.
+	BlobVerificationsPerSecond int
+	// CellBatchesPerSecond is the rate of VerifyCellKZGProofBatch calls, at
+	// most MaxRate. Zero disables them.
+	CellBatchesPerSecond int
+	// CellsPerBatch is the number of cells in each cell batch.
+	CellsPerBatch int
+	// Duration is how long to generate load for.
+	Duration time.Duration
+	// Workers is the number of goroutines executing operations. Defaults to
+	// runtime.NumCPU().
+	Workers int
+	// QueueSize is the number of scheduled operations which may wait for a
+	// worker. Operations scheduled while the queue is full are dropped.
+	// Defaults to 4 * Workers.
+	QueueSize int
+}
+
+// OpStats summarizes the executions of one operation. Latencies are measured
+// from when the operation was scheduled, so they include time spent queued.
+type OpStats struct {
+	Name       string
+	Count      int
+	Errors     int
+	Dropped    int
+	Throughput float64
+	P50        time.Duration
+	P90        time.Duration
+	P99        time.Duration
+	Max        time.Duration
+}
+
+// Report is the result of a load test.
+type Report struct {
+	Elapsed time.Duration
+	Ops     []OpStats
+}
+
+func (r *Report) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "elapsed: %v\n", r.Elapsed.Round(time.Millisecond))
+	for _, op := range r.Ops {
+		fmt.Fprintf(&sb, "%s: count=%d errors=%d dropped=%d throughput=%.1f/s p50=%v p90=%v p99=%v max=%v\n",
+			op.Name, op.Count, op.Errors, op.Dropped, op.Throughput,
+			op.P50.Round(time.Microsecond), op.P90.Round(time.Microsecond),
+			op.P99.Round(time.Microsecond), op.Max.Round(time.Microsecond))
+	}
+	return sb.String()
+}
+
+// job is a single scheduled operation.
+type job struct {
+	op        int
+	scheduled time.Time
+}
+
+// operation is a kind of work, with its rate and collected samples.
+type operation struct {
+	name      string
+	rate      int
+	run       func() error
+	mu        sync.Mutex
+	latencies []time.Duration
+	errors    int
+	dropped   int
+}
+
+func (o *operation) record(latency time.Duration, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if err != nil {
+		o.errors++
+		return
+	}
+	o.latencies = append(o.latencies, latency)
+}
+
+func (o *operation) stats(elapsed time.Duration) OpStats {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	sort.Slice(o.latencies, func(i, j int) bool { return o.latencies[i] < o.latencies[j] })
+	stats := OpStats{
+		Name:       o.name,
+		Count:      len(o.latencies),
+		Errors:     o.errors,
+		Dropped:    o.dropped,
+		Throughput: float64(len(o.latencies)) / elapsed.Seconds(),
+	}
+	if len(o.latencies) > 0 {
+		stats.P50 = percentile(o.latencies, 0.50)
+		stats.P90 = percentile(o.latencies, 0.90)
+		stats.P99 = percentile(o.latencies, 0.99)
+		stats.Max = o.latencies[len(o.latencies)-1]
+	}
+	return stats
+}
+
+// percentile returns the p-th percentile of sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	return sorted[int(p*float64(len(sorted)-1))]
+}
+
+// newOperations prepares valid inputs and returns the configured operations.
+func newOperations(cfg Config) ([]*operation, error) {
+	blob := fixtures.RandomBlob(0)
+	commitment, err := ckzg4844.BlobToKZGCommitment(blob)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := ckzg4844.ComputeBlobKZGProof(blob, ckzg4844.Bytes48(commitment))
+	if err != nil {
+		return nil, err
+	}
+	var ops []*operation
+	if cfg.BlobVerificationsPerSecond > 0 {
+		ops = append(ops, &operation{
+			name: OpVerifyBlobKZGProof,
+			rate: cfg.BlobVerificationsPerSecond,
+			run: func() error {
+				ok, err := ckzg4844.VerifyBlobKZGProof(blob, ckzg4844.Bytes48(commitment), ckzg4844.Bytes48(proof))
+				if err == nil && !ok {
+					err = errors.New("proof did not verify")
+				}
+				return err
+			},
+		})
+	}
+	if cfg.CellBatchesPerSecond > 0 {
+		cells, cellProofs, err := ckzg4844.ComputeCellsAndKZGProofs(blob)
+		if err != nil {
+			return nil, err
+		}
+		commitments := make([]ckzg4844.Bytes48, cfg.CellsPerBatch)
+		cellIndices := make([]uint64, cfg.CellsPerBatch)
+		batchCells := make([]ckzg4844.Cell, cfg.CellsPerBatch)
+		batchProofs := make([]ckzg4844.Bytes48, cfg.CellsPerBatch)
+		for i := range batchCells {
+			cellIndex := i % ckzg4844.CellsPerExtBlob
+			commitments[i] = ckzg4844.Bytes48(commitment)
+			cellIndices[i] = uint64(cellIndex)
+			batchCells[i] = cells[cellIndex]
+			batchProofs[i] = ckzg4844.Bytes48(cellProofs[cellIndex])
+		}
+		ops = append(ops, &operation{
+			name: OpVerifyCellKZGProofBatch,
+			rate: cfg.CellBatchesPerSecond,
+			run: func() error {
+				ok, err := ckzg4844.VerifyCellKZGProofBatch(commitments, cellIndices, batchCells, batchProofs)
+				if err == nil && !ok {
+					err = errors.New("cells did not verify")
+				}
+				return err
+			},
+		})
+	}
+	return ops, nil
+}
+
+// validate returns an error wrapping ErrInvalidConfig if cfg can't be run.
+func (cfg *Config) validate() error {
+	for _, rate := range []struct {
+		name  string
+		value int
+	}{
+		{"BlobVerificationsPerSecond", cfg.BlobVerificationsPerSecond},
+		{"CellBatchesPerSecond", cfg.CellBatchesPerSecond},
+	} {
+		if rate.value < 0 || rate.value > MaxRate {
+			return fmt.Errorf("%w: %s is %d, must be between 0 and %d", ErrInvalidConfig, rate.name, rate.value, MaxRate)
+		}
+	}
+	if cfg.BlobVerificationsPerSecond == 0 && cfg.CellBatchesPerSecond == 0 {
+		return fmt.Errorf("%w: every rate is zero", ErrInvalidConfig)
+	}
+	if cfg.CellBatchesPerSecond > 0 && cfg.CellsPerBatch <= 0 {
+		return fmt.Errorf("%w: CellsPerBatch is %d, must be positive", ErrInvalidConfig, cfg.CellsPerBatch)
+	}
+	if cfg.Duration <= 0 {
+		return fmt.Errorf("%w: Duration is %v, must be positive", ErrInvalidConfig, cfg.Duration)
+	}
+	return nil
+}
+
+// Run generates the configured load until cfg.Duration has passed or ctx is
+// cancelled, and then waits for queued operations to finish.
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = runtime.NumCPU()
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 4 * cfg.Workers
+	}
+
+	ops, err := newOperations(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make(chan job, cfg.QueueSize)
+	var workers sync.WaitGroup
+	for i := 0; i < cfg.Workers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for j := range jobs {
+				err := ops[j.op].run()
+				ops[j.op].record(time.Since(j.scheduled), err)
+			}
+		}()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+	start := time.Now()
+	var schedulers sync.WaitGroup
+	for i, op := range ops {
+		schedulers.Add(1)
+		go func(i int, op *operation) {
+			defer schedulers.Done()
+			ticker := time.NewTicker(time.Second / time.Duration(op.rate))
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case scheduled := <-ticker.C:
+					select {
+					case jobs <- job{op: i, scheduled: scheduled}:
+					default:
+						op.mu.Lock()
+						op.dropped++
+						op.mu.Unlock()
+					}
+				}
+			}
+		}(i, op)
+	}
+	schedulers.Wait()
+	close(jobs)
+	workers.Wait()
+	elapsed := time.Since(start)
+
+	report := &Report{Elapsed: elapsed}
+	for _, op := range ops {
+		report.Ops = append(report.Ops, op.stats(elapsed))
+	}
+	return report, nil
+}
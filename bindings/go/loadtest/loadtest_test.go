@@ -0,0 +1,52 @@
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/ckzgtest"
+)
+
+func TestRun(t *testing.T) {
+	ckzgtest.LoadTrustedSetup(t)
+	report, err := Run(context.Background(), Config{
+		BlobVerificationsPerSecond: 20,
+		CellBatchesPerSecond:       10,
+		CellsPerBatch:              16,
+		Duration:                   500 * time.Millisecond,
+		Workers:                    2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(report)
+	if len(report.Ops) != 2 {
+		t.Fatalf("expected 2 operations, got %d", len(report.Ops))
+	}
+	for _, op := range report.Ops {
+		if op.Count == 0 || op.Errors != 0 {
+			t.Fatalf("%s: count=%d errors=%d", op.Name, op.Count, op.Errors)
+		}
+		if op.P50 > op.P99 || op.P99 > op.Max {
+			t.Fatalf("%s: percentiles out of order", op.Name)
+		}
+	}
+}
+
+func TestRunInvalidConfig(t *testing.T) {
+	for _, cfg := range []Config{
+		{CellBatchesPerSecond: 1, Duration: time.Second},
+		{BlobVerificationsPerSecond: -1, Duration: time.Second},
+		{BlobVerificationsPerSecond: MaxRate + 1, Duration: time.Second},
+		{CellBatchesPerSecond: MaxRate + 1, CellsPerBatch: 1, Duration: time.Second},
+		{Duration: time.Second},
+		{BlobVerificationsPerSecond: 1},
+	} {
+		_, err := Run(context.Background(), cfg)
+		if !errors.Is(err, ErrInvalidConfig) {
+			t.Fatalf("%+v: expected ErrInvalidConfig, got %v", cfg, err)
+		}
+	}
+}
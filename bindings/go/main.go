@@ -10,7 +10,6 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"unsafe"
 
 	// So its functions are available during compilation.
 	_ "github.com/supranational/blst/bindings/go"
@@ -36,9 +35,14 @@ type (
 	Cell          [BytesPerCell]byte
 )
 
+// BLSModulus is the order of the BLS12-381 scalar field, big-endian. A field
+// element is canonical if it is less than BLSModulus. It must not be modified.
+var BLSModulus = Bytes32{
+	0x73, 0xed, 0xa7, 0x53, 0x29, 0x9d, 0x7d, 0x48, 0x33, 0x39, 0xd8, 0x08, 0x09, 0xa1, 0xd8, 0x05,
+	0x53, 0xbd, 0xa4, 0x02, 0xff, 0xfe, 0x5b, 0xfe, 0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x01,
+}
+
 var (
-	loaded     = false
-	settings   = C.KZGSettings{}
 	ErrBadArgs = errors.New("bad arguments")
 	ErrError   = errors.New("unexpected error")
 	ErrMalloc  = errors.New("malloc failed")
@@ -67,72 +71,175 @@ func makeErrorFromRet(ret C.C_KZG_RET) error {
 // Unmarshal Functions
 ///////////////////////////////////////////////////////////////////////////////
 
-func (b *Bytes32) UnmarshalText(input []byte) error {
+// unmarshalHex decodes input, hex with an optional 0x prefix, into out, which
+// it must fill exactly.
+func unmarshalHex(out, input []byte) error {
 	if bytes.HasPrefix(input, []byte("0x")) {
 		input = input[2:]
 	}
-	if len(input) != 2*len(b) {
+	if len(input) != 2*len(out) {
 		return ErrBadArgs
 	}
-	l, err := hex.Decode(b[:], input)
+	l, err := hex.Decode(out, input)
 	if err != nil {
 		return err
 	}
-	if l != len(b) {
+	if l != len(out) {
 		return ErrBadArgs
 	}
 	return nil
 }
 
-func (b *Bytes48) UnmarshalText(input []byte) error {
-	if bytes.HasPrefix(input, []byte("0x")) {
-		input = input[2:]
-	}
-	if len(input) != 2*len(b) {
-		return ErrBadArgs
-	}
-	l, err := hex.Decode(b[:], input)
-	if err != nil {
-		return err
+// unmarshalJSONHex decodes input, a JSON string holding hex, into out. As is
+// conventional, null leaves out unchanged.
+func unmarshalJSONHex(out, input []byte) error {
+	if string(input) == "null" {
+		return nil
 	}
-	if l != len(b) {
+	if len(input) < 2 || input[0] != '"' || input[len(input)-1] != '"' {
 		return ErrBadArgs
 	}
-	return nil
+	return unmarshalHex(out, input[1:len(input)-1])
+}
+
+func (b *Bytes32) UnmarshalText(input []byte) error {
+	return unmarshalHex(b[:], input)
+}
+
+func (b *Bytes48) UnmarshalText(input []byte) error {
+	return unmarshalHex(b[:], input)
+}
+
+func (c *KZGCommitment) UnmarshalText(input []byte) error {
+	return unmarshalHex(c[:], input)
+}
+
+func (p *KZGProof) UnmarshalText(input []byte) error {
+	return unmarshalHex(p[:], input)
 }
 
 func (b *Blob) UnmarshalText(input []byte) error {
-	if bytes.HasPrefix(input, []byte("0x")) {
-		input = input[2:]
-	}
-	if len(input) != 2*len(b) {
-		return ErrBadArgs
-	}
-	l, err := hex.Decode(b[:], input)
-	if err != nil {
-		return err
-	}
-	if l != len(b) {
-		return ErrBadArgs
-	}
-	return nil
+	return unmarshalHex(b[:], input)
 }
 
 func (c *Cell) UnmarshalText(input []byte) error {
-	if bytes.HasPrefix(input, []byte("0x")) {
-		input = input[2:]
-	}
-	if len(input) != 2*len(c) {
-		return ErrBadArgs
-	}
-	l, err := hex.Decode(c[:], input)
-	if err != nil {
-		return err
+	return unmarshalHex(c[:], input)
+}
+
+func (b *Bytes32) UnmarshalJSON(input []byte) error {
+	return unmarshalJSONHex(b[:], input)
+}
+
+func (b *Bytes48) UnmarshalJSON(input []byte) error {
+	return unmarshalJSONHex(b[:], input)
+}
+
+func (c *KZGCommitment) UnmarshalJSON(input []byte) error {
+	return unmarshalJSONHex(c[:], input)
+}
+
+func (p *KZGProof) UnmarshalJSON(input []byte) error {
+	return unmarshalJSONHex(p[:], input)
+}
+
+func (b *Blob) UnmarshalJSON(input []byte) error {
+	return unmarshalJSONHex(b[:], input)
+}
+
+func (c *Cell) UnmarshalJSON(input []byte) error {
+	return unmarshalJSONHex(c[:], input)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Marshal Functions
+///////////////////////////////////////////////////////////////////////////////
+
+// marshalHex returns the 0x-prefixed hex encoding of b, the form used by the
+// reference tests and JSON-RPC, in double quotes if quoted is set.
+func marshalHex(b []byte, quoted bool) []byte {
+	q := 0
+	if quoted {
+		q = 1
 	}
-	if l != len(c) {
-		return ErrBadArgs
+	out := make([]byte, 2+2*len(b)+2*q)
+	copy(out[q:], "0x")
+	hex.Encode(out[q+2:], b)
+	if quoted {
+		out[0], out[len(out)-1] = '"', '"'
 	}
-	return nil
+	return out
+}
+
+func (b Bytes32) MarshalText() ([]byte, error) {
+	return marshalHex(b[:], false), nil
+}
+
+func (b Bytes48) MarshalText() ([]byte, error) {
+	return marshalHex(b[:], false), nil
+}
+
+func (c KZGCommitment) MarshalText() ([]byte, error) {
+	return marshalHex(c[:], false), nil
+}
+
+func (p KZGProof) MarshalText() ([]byte, error) {
+	return marshalHex(p[:], false), nil
+}
+
+func (b Blob) MarshalText() ([]byte, error) {
+	return marshalHex(b[:], false), nil
+}
+
+func (c Cell) MarshalText() ([]byte, error) {
+	return marshalHex(c[:], false), nil
+}
+
+func (b Bytes32) MarshalJSON() ([]byte, error) {
+	return marshalHex(b[:], true), nil
+}
+
+func (b Bytes48) MarshalJSON() ([]byte, error) {
+	return marshalHex(b[:], true), nil
+}
+
+func (c KZGCommitment) MarshalJSON() ([]byte, error) {
+	return marshalHex(c[:], true), nil
+}
+
+func (p KZGProof) MarshalJSON() ([]byte, error) {
+	return marshalHex(p[:], true), nil
+}
+
+func (b Blob) MarshalJSON() ([]byte, error) {
+	return marshalHex(b[:], true), nil
+}
+
+func (c Cell) MarshalJSON() ([]byte, error) {
+	return marshalHex(c[:], true), nil
+}
+
+func (b Bytes32) String() string {
+	return string(marshalHex(b[:], false))
+}
+
+func (b Bytes48) String() string {
+	return string(marshalHex(b[:], false))
+}
+
+func (c KZGCommitment) String() string {
+	return string(marshalHex(c[:], false))
+}
+
+func (p KZGProof) String() string {
+	return string(marshalHex(p[:], false))
+}
+
+func (b Blob) String() string {
+	return string(marshalHex(b[:], false))
+}
+
+func (c Cell) String() string {
+	return string(marshalHex(c[:], false))
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -151,25 +258,19 @@ LoadTrustedSetup is the binding for:
 	    const uint8_t *g2_monomial_bytes,
 	    uint64_t num_g2_monomial_bytes,
 	    uint64_t precompute);
+
+precompute, between 0 and MaxPrecompute, is the window size of the fixed-base
+tables used to compute cell proofs. Larger values make
+ComputeCellsAndKZGProofs faster, at the cost of memory which doubles with each
+step, see PrecomputeMemory: with 8, the tables take 96 MiB. There are
+diminishing returns past 8. Zero disables the tables. The same applies to
+LoadTrustedSetupFile and the other loading functions.
 */
 func LoadTrustedSetup(g1MonomialBytes, g1LagrangeBytes, g2MonomialBytes []byte, precompute uint) error {
-	if loaded {
+	if defaultContext.loaded {
 		panic("trusted setup is already loaded")
 	}
-	ret := C.load_trusted_setup(
-		&settings,
-		*(**C.uint8_t)(unsafe.Pointer(&g1MonomialBytes)),
-		(C.uint64_t)(len(g1MonomialBytes)),
-		*(**C.uint8_t)(unsafe.Pointer(&g1LagrangeBytes)),
-		(C.uint64_t)(len(g1LagrangeBytes)),
-		*(**C.uint8_t)(unsafe.Pointer(&g2MonomialBytes)),
-		(C.uint64_t)(len(g2MonomialBytes)),
-		(C.uint64_t)(precompute))
-	if ret == C.C_KZG_OK {
-		loaded = true
-		return nil
-	}
-	return makeErrorFromRet(ret)
+	return defaultContext.load(g1MonomialBytes, g1LagrangeBytes, g2MonomialBytes, precompute)
 }
 
 /*
@@ -181,24 +282,10 @@ LoadTrustedSetupFile is the binding for:
 	    uint64_t precompute);
 */
 func LoadTrustedSetupFile(trustedSetupFile string, precompute uint) error {
-	if loaded {
+	if defaultContext.loaded {
 		panic("trusted setup is already loaded")
 	}
-	cTrustedSetupFile := C.CString(trustedSetupFile)
-	defer C.free(unsafe.Pointer(cTrustedSetupFile))
-	cMode := C.CString("r")
-	defer C.free(unsafe.Pointer(cMode))
-	fp := C.fopen(cTrustedSetupFile, cMode)
-	if fp == nil {
-		panic("error reading trusted setup")
-	}
-	ret := C.load_trusted_setup_file(&settings, fp, (C.uint64_t)(precompute))
-	C.fclose(fp)
-	if ret == C.C_KZG_OK {
-		loaded = true
-		return nil
-	}
-	return makeErrorFromRet(ret)
+	return defaultContext.loadFile(trustedSetupFile, precompute)
 }
 
 /*
@@ -208,11 +295,7 @@ FreeTrustedSetup is the binding for:
 	    KZGSettings *s);
 */
 func FreeTrustedSetup() {
-	if !loaded {
-		panic("trusted setup isn't loaded")
-	}
-	C.free_trusted_setup(&settings)
-	loaded = false
+	defaultContext.Free()
 }
 
 /*
@@ -224,23 +307,7 @@ BlobToKZGCommitment is the binding for:
 	    const KZGSettings *s);
 */
 func BlobToKZGCommitment(blob *Blob) (KZGCommitment, error) {
-	if !loaded {
-		panic("trusted setup isn't loaded")
-	}
-	if blob == nil {
-		return KZGCommitment{}, ErrBadArgs
-	}
-
-	var commitment KZGCommitment
-	ret := C.blob_to_kzg_commitment(
-		(*C.KZGCommitment)(unsafe.Pointer(&commitment)),
-		(*C.Blob)(unsafe.Pointer(blob)),
-		&settings)
-
-	if ret != C.C_KZG_OK {
-		return KZGCommitment{}, makeErrorFromRet(ret)
-	}
-	return commitment, nil
+	return defaultContext.BlobToKZGCommitment(blob)
 }
 
 /*
@@ -254,25 +321,7 @@ ComputeKZGProof is the binding for:
 	    const KZGSettings *s);
 */
 func ComputeKZGProof(blob *Blob, zBytes Bytes32) (KZGProof, Bytes32, error) {
-	if !loaded {
-		panic("trusted setup isn't loaded")
-	}
-	if blob == nil {
-		return KZGProof{}, Bytes32{}, ErrBadArgs
-	}
-
-	var proof, y = KZGProof{}, Bytes32{}
-	ret := C.compute_kzg_proof(
-		(*C.KZGProof)(unsafe.Pointer(&proof)),
-		(*C.Bytes32)(unsafe.Pointer(&y)),
-		(*C.Blob)(unsafe.Pointer(blob)),
-		(*C.Bytes32)(unsafe.Pointer(&zBytes)),
-		&settings)
-
-	if ret != C.C_KZG_OK {
-		return KZGProof{}, Bytes32{}, makeErrorFromRet(ret)
-	}
-	return proof, y, nil
+	return defaultContext.ComputeKZGProof(blob, zBytes)
 }
 
 /*
@@ -285,23 +334,7 @@ ComputeBlobKZGProof is the binding for:
 	    const KZGSettings *s);
 */
 func ComputeBlobKZGProof(blob *Blob, commitmentBytes Bytes48) (KZGProof, error) {
-	if !loaded {
-		panic("trusted setup isn't loaded")
-	}
-	if blob == nil {
-		return KZGProof{}, ErrBadArgs
-	}
-	var proof KZGProof
-	ret := C.compute_blob_kzg_proof(
-		(*C.KZGProof)(unsafe.Pointer(&proof)),
-		(*C.Blob)(unsafe.Pointer(blob)),
-		(*C.Bytes48)(unsafe.Pointer(&commitmentBytes)),
-		&settings)
-
-	if ret != C.C_KZG_OK {
-		return KZGProof{}, makeErrorFromRet(ret)
-	}
-	return proof, nil
+	return defaultContext.ComputeBlobKZGProof(blob, commitmentBytes)
 }
 
 /*
@@ -316,22 +349,7 @@ VerifyKZGProof is the binding for:
 	    const KZGSettings *s);
 */
 func VerifyKZGProof(commitmentBytes Bytes48, zBytes, yBytes Bytes32, proofBytes Bytes48) (bool, error) {
-	if !loaded {
-		panic("trusted setup isn't loaded")
-	}
-	var result C.bool
-	ret := C.verify_kzg_proof(
-		&result,
-		(*C.Bytes48)(unsafe.Pointer(&commitmentBytes)),
-		(*C.Bytes32)(unsafe.Pointer(&zBytes)),
-		(*C.Bytes32)(unsafe.Pointer(&yBytes)),
-		(*C.Bytes48)(unsafe.Pointer(&proofBytes)),
-		&settings)
-
-	if ret != C.C_KZG_OK {
-		return false, makeErrorFromRet(ret)
-	}
-	return bool(result), nil
+	return defaultContext.VerifyKZGProof(commitmentBytes, zBytes, yBytes, proofBytes)
 }
 
 /*
@@ -345,25 +363,7 @@ VerifyBlobKZGProof is the binding for:
 	    const KZGSettings *s);
 */
 func VerifyBlobKZGProof(blob *Blob, commitmentBytes, proofBytes Bytes48) (bool, error) {
-	if !loaded {
-		panic("trusted setup isn't loaded")
-	}
-	if blob == nil {
-		return false, ErrBadArgs
-	}
-
-	var result C.bool
-	ret := C.verify_blob_kzg_proof(
-		&result,
-		(*C.Blob)(unsafe.Pointer(blob)),
-		(*C.Bytes48)(unsafe.Pointer(&commitmentBytes)),
-		(*C.Bytes48)(unsafe.Pointer(&proofBytes)),
-		&settings)
-
-	if ret != C.C_KZG_OK {
-		return false, makeErrorFromRet(ret)
-	}
-	return bool(result), nil
+	return defaultContext.VerifyBlobKZGProof(blob, commitmentBytes, proofBytes)
 }
 
 /*
@@ -375,28 +375,12 @@ VerifyBlobKZGProofBatch is the binding for:
 	    const Bytes48 *commitments_bytes,
 	    const Bytes48 *proofs_bytes,
 	    const KZGSettings *s);
+
+Batches too large for the memory budget are verified in chunks, see
+BatchChunking.
 */
 func VerifyBlobKZGProofBatch(blobs []Blob, commitmentsBytes, proofsBytes []Bytes48) (bool, error) {
-	if !loaded {
-		panic("trusted setup isn't loaded")
-	}
-	if len(blobs) != len(commitmentsBytes) || len(blobs) != len(proofsBytes) {
-		return false, ErrBadArgs
-	}
-
-	var result C.bool
-	ret := C.verify_blob_kzg_proof_batch(
-		&result,
-		*(**C.Blob)(unsafe.Pointer(&blobs)),
-		*(**C.Bytes48)(unsafe.Pointer(&commitmentsBytes)),
-		*(**C.Bytes48)(unsafe.Pointer(&proofsBytes)),
-		(C.uint64_t)(len(blobs)),
-		&settings)
-
-	if ret != C.C_KZG_OK {
-		return false, makeErrorFromRet(ret)
-	}
-	return bool(result), nil
+	return defaultContext.VerifyBlobKZGProofBatch(blobs, commitmentsBytes, proofsBytes)
 }
 
 /*
@@ -409,22 +393,18 @@ ComputeCellsAndKZGProofs is the binding for:
 	    const KZGSettings *s);
 */
 func ComputeCellsAndKZGProofs(blob *Blob) ([CellsPerExtBlob]Cell, [CellsPerExtBlob]KZGProof, error) {
-	if !loaded {
-		panic("trusted setup isn't loaded")
-	}
-
-	cells := [CellsPerExtBlob]Cell{}
-	proofs := [CellsPerExtBlob]KZGProof{}
-	ret := C.compute_cells_and_kzg_proofs(
-		(*C.Cell)(unsafe.Pointer(&cells)),
-		(*C.KZGProof)(unsafe.Pointer(&proofs)),
-		(*C.Blob)(unsafe.Pointer(blob)),
-		&settings)
+	return defaultContext.ComputeCellsAndKZGProofs(blob)
+}
 
-	if ret != C.C_KZG_OK {
-		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, makeErrorFromRet(ret)
-	}
-	return cells, proofs, nil
+/*
+ComputeCellsAndKZGProofsInto is like ComputeCellsAndKZGProofs, but it writes the
+results into caller-provided arrays instead of returning them by value. This
+avoids copying (and growing the goroutine stack by) several hundred kilobytes
+on every call. Either cells or proofs may be nil, in which case they won't be
+computed, but not both.
+*/
+func ComputeCellsAndKZGProofsInto(cells *[CellsPerExtBlob]Cell, proofs *[CellsPerExtBlob]KZGProof, blob *Blob) error {
+	return defaultContext.ComputeCellsAndKZGProofsInto(cells, proofs, blob)
 }
 
 /*
@@ -439,27 +419,16 @@ RecoverCellsAndKZGProofs is the binding for:
 	    const KZGSettings *s);
 */
 func RecoverCellsAndKZGProofs(cellIndices []uint64, cells []Cell) ([CellsPerExtBlob]Cell, [CellsPerExtBlob]KZGProof, error) {
-	if !loaded {
-		panic("trusted setup isn't loaded")
-	}
-	if len(cellIndices) != len(cells) {
-		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, ErrBadArgs
-	}
+	return defaultContext.RecoverCellsAndKZGProofs(cellIndices, cells)
+}
 
-	recoveredCells := [CellsPerExtBlob]Cell{}
-	recoveredProofs := [CellsPerExtBlob]KZGProof{}
-	ret := C.recover_cells_and_kzg_proofs(
-		(*C.Cell)(unsafe.Pointer(&recoveredCells)),
-		(*C.KZGProof)(unsafe.Pointer(&recoveredProofs)),
-		*(**C.uint64_t)(unsafe.Pointer(&cellIndices)),
-		*(**C.Cell)(unsafe.Pointer(&cells)),
-		(C.uint64_t)(len(cells)),
-		&settings)
-
-	if ret != C.C_KZG_OK {
-		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, makeErrorFromRet(ret)
-	}
-	return recoveredCells, recoveredProofs, nil
+/*
+RecoverCellsAndKZGProofsInto is like RecoverCellsAndKZGProofs, but it writes
+the results into caller-provided arrays instead of returning them by value.
+If recoveredProofs is nil, the proofs won't be recomputed.
+*/
+func RecoverCellsAndKZGProofsInto(recoveredCells *[CellsPerExtBlob]Cell, recoveredProofs *[CellsPerExtBlob]KZGProof, cellIndices []uint64, cells []Cell) error {
+	return defaultContext.RecoverCellsAndKZGProofsInto(recoveredCells, recoveredProofs, cellIndices, cells)
 }
 
 /*
@@ -473,27 +442,10 @@ VerifyCellKZGProofBatch is the binding for:
 	    const Bytes48 *proofs_bytes,
 	    uint64_t num_cells,
 	    const KZGSettings *s);
+
+Batches too large for the memory budget are verified in chunks, see
+BatchChunking.
 */
 func VerifyCellKZGProofBatch(commitmentsBytes []Bytes48, cellIndices []uint64, cells []Cell, proofsBytes []Bytes48) (bool, error) {
-	if !loaded {
-		panic("trusted setup isn't loaded")
-	}
-	if len(commitmentsBytes) != len(cells) || len(cellIndices) != len(cells) || len(proofsBytes) != len(cells) {
-		return false, ErrBadArgs
-	}
-
-	var result C.bool
-	ret := C.verify_cell_kzg_proof_batch(
-		&result,
-		*(**C.Bytes48)(unsafe.Pointer(&commitmentsBytes)),
-		*(**C.uint64_t)(unsafe.Pointer(&cellIndices)),
-		*(**C.Cell)(unsafe.Pointer(&cells)),
-		*(**C.Bytes48)(unsafe.Pointer(&proofsBytes)),
-		(C.uint64_t)(len(cells)),
-		&settings)
-
-	if ret != C.C_KZG_OK {
-		return false, makeErrorFromRet(ret)
-	}
-	return bool(result), nil
+	return defaultContext.VerifyCellKZGProofBatch(commitmentsBytes, cellIndices, cells, proofsBytes)
 }
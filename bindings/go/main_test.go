@@ -630,6 +630,48 @@ func TestPartialRecover(t *testing.T) {
 	}
 }
 
+func TestSelfTest(t *testing.T) {
+	require.NoError(t, SelfTest())
+}
+
+func TestSelfTestFaults(t *testing.T) {
+	defer SetFaultInjector(nil)
+
+	fi := NewFaultInjector()
+	fi.FailNthCall("ComputeCellsAndKZGProofs", 1, nil)
+	SetFaultInjector(fi)
+	require.ErrorIs(t, SelfTest(), ErrSelfTestFailed)
+
+	commitment, err := BlobToKZGCommitment(selfTestBlob())
+	require.NoError(t, err)
+	fi = NewFaultInjector()
+	fi.RejectCommitment(Bytes48(commitment))
+	SetFaultInjector(fi)
+	require.ErrorIs(t, SelfTest(), ErrSelfTestFailed)
+
+	SetFaultInjector(nil)
+	require.NoError(t, SelfTest())
+}
+
+func TestSelfTestOnLoad(t *testing.T) {
+	EnableSelfTestOnLoad(true)
+	defer EnableSelfTestOnLoad(false)
+	defer SetFaultInjector(nil)
+
+	c, err := NewContextFromFile("../../src/trusted_setup.txt", 0)
+	require.NoError(t, err)
+	c.Free()
+
+	fi := NewFaultInjector()
+	fi.FailNthCall("BlobToKZGCommitment", 1, nil)
+	SetFaultInjector(fi)
+	_, err = NewContextFromFile("../../src/trusted_setup.txt", 0)
+	require.ErrorIs(t, err, ErrSelfTestFailed)
+	var opErr *Error
+	require.ErrorAs(t, err, &opErr)
+	require.Equal(t, "LoadTrustedSetupFile", opErr.Op)
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // Benchmarks
 ///////////////////////////////////////////////////////////////////////////////
@@ -843,3 +885,68 @@ func Benchmark(b *testing.B) {
 		})
 	}
 }
+
+// BenchmarkBlockImport simulates importing a block with blobs end-to-end: the
+// blobs, commitments, and proofs are parsed from their hex encodings, the blob
+// proofs are batch verified, and a sample of cells from each blob is verified.
+func BenchmarkBlockImport(b *testing.B) {
+	const maxBlobs = 64
+	const sampledColumns = 8
+
+	blobHexes := make([][]byte, maxBlobs)
+	commitmentHexes := make([][]byte, maxBlobs)
+	proofHexes := make([][]byte, maxBlobs)
+	blobCells := make([][CellsPerExtBlob]Cell, maxBlobs)
+	blobCellProofs := make([][CellsPerExtBlob]KZGProof, maxBlobs)
+	for i := 0; i < maxBlobs; i++ {
+		var blob Blob
+		fillBlobRandom(&blob, int64(i))
+		commitment, err := BlobToKZGCommitment(&blob)
+		require.NoError(b, err)
+		proof, err := ComputeBlobKZGProof(&blob, Bytes48(commitment))
+		require.NoError(b, err)
+		require.NoError(b, ComputeCellsAndKZGProofsInto(&blobCells[i], &blobCellProofs[i], &blob))
+		blobHexes[i] = []byte(fmt.Sprintf("0x%x", blob[:]))
+		commitmentHexes[i] = []byte(fmt.Sprintf("0x%x", commitment[:]))
+		proofHexes[i] = []byte(fmt.Sprintf("0x%x", proof[:]))
+	}
+
+	for _, numBlobs := range []int{6, 16, 32, 64} {
+		b.Run(fmt.Sprintf("blobs=%d", numBlobs), func(b *testing.B) {
+			blobs := make([]Blob, numBlobs)
+			commitments := make([]Bytes48, numBlobs)
+			proofs := make([]Bytes48, numBlobs)
+			numCells := numBlobs * sampledColumns
+			cellCommitments := make([]Bytes48, numCells)
+			cellIndices := make([]uint64, numCells)
+			cells := make([]Cell, numCells)
+			cellProofs := make([]Bytes48, numCells)
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				for i := 0; i < numBlobs; i++ {
+					require.NoError(b, blobs[i].UnmarshalText(blobHexes[i]))
+					require.NoError(b, commitments[i].UnmarshalText(commitmentHexes[i]))
+					require.NoError(b, proofs[i].UnmarshalText(proofHexes[i]))
+				}
+
+				ok, err := VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+				require.NoError(b, err)
+				require.True(b, ok)
+
+				for i := 0; i < numBlobs; i++ {
+					for j := 0; j < sampledColumns; j++ {
+						k := i*sampledColumns + j
+						column := (n + j*CellsPerExtBlob/sampledColumns) % CellsPerExtBlob
+						cellCommitments[k] = commitments[i]
+						cellIndices[k] = uint64(column)
+						cells[k] = blobCells[i][column]
+						cellProofs[k] = Bytes48(blobCellProofs[i][column])
+					}
+				}
+				ok, err = VerifyCellKZGProofBatch(cellCommitments, cellIndices, cells, cellProofs)
+				require.NoError(b, err)
+				require.True(b, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+/*
+Package mainnet embeds the mainnet trusted setup, so clients can load it
+without any filesystem access:
+
+	err := ckzg4844.LoadTrustedSetupFromReader(mainnet.TrustedSetup(), 0)
+
+It is a separate package so that only the binaries which import it carry the
+setup, which is about 800KB.
+*/
+package mainnet
+
+import (
+	_ "embed"
+	"io"
+	"strings"
+)
+
+// trustedSetup is a copy of src/trusted_setup.txt.
+//
+//go:embed trusted_setup.txt
+var trustedSetup string
+
+// TrustedSetup returns the mainnet trusted setup in the format read by
+// ckzg4844.LoadTrustedSetupFromReader.
+func TrustedSetup() io.Reader {
+	return strings.NewReader(trustedSetup)
+}
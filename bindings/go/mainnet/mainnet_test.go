@@ -0,0 +1,19 @@
+package mainnet
+
+import (
+	"os"
+	"testing"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustedSetup(t *testing.T) {
+	expected, err := os.ReadFile("../../../src/trusted_setup.txt")
+	require.NoError(t, err)
+	require.Equal(t, string(expected), trustedSetup)
+
+	require.NoError(t, ckzg4844.LoadTrustedSetupFromReader(TrustedSetup(), 0))
+	defer ckzg4844.FreeTrustedSetup()
+	require.NoError(t, ckzg4844.SelfTest())
+}
@@ -0,0 +1,104 @@
+package ckzg4844
+
+import (
+	"encoding"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type hexType interface {
+	encoding.TextMarshaler
+	json.Marshaler
+	String() string
+}
+
+// requireRoundTrip decodes the hex string s into v, and checks that every
+// encoding of v gives s back.
+func requireRoundTrip[T any, P interface {
+	*T
+	encoding.TextUnmarshaler
+	json.Unmarshaler
+}](t *testing.T, s string) {
+	var v T
+	require.NoError(t, P(&v).UnmarshalText([]byte(s)))
+	encoder := any(v).(hexType)
+	text, err := encoder.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, s, string(text))
+	require.Equal(t, s, encoder.String())
+
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	require.Equal(t, `"`+s+`"`, string(data))
+	var decoded T
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, v, decoded)
+}
+
+func TestMarshalReferenceTests(t *testing.T) {
+	type Test struct {
+		Input struct {
+			Blob string `yaml:"blob"`
+			Z    string `yaml:"z"`
+		}
+		Output any `yaml:"output"`
+	}
+
+	for _, pattern := range []string{computeCellsAndKZGProofsTests, computeKZGProofTests} {
+		tests, err := filepath.Glob(pattern)
+		require.NoError(t, err)
+		require.NotEmpty(t, tests)
+		for _, testPath := range tests {
+			testFile, err := os.Open(testPath)
+			require.NoError(t, err)
+			var test Test
+			require.NoError(t, yaml.NewDecoder(testFile).Decode(&test))
+			require.NoError(t, testFile.Close())
+			if test.Output == nil {
+				continue
+			}
+
+			requireRoundTrip[Blob](t, test.Input.Blob)
+			output := test.Output.([]any)
+			if test.Input.Z != "" {
+				requireRoundTrip[Bytes32](t, test.Input.Z)
+				requireRoundTrip[KZGProof](t, output[0].(string))
+				requireRoundTrip[Bytes32](t, output[1].(string))
+				continue
+			}
+			for _, cell := range output[0].([]any) {
+				requireRoundTrip[Cell](t, cell.(string))
+			}
+			for _, proof := range output[1].([]any) {
+				requireRoundTrip[KZGProof](t, proof.(string))
+				requireRoundTrip[Bytes48](t, proof.(string))
+				requireRoundTrip[KZGCommitment](t, proof.(string))
+			}
+		}
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	type payload struct {
+		Commitment KZGCommitment `json:"commitment"`
+		Proof      *KZGProof     `json:"proof"`
+		Cells      []Cell        `json:"cells"`
+		Missing    Bytes48       `json:"missing"`
+	}
+	in := payload{Commitment: KZGCommitment{1}, Proof: &KZGProof{2}, Cells: []Cell{{3}}}
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+	var out payload
+	require.NoError(t, json.Unmarshal(data, &out))
+	require.Equal(t, in, out)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"commitment":null}`), &out))
+	require.Equal(t, KZGCommitment{1}, out.Commitment)
+	require.Error(t, json.Unmarshal([]byte(`{"commitment":"0x01"}`), &out))
+	require.Error(t, json.Unmarshal([]byte(`{"commitment":1}`), &out))
+}
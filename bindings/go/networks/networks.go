@@ -0,0 +1,282 @@
+// Package networks is a registry of the blob parameters and trusted setup of
+// each network, so that tools supporting mainnet, testnets and devnets can
+// configure the whole blob stack from one place. Mainnet and the public
+// testnets are registered by default; devnets and L2s can Register their own.
+// A ForkTracker calls hooks as the entries of a network's schedule activate.
+package networks
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"sync"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/mainnet"
+)
+
+// Constants shared by all networks.
+const (
+	// GasPerBlob is the blob gas used by each blob.
+	GasPerBlob = 1 << 17
+	// MinBaseFeePerBlobGas is the minimum blob base fee, in wei.
+	MinBaseFeePerBlobGas = 1
+	// MaxBlobCommitmentsPerBlock is the limit of the commitments list in a
+	// beacon block body.
+	MaxBlobCommitmentsPerBlock = 4096
+	// NumberOfColumns is the number of data columns of the extended blobs.
+	NumberOfColumns = ckzg4844.CellsPerExtBlob
+)
+
+var (
+	// ErrUnknownNetwork is returned by Lookup for a name which isn't
+	// registered.
+	ErrUnknownNetwork = errors.New("unknown network")
+	// ErrInvalidNetwork is returned by Register for an invalid network, or one
+	// whose name is already registered.
+	ErrInvalidNetwork = errors.New("invalid network")
+)
+
+// BlobParameters are the blob parameters in effect from Epoch onwards, until
+// the next entry of the schedule.
+type BlobParameters struct {
+	// Fork names the fork or blob parameter only fork activating them.
+	Fork  string
+	Epoch uint64
+	// TargetBlobsPerBlock and MaxBlobsPerBlock are counts of blobs.
+	TargetBlobsPerBlock uint64
+	MaxBlobsPerBlock    uint64
+	// BaseFeeUpdateFraction controls how fast the blob base fee changes.
+	BaseFeeUpdateFraction uint64
+}
+
+// Network is the blob configuration of a network.
+type Network struct {
+	Name string
+	// Schedule lists the blob parameters of the network, ordered by epoch.
+	// The first entry activates blobs.
+	Schedule []BlobParameters
+	// TrustedSetup returns the trusted setup of the network, in the format
+	// read by ckzg4844.NewContextFromReader. If it is nil, the network uses
+	// the mainnet trusted setup.
+	TrustedSetup func() io.Reader
+}
+
+// NewContext loads the trusted setup of the network into a new context.
+func (n *Network) NewContext(precompute uint) (*ckzg4844.Context, error) {
+	trustedSetup := n.TrustedSetup
+	if trustedSetup == nil {
+		trustedSetup = mainnet.TrustedSetup
+	}
+	return ckzg4844.NewContextFromReader(trustedSetup(), precompute)
+}
+
+// ParametersAt returns the blob parameters in effect at epoch. It returns
+// false if blobs aren't active yet.
+func (n *Network) ParametersAt(epoch uint64) (BlobParameters, bool) {
+	i := sort.Search(len(n.Schedule), func(i int) bool {
+		return n.Schedule[i].Epoch > epoch
+	})
+	if i == 0 {
+		return BlobParameters{}, false
+	}
+	return n.Schedule[i-1], true
+}
+
+// MaxBlobGasPerBlock returns the maximum blob gas of a block.
+func (p BlobParameters) MaxBlobGasPerBlock() uint64 {
+	return p.MaxBlobsPerBlock * GasPerBlob
+}
+
+// TargetBlobGasPerBlock returns the target blob gas of a block.
+func (p BlobParameters) TargetBlobGasPerBlock() uint64 {
+	return p.TargetBlobsPerBlock * GasPerBlob
+}
+
+// BlobBaseFee returns the base fee per blob gas for the given excess blob gas,
+// as computed by fake_exponential in EIP-4844. Parameters without a
+// BaseFeeUpdateFraction, such as the zero value, which Register rejects, give
+// MinBaseFeePerBlobGas.
+func (p BlobParameters) BlobBaseFee(excessBlobGas uint64) *big.Int {
+	factor := big.NewInt(MinBaseFeePerBlobGas)
+	if p.BaseFeeUpdateFraction == 0 {
+		return factor
+	}
+	numerator := new(big.Int).SetUint64(excessBlobGas)
+	denominator := new(big.Int).SetUint64(p.BaseFeeUpdateFraction)
+
+	output := new(big.Int)
+	accum := new(big.Int).Mul(factor, denominator)
+	for i := int64(1); accum.Sign() > 0; i++ {
+		output.Add(output, accum)
+		accum.Mul(accum, numerator)
+		accum.Div(accum, denominator)
+		accum.Div(accum, big.NewInt(i))
+	}
+	return output.Div(output, denominator)
+}
+
+func (n *Network) validate() error {
+	if n.Name == "" {
+		return fmt.Errorf("%w: missing name", ErrInvalidNetwork)
+	}
+	if len(n.Schedule) == 0 {
+		return fmt.Errorf("%w: %s: empty schedule", ErrInvalidNetwork, n.Name)
+	}
+	for i, p := range n.Schedule {
+		if i > 0 && p.Epoch <= n.Schedule[i-1].Epoch {
+			return fmt.Errorf("%w: %s: schedule isn't ordered by epoch", ErrInvalidNetwork, n.Name)
+		}
+		if p.TargetBlobsPerBlock > p.MaxBlobsPerBlock || p.MaxBlobsPerBlock > MaxBlobCommitmentsPerBlock {
+			return fmt.Errorf("%w: %s: invalid blob counts at epoch %d", ErrInvalidNetwork, n.Name, p.Epoch)
+		}
+		if p.BaseFeeUpdateFraction == 0 {
+			return fmt.Errorf("%w: %s: zero base fee update fraction at epoch %d", ErrInvalidNetwork, n.Name, p.Epoch)
+		}
+	}
+	return nil
+}
+
+/*
+ForkTracker follows the epoch of a network and calls hooks when an entry of its
+schedule activates, so that components can reconfigure themselves, for
+example resize a blob pool, at the fork rather than polling ParametersAt.
+*/
+type ForkTracker struct {
+	network Network
+
+	mu sync.Mutex
+	// active is the number of schedule entries which are active.
+	active int
+	hooks  []func(BlobParameters)
+}
+
+// NewForkTracker returns a ForkTracker for n, before any epoch is known.
+func NewForkTracker(n Network) *ForkTracker {
+	n.Schedule = append([]BlobParameters(nil), n.Schedule...)
+	return &ForkTracker{network: n}
+}
+
+// OnFork adds a hook which is called with the blob parameters of every
+// schedule entry which activates from then on.
+func (t *ForkTracker) OnFork(hook func(BlobParameters)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.hooks = append(t.hooks, hook)
+}
+
+/*
+Advance tells the tracker that the network reached epoch. The hooks are called,
+in the order they were added, for every schedule entry which activated since
+the previous call, oldest first. Epochs before the previous one are ignored.
+The hooks are called with the tracker locked, so they must not call it.
+*/
+func (t *ForkTracker) Advance(epoch uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for t.active < len(t.network.Schedule) && t.network.Schedule[t.active].Epoch <= epoch {
+		p := t.network.Schedule[t.active]
+		t.active++
+		for _, hook := range t.hooks {
+			hook(p)
+		}
+	}
+}
+
+// Current returns the blob parameters of the last entry which activated. It
+// returns false if blobs aren't active yet.
+func (t *ForkTracker) Current() (BlobParameters, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.active == 0 {
+		return BlobParameters{}, false
+	}
+	return t.network.Schedule[t.active-1], true
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]Network{}
+)
+
+// Register adds a network to the registry. It fails if a network with the same
+// name is already registered.
+func Register(n Network) error {
+	if err := n.validate(); err != nil {
+		return err
+	}
+	n.Schedule = append([]BlobParameters(nil), n.Schedule...)
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := registry[n.Name]; ok {
+		return fmt.Errorf("%w: %s is already registered", ErrInvalidNetwork, n.Name)
+	}
+	registry[n.Name] = n
+	return nil
+}
+
+// Lookup returns the registered network with the given name.
+func Lookup(name string) (Network, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	n, ok := registry[name]
+	if !ok {
+		return Network{}, fmt.Errorf("%w: %s", ErrUnknownNetwork, name)
+	}
+	n.Schedule = append([]BlobParameters(nil), n.Schedule...)
+	return n, nil
+}
+
+// Names returns the names of the registered networks, sorted.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Blob parameters which are the same on every network.
+var (
+	deneb   = BlobParameters{Fork: "deneb", TargetBlobsPerBlock: 3, MaxBlobsPerBlock: 6, BaseFeeUpdateFraction: 3338477}
+	electra = BlobParameters{Fork: "electra", TargetBlobsPerBlock: 6, MaxBlobsPerBlock: 9, BaseFeeUpdateFraction: 5007716}
+	bpo1    = BlobParameters{Fork: "bpo1", TargetBlobsPerBlock: 10, MaxBlobsPerBlock: 15, BaseFeeUpdateFraction: 8346193}
+	bpo2    = BlobParameters{Fork: "bpo2", TargetBlobsPerBlock: 14, MaxBlobsPerBlock: 21, BaseFeeUpdateFraction: 11684671}
+)
+
+func at(p BlobParameters, epoch uint64) BlobParameters {
+	p.Epoch = epoch
+	return p
+}
+
+func fulu(epoch uint64) BlobParameters {
+	p := at(electra, epoch)
+	p.Fork = "fulu"
+	return p
+}
+
+func init() {
+	for _, n := range []Network{
+		{Name: "mainnet", Schedule: []BlobParameters{
+			at(deneb, 269568), at(electra, 364032), fulu(411392), at(bpo1, 412672), at(bpo2, 419072),
+		}},
+		{Name: "sepolia", Schedule: []BlobParameters{
+			at(deneb, 132608), at(electra, 222464), fulu(272640), at(bpo1, 274176), at(bpo2, 275712),
+		}},
+		{Name: "holesky", Schedule: []BlobParameters{
+			at(deneb, 29696), at(electra, 115968), fulu(165120), at(bpo1, 166400), at(bpo2, 167936),
+		}},
+		{Name: "hoodi", Schedule: []BlobParameters{
+			at(deneb, 0), at(electra, 2048), fulu(50688), at(bpo1, 52480), at(bpo2, 54016),
+		}},
+	} {
+		if err := Register(n); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package networks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMainnet(t *testing.T) {
+	mainnet, err := Lookup("mainnet")
+	require.NoError(t, err)
+
+	_, ok := mainnet.ParametersAt(269567)
+	require.False(t, ok)
+	p, ok := mainnet.ParametersAt(269568)
+	require.True(t, ok)
+	require.Equal(t, "deneb", p.Fork)
+	require.Equal(t, uint64(786432), p.MaxBlobGasPerBlock())
+	require.Equal(t, uint64(393216), p.TargetBlobGasPerBlock())
+	p, _ = mainnet.ParametersAt(400000)
+	require.Equal(t, "electra", p.Fork)
+	p, _ = mainnet.ParametersAt(1 << 40)
+	require.Equal(t, "bpo2", p.Fork)
+	require.Equal(t, uint64(21), p.MaxBlobsPerBlock)
+}
+
+func TestBlobBaseFee(t *testing.T) {
+	p := BlobParameters{BaseFeeUpdateFraction: 3338477}
+	require.Equal(t, int64(1), p.BlobBaseFee(0).Int64())
+	require.Equal(t, int64(1), p.BlobBaseFee(2314057).Int64())
+	require.Equal(t, int64(2), p.BlobBaseFee(2314058).Int64())
+	require.Equal(t, int64(23), p.BlobBaseFee(10*1024*1024).Int64())
+	require.Equal(t, int64(MinBaseFeePerBlobGas), BlobParameters{}.BlobBaseFee(1<<30).Int64())
+}
+
+func TestForkTracker(t *testing.T) {
+	mainnet, err := Lookup("mainnet")
+	require.NoError(t, err)
+	tracker := NewForkTracker(mainnet)
+	var forks []string
+	tracker.OnFork(func(p BlobParameters) { forks = append(forks, p.Fork) })
+
+	tracker.Advance(0)
+	_, ok := tracker.Current()
+	require.False(t, ok)
+	require.Empty(t, forks)
+
+	tracker.Advance(400000)
+	require.Equal(t, []string{"deneb", "electra"}, forks)
+	tracker.Advance(400001)
+	tracker.Advance(0)
+	require.Len(t, forks, 2)
+	tracker.Advance(1 << 40)
+	require.Equal(t, []string{"deneb", "electra", "fulu", "bpo1", "bpo2"}, forks)
+	p, ok := tracker.Current()
+	require.True(t, ok)
+	require.Equal(t, "bpo2", p.Fork)
+}
+
+func TestNewContext(t *testing.T) {
+	// The public testnets use the mainnet trusted setup.
+	hoodi, err := Lookup("hoodi")
+	require.NoError(t, err)
+	c, err := hoodi.NewContext(0)
+	require.NoError(t, err)
+	defer c.Free()
+	require.NoError(t, c.SelfTest())
+}
+
+func TestRegister(t *testing.T) {
+	devnet := Network{Name: "test-devnet", Schedule: []BlobParameters{
+		{Fork: "deneb", Epoch: 0, TargetBlobsPerBlock: 32, MaxBlobsPerBlock: 48, BaseFeeUpdateFraction: 1},
+	}}
+	require.NoError(t, Register(devnet))
+	require.ErrorIs(t, Register(devnet), ErrInvalidNetwork)
+	n, err := Lookup("test-devnet")
+	require.NoError(t, err)
+	require.Equal(t, devnet, n)
+	require.Contains(t, Names(), "test-devnet")
+
+	_, err = Lookup("missing")
+	require.ErrorIs(t, err, ErrUnknownNetwork)
+	require.ErrorIs(t, Register(Network{Name: "empty"}), ErrInvalidNetwork)
+}
@@ -0,0 +1,178 @@
+package ckzg4844
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+/*
+VerifyOptions tunes a batch verification, so that call sites such as gossip
+validation and block import can each get the behavior they need. The zero
+value verifies the whole batch at once and reports only whether all of it
+verifies.
+*/
+type VerifyOptions struct {
+	// Parallelism is the number of sub-batches verified concurrently.
+	Parallelism int
+	// ValidateEncodings makes inputs which aren't validly encoded, such as a
+	// commitment which isn't a point, fail the call with ErrBadArgs. Otherwise
+	// they are treated like items which don't verify.
+	ValidateEncodings bool
+	// FailFast stops verifying as soon as a sub-batch doesn't verify. Items
+	// is nil when it stops early.
+	FailFast bool
+	// ReturnPerItem fills VerifyResult.Items, verifying the items of
+	// sub-batches which don't verify one by one.
+	ReturnPerItem bool
+	// Backend performs the verifications. Defaults to NativeBackend.
+	Backend Backend
+}
+
+// VerifyResult is the result of a batch verification with options.
+type VerifyResult struct {
+	// OK is whether every item verifies.
+	OK bool
+	// Items holds whether each item verifies, if ReturnPerItem was set.
+	Items []bool
+}
+
+// verifyWithOptions verifies n items in sub-batches with verify, which
+// verifies items start to end.
+func verifyWithOptions(n int, opts VerifyOptions, verify func(start, end int) (bool, error)) (VerifyResult, error) {
+	parallelism := opts.Parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	chunks := parallelism
+	if opts.FailFast {
+		// Smaller sub-batches leave more work to skip after a failure.
+		chunks *= 4
+	}
+	if chunks > n {
+		chunks = n
+	}
+	if chunks < 1 {
+		chunks = 1
+	}
+	chunkSize := (n + chunks - 1) / chunks
+	if chunkSize < 1 {
+		chunkSize = 1
+	}
+	return verifyChunks(n, chunkSize, parallelism, opts, verify)
+}
+
+// verifyChunks verifies n items in sub-batches of chunkSize items with verify,
+// using parallelism goroutines.
+func verifyChunks(n, chunkSize, parallelism int, opts VerifyOptions, verify func(start, end int) (bool, error)) (VerifyResult, error) {
+	check := func(start, end int) (bool, error) {
+		ok, err := verify(start, end)
+		if !opts.ValidateEncodings && errors.Is(err, ErrBadArgs) {
+			return false, nil
+		}
+		return ok, err
+	}
+
+	var items []bool
+	if opts.ReturnPerItem {
+		items = make([]bool, n)
+	}
+	var (
+		next     atomic.Int64
+		stopped  atomic.Bool
+		failed   atomic.Bool
+		errOnce  sync.Once
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				start := int(next.Add(int64(chunkSize))) - chunkSize
+				if start >= n || stopped.Load() {
+					return
+				}
+				end := start + chunkSize
+				if end > n {
+					end = n
+				}
+				ok, err := check(start, end)
+				if err != nil {
+					errOnce.Do(func() { firstErr = err })
+					stopped.Store(true)
+					return
+				}
+				if ok {
+					for i := start; i < end && items != nil; i++ {
+						items[i] = true
+					}
+					continue
+				}
+				failed.Store(true)
+				if opts.FailFast {
+					stopped.Store(true)
+					return
+				}
+				for i := start; i < end && items != nil; i++ {
+					ok, err := check(i, i+1)
+					if err != nil {
+						errOnce.Do(func() { firstErr = err })
+						stopped.Store(true)
+						return
+					}
+					items[i] = ok
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return VerifyResult{}, firstErr
+	}
+	if opts.FailFast && failed.Load() {
+		items = nil
+	}
+	return VerifyResult{OK: !failed.Load(), Items: items}, nil
+}
+
+// VerifyBlobKZGProofBatchWithOptions is VerifyBlobKZGProofBatch tuned by opts.
+func VerifyBlobKZGProofBatchWithOptions(blobs []Blob, commitmentsBytes, proofsBytes []Bytes48, opts VerifyOptions) (VerifyResult, error) {
+	if err := checkBlobBatchLengths("VerifyBlobKZGProofBatch", blobs, commitmentsBytes, proofsBytes); err != nil {
+		return VerifyResult{}, err
+	}
+	backend := opts.Backend
+	if backend == nil {
+		backend = NativeBackend{}
+	}
+	result, err := verifyWithOptions(len(blobs), opts, func(start, end int) (bool, error) {
+		return backend.VerifyBlobKZGProofBatch(blobs[start:end], commitmentsBytes[start:end], proofsBytes[start:end])
+	})
+	if err != nil {
+		return VerifyResult{}, opError("VerifyBlobKZGProofBatch", err, func() error {
+			return diagnoseBlobBatch(blobs, commitmentsBytes, proofsBytes)
+		})
+	}
+	return result, nil
+}
+
+// VerifyCellKZGProofBatchWithOptions is VerifyCellKZGProofBatch tuned by opts.
+func VerifyCellKZGProofBatchWithOptions(commitmentsBytes []Bytes48, cellIndices []uint64, cells []Cell, proofsBytes []Bytes48, opts VerifyOptions) (VerifyResult, error) {
+	if err := checkCellBatchLengths("VerifyCellKZGProofBatch", commitmentsBytes, cellIndices, cells, proofsBytes); err != nil {
+		return VerifyResult{}, err
+	}
+	backend := opts.Backend
+	if backend == nil {
+		backend = NativeBackend{}
+	}
+	result, err := verifyWithOptions(len(cells), opts, func(start, end int) (bool, error) {
+		return backend.VerifyCellKZGProofBatch(commitmentsBytes[start:end], cellIndices[start:end], cells[start:end], proofsBytes[start:end])
+	})
+	if err != nil {
+		return VerifyResult{}, opError("VerifyCellKZGProofBatch", err, func() error {
+			return diagnoseCellBatch(commitmentsBytes, cellIndices, cells, proofsBytes)
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package ckzg4844
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyBlobKZGProofBatchWithOptions(t *testing.T) {
+	var blobs []Blob
+	var commitments, proofs []Bytes48
+	for i := 0; i < 6; i++ {
+		blob := selfTestBlob()
+		blob[31] = byte(i)
+		commitment, err := BlobToKZGCommitment(blob)
+		require.NoError(t, err)
+		proof, err := ComputeBlobKZGProof(blob, Bytes48(commitment))
+		require.NoError(t, err)
+		blobs = append(blobs, *blob)
+		commitments = append(commitments, Bytes48(commitment))
+		proofs = append(proofs, Bytes48(proof))
+	}
+
+	result, err := VerifyBlobKZGProofBatchWithOptions(blobs, commitments, proofs, VerifyOptions{Parallelism: 3, ReturnPerItem: true})
+	require.NoError(t, err)
+	require.True(t, result.OK)
+	require.Equal(t, []bool{true, true, true, true, true, true}, result.Items)
+
+	proofs[4] = proofs[0]
+	commitments[1] = Bytes48{}
+	result, err = VerifyBlobKZGProofBatchWithOptions(blobs, commitments, proofs, VerifyOptions{Parallelism: 2, ReturnPerItem: true})
+	require.NoError(t, err)
+	require.False(t, result.OK)
+	require.Equal(t, []bool{true, false, true, true, false, true}, result.Items)
+
+	_, err = VerifyBlobKZGProofBatchWithOptions(blobs, commitments, proofs, VerifyOptions{ValidateEncodings: true})
+	require.ErrorIs(t, err, ErrBadArgs)
+
+	result, err = VerifyBlobKZGProofBatchWithOptions(blobs, commitments, proofs, VerifyOptions{FailFast: true, ReturnPerItem: true})
+	require.NoError(t, err)
+	require.False(t, result.OK)
+	require.Nil(t, result.Items)
+}
+
+func TestVerifyCellKZGProofBatchWithOptions(t *testing.T) {
+	blob := selfTestBlob()
+	commitment, err := BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+	cells, proofs, err := ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+
+	commitments := []Bytes48{Bytes48(commitment), Bytes48(commitment), Bytes48(commitment)}
+	result, err := VerifyCellKZGProofBatchWithOptions(
+		commitments,
+		[]uint64{0, 1, 2},
+		[]Cell{cells[0], cells[2], cells[2]},
+		[]Bytes48{Bytes48(proofs[0]), Bytes48(proofs[1]), Bytes48(proofs[2])},
+		VerifyOptions{Parallelism: 2, ReturnPerItem: true})
+	require.NoError(t, err)
+	require.False(t, result.OK)
+	require.Equal(t, []bool{true, false, true}, result.Items)
+}
@@ -0,0 +1,176 @@
+package ckzg4844
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// ParallelOptions configures the ...Parallel functions.
+type ParallelOptions struct {
+	// Workers is the number of goroutines, each making its own calls into
+	// the C library. Defaults to GOMAXPROCS.
+	Workers int
+}
+
+func (opts ParallelOptions) workers(n int) int {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	return workers
+}
+
+// workerGroup is a Group which runs at most cap(sem) functions at a time, and
+// cancels its context on the first error.
+type workerGroup struct {
+	wg     sync.WaitGroup
+	sem    chan struct{}
+	cancel context.CancelFunc
+	once   sync.Once
+	err    error
+}
+
+func newWorkerGroup(workers int) (*workerGroup, context.Context) {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &workerGroup{sem: make(chan struct{}, workers), cancel: cancel}, ctx
+}
+
+func (g *workerGroup) Go(f func() error) {
+	g.sem <- struct{}{}
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer func() { <-g.sem }()
+		if err := f(); err != nil {
+			g.once.Do(func() {
+				g.err = err
+				g.cancel()
+			})
+		}
+	}()
+}
+
+func (g *workerGroup) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+	return g.err
+}
+
+// verifyParallel splits n items into one shard per worker and verifies the
+// shards concurrently with verify.
+func verifyParallel(n int, opts ParallelOptions, verify func(start, end int) (bool, error)) (bool, error) {
+	workers := opts.workers(n)
+	shardSize := (n + workers - 1) / workers
+	if shardSize < 1 {
+		shardSize = 1
+	}
+	result, err := verifyChunks(n, shardSize, workers, VerifyOptions{ValidateEncodings: true}, verify)
+	return result.OK, err
+}
+
+/*
+VerifyBlobKZGProofBatchParallel is like VerifyBlobKZGProofBatch, but shards the
+batch across goroutines which verify their shards concurrently, instead of
+verifying it in a single-threaded call. It is meant for verifying many blobs at
+once, for example after a sync burst. The result is the same as the one of
+VerifyBlobKZGProofBatch.
+*/
+func VerifyBlobKZGProofBatchParallel(blobs []Blob, commitmentsBytes, proofsBytes []Bytes48, opts ParallelOptions) (bool, error) {
+	if !defaultContext.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	if err := checkBlobBatchLengths("VerifyBlobKZGProofBatchParallel", blobs, commitmentsBytes, proofsBytes); err != nil {
+		return false, err
+	}
+	ok, err := verifyParallel(len(blobs), opts, func(start, end int) (bool, error) {
+		return VerifyBlobKZGProofBatch(blobs[start:end], commitmentsBytes[start:end], proofsBytes[start:end])
+	})
+	if err != nil {
+		return false, opError("VerifyBlobKZGProofBatchParallel", err, func() error {
+			return diagnoseBlobBatch(blobs, commitmentsBytes, proofsBytes)
+		})
+	}
+	return ok, nil
+}
+
+// VerifyCellKZGProofBatchParallel is VerifyCellKZGProofBatch sharded across
+// goroutines, like VerifyBlobKZGProofBatchParallel.
+func VerifyCellKZGProofBatchParallel(commitmentsBytes []Bytes48, cellIndices []uint64, cells []Cell, proofsBytes []Bytes48, opts ParallelOptions) (bool, error) {
+	if !defaultContext.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	if err := checkCellBatchLengths("VerifyCellKZGProofBatchParallel", commitmentsBytes, cellIndices, cells, proofsBytes); err != nil {
+		return false, err
+	}
+	ok, err := verifyParallel(len(cells), opts, func(start, end int) (bool, error) {
+		return VerifyCellKZGProofBatch(commitmentsBytes[start:end], cellIndices[start:end], cells[start:end], proofsBytes[start:end])
+	})
+	if err != nil {
+		return false, opError("VerifyCellKZGProofBatchParallel", err, func() error {
+			return diagnoseCellBatch(commitmentsBytes, cellIndices, cells, proofsBytes)
+		})
+	}
+	return ok, nil
+}
+
+/*
+BlobsToKZGCommitments computes the commitments to all blobs, in parallel on
+GOMAXPROCS goroutines, so that a block builder can commit to every blob of a
+block with one call. It returns the first error of any blob.
+*/
+func BlobsToKZGCommitments(blobs []Blob) ([]KZGCommitment, error) {
+	if !defaultContext.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	commitments := make([]KZGCommitment, len(blobs))
+	g, ctx := newWorkerGroup(ParallelOptions{}.workers(len(blobs)))
+	if err := GoBlobToKZGCommitments(ctx, g, blobs, commitments); err != nil {
+		return nil, err
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return commitments, nil
+}
+
+// ComputeBlobKZGProofs computes the proofs of all blobs in parallel, like
+// BlobsToKZGCommitments.
+func ComputeBlobKZGProofs(blobs []Blob, commitmentsBytes []Bytes48) ([]KZGProof, error) {
+	if !defaultContext.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	proofs := make([]KZGProof, len(blobs))
+	g, ctx := newWorkerGroup(ParallelOptions{}.workers(len(blobs)))
+	if err := GoComputeBlobKZGProofs(ctx, g, blobs, commitmentsBytes, proofs); err != nil {
+		return nil, err
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return proofs, nil
+}
+
+// ComputeCellsAndKZGProofsBatch computes the cells and cell proofs of all
+// blobs in parallel, like BlobsToKZGCommitments.
+func ComputeCellsAndKZGProofsBatch(blobs []Blob) ([][CellsPerExtBlob]Cell, [][CellsPerExtBlob]KZGProof, error) {
+	if !defaultContext.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	cells := make([][CellsPerExtBlob]Cell, len(blobs))
+	proofs := make([][CellsPerExtBlob]KZGProof, len(blobs))
+	g, ctx := newWorkerGroup(ParallelOptions{}.workers(len(blobs)))
+	goChunks(ctx, g, len(blobs), 1, func(i, _ int) error {
+		return ComputeCellsAndKZGProofsInto(&cells[i], &proofs[i], &blobs[i])
+	})
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+	return cells, proofs, nil
+}
@@ -0,0 +1,139 @@
+package ckzg4844
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parallelTestBlobs(tb testing.TB, n int) ([]Blob, []Bytes48, []Bytes48) {
+	blobs := make([]Blob, n)
+	commitments := make([]Bytes48, n)
+	proofs := make([]Bytes48, n)
+	for i := range blobs {
+		fillBlobRandom(&blobs[i], int64(i))
+		commitment, err := BlobToKZGCommitment(&blobs[i])
+		require.NoError(tb, err)
+		proof, err := ComputeBlobKZGProof(&blobs[i], Bytes48(commitment))
+		require.NoError(tb, err)
+		commitments[i], proofs[i] = Bytes48(commitment), Bytes48(proof)
+	}
+	return blobs, commitments, proofs
+}
+
+func parallelTestCells(tb testing.TB) ([]Bytes48, []uint64, []Cell, []Bytes48) {
+	blob := selfTestBlob()
+	commitment, err := BlobToKZGCommitment(blob)
+	require.NoError(tb, err)
+	cells, proofs, err := ComputeCellsAndKZGProofs(blob)
+	require.NoError(tb, err)
+	commitments := make([]Bytes48, CellsPerExtBlob)
+	cellIndices := make([]uint64, CellsPerExtBlob)
+	cellProofs := make([]Bytes48, CellsPerExtBlob)
+	for i := range cellIndices {
+		commitments[i] = Bytes48(commitment)
+		cellIndices[i] = uint64(i)
+		cellProofs[i] = Bytes48(proofs[i])
+	}
+	return commitments, cellIndices, cells[:], cellProofs
+}
+
+func TestVerifyBlobKZGProofBatchParallel(t *testing.T) {
+	blobs, commitments, proofs := parallelTestBlobs(t, 5)
+	for _, workers := range []int{0, 1, 2, 8} {
+		ok, err := VerifyBlobKZGProofBatchParallel(blobs, commitments, proofs, ParallelOptions{Workers: workers})
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	proofs[3] = proofs[2]
+	ok, err := VerifyBlobKZGProofBatchParallel(blobs, commitments, proofs, ParallelOptions{Workers: 3})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	commitments[4] = Bytes48{}
+	_, err = VerifyBlobKZGProofBatchParallel(blobs, commitments, proofs, ParallelOptions{Workers: 3})
+	require.ErrorIs(t, err, ErrBadArgs)
+	_, err = VerifyBlobKZGProofBatchParallel(blobs, commitments[:4], proofs, ParallelOptions{})
+	require.ErrorIs(t, err, ErrBadArgs)
+
+	ok, err = VerifyBlobKZGProofBatchParallel(nil, nil, nil, ParallelOptions{})
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestVerifyCellKZGProofBatchParallel(t *testing.T) {
+	commitments, cellIndices, cells, proofs := parallelTestCells(t)
+	ok, err := VerifyCellKZGProofBatchParallel(commitments, cellIndices, cells, proofs, ParallelOptions{Workers: 4})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	cells[100], cells[101] = cells[101], cells[100]
+	ok, err = VerifyCellKZGProofBatchParallel(commitments, cellIndices, cells, proofs, ParallelOptions{Workers: 4})
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestBlobsToKZGCommitments(t *testing.T) {
+	blobs, commitments, proofs := parallelTestBlobs(t, 5)
+	computed, err := BlobsToKZGCommitments(blobs)
+	require.NoError(t, err)
+	require.Len(t, computed, len(blobs))
+	computedProofs, err := ComputeBlobKZGProofs(blobs, commitments)
+	require.NoError(t, err)
+	cells, cellProofs, err := ComputeCellsAndKZGProofsBatch(blobs[:2])
+	require.NoError(t, err)
+	for i := range blobs {
+		require.Equal(t, commitments[i], Bytes48(computed[i]))
+		require.Equal(t, proofs[i], Bytes48(computedProofs[i]))
+	}
+	for i := range cells {
+		expectedCells, expectedProofs, err := ComputeCellsAndKZGProofs(&blobs[i])
+		require.NoError(t, err)
+		require.Equal(t, expectedCells, cells[i])
+		require.Equal(t, expectedProofs, cellProofs[i])
+	}
+
+	_, err = ComputeBlobKZGProofs(blobs, commitments[:1])
+	require.ErrorIs(t, err, ErrBadArgs)
+	commitments[3] = Bytes48{}
+	_, err = ComputeBlobKZGProofs(blobs, commitments)
+	require.ErrorIs(t, err, ErrBadArgs)
+	blobs[1][0] = 0xff
+	_, err = BlobsToKZGCommitments(blobs)
+	require.ErrorIs(t, err, ErrBadArgs)
+	_, _, err = ComputeCellsAndKZGProofsBatch(blobs)
+	require.ErrorIs(t, err, ErrBadArgs)
+
+	computed, err = BlobsToKZGCommitments(nil)
+	require.NoError(t, err)
+	require.Empty(t, computed)
+}
+
+func BenchmarkVerifyBlobKZGProofBatchParallel(b *testing.B) {
+	blobs, commitments, proofs := parallelTestBlobs(b, 64)
+	for workers := 1; workers <= runtime.NumCPU(); workers *= 2 {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				ok, err := VerifyBlobKZGProofBatchParallel(blobs, commitments, proofs, ParallelOptions{Workers: workers})
+				require.NoError(b, err)
+				require.True(b, ok)
+			}
+		})
+	}
+}
+
+func BenchmarkVerifyCellKZGProofBatchParallel(b *testing.B) {
+	commitments, cellIndices, cells, proofs := parallelTestCells(b)
+	for workers := 1; workers <= runtime.NumCPU(); workers *= 2 {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				ok, err := VerifyCellKZGProofBatchParallel(commitments, cellIndices, cells, proofs, ParallelOptions{Workers: workers})
+				require.NoError(b, err)
+				require.True(b, ok)
+			}
+		})
+	}
+}
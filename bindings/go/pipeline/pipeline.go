@@ -0,0 +1,225 @@
+// Package pipeline verifies a stream of SSZ encoded data column sidecars in
+// stages: decode, validate encodings, and batch verify. The stages are
+// connected by bounded buffers, so a slow stage applies back-pressure to the
+// producer instead of letting work pile up, and results come out in the order
+// the sidecars went in.
+//
+// A trusted setup must be loaded before running a pipeline.
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/ssz"
+)
+
+// ErrInvalidSidecar is the error of a Result whose sidecar can't be decoded or
+// is malformed, for example with an out of range column index or an invalid
+// commitment.
+var ErrInvalidSidecar = errors.New("invalid data column sidecar")
+
+// Config configures a pipeline.
+type Config struct {
+	// Buffer is the number of sidecars which may wait between two stages.
+	// Defaults to 16.
+	Buffer int
+	// MaxBatchCells is the number of cells verified together in one batch.
+	// Defaults to 1024.
+	MaxBatchCells int
+}
+
+// Result is the outcome for one input. Sidecar is set when the input could be
+// decoded, and Err is nil when the sidecar's proofs verified, or
+// ckzg4844.ErrVerificationFailed when they don't.
+type Result struct {
+	Sidecar *ssz.DataColumnSidecar
+	Err     error
+}
+
+/*
+Run starts a pipeline reading encoded sidecars from in and returns the channel
+of results, which has one result per input, in order. The results channel is
+closed once in is closed and all of its sidecars are processed, or once ctx is
+done. Decoded sidecars alias their input, which must not be modified.
+*/
+func Run(ctx context.Context, config Config, in <-chan []byte) <-chan Result {
+	if config.Buffer <= 0 {
+		config.Buffer = 16
+	}
+	if config.MaxBatchCells <= 0 {
+		config.MaxBatchCells = 1024
+	}
+	decoded := make(chan Result, config.Buffer)
+	validated := make(chan Result, config.Buffer)
+	out := make(chan Result, config.Buffer)
+	go stage(ctx, in, decoded, decode)
+	go stage(ctx, decoded, validated, validate)
+	go verify(ctx, config, validated, out)
+	return out
+}
+
+// stage applies f to every input and sends the results on, in order.
+func stage[In any](ctx context.Context, in <-chan In, out chan<- Result, f func(In) Result) {
+	defer close(out)
+	for {
+		select {
+		case item, ok := <-in:
+			if !ok {
+				return
+			}
+			select {
+			case out <- f(item):
+			case <-ctx.Done():
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func decode(data []byte) Result {
+	sidecar := new(ssz.DataColumnSidecar)
+	if err := sidecar.UnmarshalSSZ(data); err != nil {
+		return Result{Err: err}
+	}
+	return Result{Sidecar: sidecar}
+}
+
+// encodingError is the ErrInvalidSidecar of a sidecar with a commitment, proof
+// or cell which isn't validly encoded. It wraps the error of
+// ckzg4844.CheckCellKZGProofBatch, which says which one.
+type encodingError struct {
+	err error
+}
+
+func (e encodingError) Error() string {
+	return ErrInvalidSidecar.Error() + ": " + e.err.Error()
+}
+
+func (e encodingError) Unwrap() error {
+	return e.err
+}
+
+func (e encodingError) Is(target error) bool {
+	return target == ErrInvalidSidecar
+}
+
+// validate checks what verify_data_column_sidecar of the consensus specs
+// checks before the proofs are verified, and that the commitments, proofs and
+// cells are validly encoded, so that a malformed sidecar doesn't fail the
+// batch it would be verified in.
+func validate(r Result) Result {
+	if r.Err != nil {
+		return r
+	}
+	s := r.Sidecar
+	switch {
+	case s.Index >= ckzg4844.CellsPerExtBlob:
+		r.Err = fmt.Errorf("%w: column index %d", ErrInvalidSidecar, s.Index)
+	case len(s.KZGCommitments) == 0:
+		r.Err = fmt.Errorf("%w: no commitments", ErrInvalidSidecar)
+	case len(s.Column) != len(s.KZGCommitments) || len(s.KZGProofs) != len(s.KZGCommitments):
+		r.Err = fmt.Errorf("%w: %d cells, %d commitments and %d proofs", ErrInvalidSidecar, len(s.Column), len(s.KZGCommitments), len(s.KZGProofs))
+	default:
+		cellIndices := make([]uint64, len(s.Column))
+		for i := range cellIndices {
+			cellIndices[i] = s.Index
+		}
+		if err := ckzg4844.CheckCellKZGProofBatch(s.KZGCommitments, cellIndices, s.Column, s.KZGProofs); err != nil {
+			r.Err = encodingError{err}
+		}
+	}
+	return r
+}
+
+// verify verifies the sidecars in batches of whatever is waiting, up to
+// MaxBatchCells cells, and sends the results on in order.
+func verify(ctx context.Context, config Config, in <-chan Result, out chan<- Result) {
+	defer close(out)
+	for {
+		var batch []Result
+		select {
+		case r, ok := <-in:
+			if !ok {
+				return
+			}
+			batch = append(batch, r)
+		case <-ctx.Done():
+			return
+		}
+		cells := numCells(batch[0])
+	more:
+		for cells < config.MaxBatchCells {
+			select {
+			case r, ok := <-in:
+				if !ok {
+					break more
+				}
+				batch = append(batch, r)
+				cells += numCells(r)
+			default:
+				break more
+			}
+		}
+		verifyBatch(batch)
+		for _, r := range batch {
+			select {
+			case out <- r:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func numCells(r Result) int {
+	if r.Err != nil {
+		return 0
+	}
+	return len(r.Sidecar.Column)
+}
+
+// verifyBatch verifies all sidecars of the batch which passed validation in
+// one call and, if that fails, each of them on its own to find the bad ones.
+func verifyBatch(batch []Result) {
+	var pending []int
+	var commitments, proofs []ckzg4844.Bytes48
+	var cellIndices []uint64
+	var cells []ckzg4844.Cell
+	for i, r := range batch {
+		if r.Err != nil {
+			continue
+		}
+		s := r.Sidecar
+		pending = append(pending, i)
+		commitments = append(commitments, s.KZGCommitments...)
+		proofs = append(proofs, s.KZGProofs...)
+		cells = append(cells, s.Column...)
+		for range s.Column {
+			cellIndices = append(cellIndices, s.Index)
+		}
+	}
+	switch len(pending) {
+	case 0:
+		return
+	case 1:
+	default:
+		ok, err := ckzg4844.VerifyCellKZGProofBatch(commitments, cellIndices, cells, proofs)
+		if err == nil && ok {
+			return
+		}
+	}
+	for _, i := range pending {
+		ok, err := batch[i].Sidecar.VerifyKZGProofs()
+		switch {
+		case err != nil:
+			batch[i].Err = err
+		case !ok:
+			batch[i].Err = ckzg4844.ErrVerificationFailed
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/ckzgtest"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/ssz"
+	"github.com/stretchr/testify/require"
+)
+
+func encodedSidecars(t *testing.T, n int) [][]byte {
+	var blobCells [2]*[ckzg4844.CellsPerExtBlob]ckzg4844.Cell
+	var blobProofs [2]*[ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof
+	var commitments [2]ckzg4844.Bytes48
+	for i := range blobCells {
+		blob := ckzgtest.RandomBlob(int64(i))
+		commitment, err := ckzg4844.BlobToKZGCommitment(blob)
+		require.NoError(t, err)
+		cells, proofs, err := ckzg4844.ComputeCellsAndKZGProofs(blob)
+		require.NoError(t, err)
+		blobCells[i], blobProofs[i], commitments[i] = &cells, &proofs, ckzg4844.Bytes48(commitment)
+	}
+	encoded := make([][]byte, n)
+	for i := range encoded {
+		sidecar := ssz.DataColumnSidecar{Index: uint64(i)}
+		for j := range blobCells {
+			sidecar.Column = append(sidecar.Column, blobCells[j][i])
+			sidecar.KZGCommitments = append(sidecar.KZGCommitments, commitments[j])
+			sidecar.KZGProofs = append(sidecar.KZGProofs, ckzg4844.Bytes48(blobProofs[j][i]))
+		}
+		data, err := sidecar.MarshalSSZ()
+		require.NoError(t, err)
+		encoded[i] = data
+	}
+	return encoded
+}
+
+// reencoded decodes a sidecar, changes it and encodes it again.
+func reencoded(t *testing.T, data []byte, change func(*ssz.DataColumnSidecar)) []byte {
+	sidecar := new(ssz.DataColumnSidecar)
+	require.NoError(t, sidecar.UnmarshalSSZ(data))
+	change(sidecar)
+	data, err := sidecar.MarshalSSZ()
+	require.NoError(t, err)
+	return data
+}
+
+func TestRun(t *testing.T) {
+	ckzgtest.LoadTrustedSetup(t)
+	encoded := encodedSidecars(t, 32)
+	// A bad proof, a bad encoding and an empty sidecar.
+	encoded[3][len(encoded[3])-1] ^= 1
+	encoded[5] = encoded[5][:100]
+	empty, err := (&ssz.DataColumnSidecar{Index: 9}).MarshalSSZ()
+	require.NoError(t, err)
+	encoded[9] = empty
+	// A cell which isn't canonical and a commitment which isn't a point.
+	encoded[12] = reencoded(t, encoded[12], func(s *ssz.DataColumnSidecar) { s.Column[1][0] = 0xff })
+	encoded[14] = reencoded(t, encoded[14], func(s *ssz.DataColumnSidecar) { s.KZGCommitments[0] = ckzg4844.Bytes48{} })
+
+	in := make(chan []byte)
+	go func() {
+		defer close(in)
+		for _, data := range encoded {
+			in <- data
+		}
+	}()
+	var results []Result
+	for r := range Run(context.Background(), Config{Buffer: 2, MaxBatchCells: 8}, in) {
+		results = append(results, r)
+	}
+	require.Len(t, results, len(encoded))
+	for i, r := range results {
+		switch i {
+		case 3:
+			require.Error(t, r.Err)
+		case 5:
+			require.ErrorIs(t, r.Err, ssz.ErrInvalidSSZ)
+		case 9:
+			require.ErrorIs(t, r.Err, ErrInvalidSidecar)
+		case 12:
+			require.ErrorIs(t, r.Err, ErrInvalidSidecar)
+			var fieldErr ckzg4844.ErrInvalidFieldElement
+			require.ErrorAs(t, r.Err, &fieldErr)
+			require.Equal(t, ckzg4844.ErrInvalidFieldElement{Argument: "cell", Index: 1}, fieldErr)
+		case 14:
+			require.ErrorIs(t, r.Err, ErrInvalidSidecar)
+			var pointErr ckzg4844.ErrInvalidPoint
+			require.ErrorAs(t, r.Err, &pointErr)
+			require.Equal(t, ckzg4844.ErrInvalidPoint{Argument: "commitment"}, pointErr)
+		default:
+			require.NoError(t, r.Err)
+			require.Equal(t, uint64(i), r.Sidecar.Index)
+		}
+	}
+}
+
+func TestRunCancel(t *testing.T) {
+	ckzgtest.LoadTrustedSetup(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan []byte)
+	out := Run(ctx, Config{}, in)
+	cancel()
+	for range out {
+	}
+}
@@ -0,0 +1,227 @@
+package ckzg4844
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// ErrPoolClosed is returned by submissions made to a VerifierPool after it was
+// closed.
+var ErrPoolClosed = errors.New("verifier pool is closed")
+
+// VerifierPoolConfig configures a VerifierPool.
+type VerifierPoolConfig struct {
+	// Workers is the number of goroutines verifying submissions. Defaults to
+	// runtime.NumCPU().
+	Workers int
+	// QueueSize is the number of submissions which may wait for a worker.
+	// Submissions made while the queue is full fail with ErrQueueFull.
+	// Defaults to 1024.
+	QueueSize int
+	// MaxBatchSize is the number of blobs, or of cells, which a worker
+	// verifies together. Defaults to DefaultMaxBatchSize.
+	MaxBatchSize int
+}
+
+// poolJob is a blob proof or a set of cell proofs to verify.
+type poolJob struct {
+	blob        *Blob
+	commitments []Bytes48
+	cellIndices []uint64
+	cells       []Cell
+	proofs      []Bytes48
+	done        func(ok bool, err error)
+}
+
+func (j *poolJob) size() int {
+	if j.blob != nil {
+		return 1
+	}
+	return len(j.cells)
+}
+
+/*
+VerifierPool verifies submitted sidecars in the background and reports each
+result through a callback, which is the shape that transaction pools and gossip
+handlers need instead of blocking calls. Workers take whatever submissions are
+waiting and verify them in a single batch, so the work is sharded over the
+workers while still benefiting from batch verification. Like in
+BatchScheduler, a batch which doesn't verify is split in half until the
+submissions which don't verify are isolated.
+
+Callbacks are called from the worker goroutines and should return quickly.
+*/
+type VerifierPool struct {
+	config VerifierPoolConfig
+	jobs   chan *poolJob
+	wg     sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewVerifierPool starts a VerifierPool. Call Close to stop it.
+func NewVerifierPool(config VerifierPoolConfig) *VerifierPool {
+	if config.Workers <= 0 {
+		config.Workers = runtime.NumCPU()
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1024
+	}
+	if config.MaxBatchSize <= 0 {
+		config.MaxBatchSize = DefaultMaxBatchSize
+	}
+	p := &VerifierPool{
+		config: config,
+		jobs:   make(chan *poolJob, config.QueueSize),
+	}
+	for i := 0; i < config.Workers; i++ {
+		p.wg.Add(1)
+		go p.worker()
+	}
+	return p
+}
+
+func (p *VerifierPool) submit(job *poolJob) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+	select {
+	case p.jobs <- job:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+/*
+SubmitBlobSidecar queues a blob proof for verification. done is called with
+the result once it has been verified. The blob must not be modified until then.
+*/
+func (p *VerifierPool) SubmitBlobSidecar(blob *Blob, commitmentBytes, proofBytes Bytes48, done func(ok bool, err error)) error {
+	if blob == nil || done == nil {
+		return &Error{Op: "SubmitBlobSidecar", Err: ErrBadArgs}
+	}
+	return p.submit(&poolJob{
+		blob:        blob,
+		commitments: []Bytes48{commitmentBytes},
+		proofs:      []Bytes48{proofBytes},
+		done:        done,
+	})
+}
+
+/*
+SubmitCells queues cell proofs, such as those of a data column sidecar, for
+verification. The arguments are as for VerifyCellKZGProofBatch. done is called
+with the result once all of the cells have been verified. The slices must not
+be modified until then.
+*/
+func (p *VerifierPool) SubmitCells(commitmentsBytes []Bytes48, cellIndices []uint64, cells []Cell, proofsBytes []Bytes48, done func(ok bool, err error)) error {
+	if done == nil {
+		return &Error{Op: "SubmitCells", Err: ErrBadArgs}
+	}
+	if err := checkCellBatchLengths("SubmitCells", commitmentsBytes, cellIndices, cells, proofsBytes); err != nil {
+		return err
+	}
+	return p.submit(&poolJob{
+		commitments: commitmentsBytes,
+		cellIndices: cellIndices,
+		cells:       cells,
+		proofs:      proofsBytes,
+		done:        done,
+	})
+}
+
+func (p *VerifierPool) worker() {
+	defer p.wg.Done()
+	for job := range p.jobs {
+		var blobJobs, cellJobs []*poolJob
+		blobCount, cellCount := 0, 0
+		add := func(job *poolJob) {
+			if job.blob != nil {
+				blobJobs = append(blobJobs, job)
+				blobCount++
+			} else {
+				cellJobs = append(cellJobs, job)
+				cellCount += job.size()
+			}
+		}
+		add(job)
+		// Take more waiting jobs, as long as the batches have room.
+	more:
+		for blobCount < p.config.MaxBatchSize && cellCount < p.config.MaxBatchSize {
+			select {
+			case job, ok := <-p.jobs:
+				if !ok {
+					break more
+				}
+				add(job)
+			default:
+				break more
+			}
+		}
+		verifyBlobJobs(blobJobs)
+		verifyCellJobs(cellJobs)
+	}
+}
+
+func verifyBlobJobs(jobs []*poolJob) {
+	if len(jobs) == 0 {
+		return
+	}
+	blobs := make([]Blob, len(jobs))
+	commitments := make([]Bytes48, len(jobs))
+	proofs := make([]Bytes48, len(jobs))
+	for i, job := range jobs {
+		blobs[i], commitments[i], proofs[i] = *job.blob, job.commitments[0], job.proofs[0]
+	}
+	bisect(0, len(jobs), func(start, end int) (bool, error) {
+		return VerifyBlobKZGProofBatch(blobs[start:end], commitments[start:end], proofs[start:end])
+	}, func(i int, ok bool, err error) {
+		jobs[i].done(ok, err)
+	})
+}
+
+func verifyCellJobs(jobs []*poolJob) {
+	if len(jobs) == 0 {
+		return
+	}
+	var (
+		commitments []Bytes48
+		cellIndices []uint64
+		cells       []Cell
+		proofs      []Bytes48
+	)
+	// The cells of job i are offsets[i] to offsets[i+1].
+	offsets := make([]int, len(jobs)+1)
+	for i, job := range jobs {
+		commitments = append(commitments, job.commitments...)
+		cellIndices = append(cellIndices, job.cellIndices...)
+		cells = append(cells, job.cells...)
+		proofs = append(proofs, job.proofs...)
+		offsets[i+1] = len(cells)
+	}
+	bisect(0, len(jobs), func(start, end int) (bool, error) {
+		first, last := offsets[start], offsets[end]
+		return VerifyCellKZGProofBatch(commitments[first:last], cellIndices[first:last], cells[first:last], proofs[first:last])
+	}, func(i int, ok bool, err error) {
+		jobs[i].done(ok, err)
+	})
+}
+
+// Close waits for all queued submissions to be verified and stops the pool.
+// Submissions made after Close fail with ErrPoolClosed.
+func (p *VerifierPool) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	close(p.jobs)
+	p.mu.Unlock()
+	p.wg.Wait()
+}
@@ -0,0 +1,98 @@
+package ckzg4844
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifierPool(t *testing.T) {
+	blob := selfTestBlob()
+	commitment, err := BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+	proof, err := ComputeBlobKZGProof(blob, Bytes48(commitment))
+	require.NoError(t, err)
+	cells, cellProofs, err := ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+
+	p := NewVerifierPool(VerifierPoolConfig{Workers: 2})
+	const n = 8
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = map[string]bool{}
+	)
+	record := func(name string) func(bool, error) {
+		wg.Add(1)
+		return func(ok bool, err error) {
+			defer wg.Done()
+			if err != nil {
+				t.Error(err)
+			}
+			mu.Lock()
+			results[name] = ok
+			mu.Unlock()
+		}
+	}
+	for i := 0; i < n; i++ {
+		require.NoError(t, p.SubmitBlobSidecar(blob, Bytes48(commitment), Bytes48(proof), record(fmt.Sprint("blob ", i))))
+		require.NoError(t, p.SubmitCells(
+			[]Bytes48{Bytes48(commitment), Bytes48(commitment)},
+			[]uint64{uint64(2 * i), uint64(2*i + 1)},
+			[]Cell{cells[2*i], cells[2*i+1]},
+			[]Bytes48{Bytes48(cellProofs[2*i]), Bytes48(cellProofs[2*i+1])},
+			record(fmt.Sprint("cells ", i))))
+	}
+	require.NoError(t, p.SubmitBlobSidecar(blob, Bytes48(commitment), Bytes48(commitment), record("bad blob")))
+	require.NoError(t, p.SubmitCells([]Bytes48{Bytes48(commitment)}, []uint64{1}, []Cell{cells[0]}, []Bytes48{Bytes48(cellProofs[0])}, record("bad cells")))
+	wg.Wait()
+	p.Close()
+
+	require.Len(t, results, 2*n+2)
+	for name, ok := range results {
+		require.Equal(t, name[:3] != "bad", ok, name)
+	}
+	require.ErrorIs(t, p.SubmitBlobSidecar(blob, Bytes48(commitment), Bytes48(proof), func(bool, error) {}), ErrPoolClosed)
+}
+
+func TestVerifyCellJobsBisect(t *testing.T) {
+	blob := selfTestBlob()
+	commitment, err := BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+	cells, cellProofs, err := ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+
+	fi := NewFaultInjector()
+	SetFaultInjector(fi)
+	defer SetFaultInjector(nil)
+
+	const n, bad = 8, 5
+	results := make([]bool, n)
+	var jobs []*poolJob
+	for i := 0; i < n; i++ {
+		i := i
+		job := &poolJob{
+			commitments: []Bytes48{Bytes48(commitment), Bytes48(commitment)},
+			cellIndices: []uint64{uint64(2 * i), uint64(2*i + 1)},
+			cells:       []Cell{cells[2*i], cells[2*i+1]},
+			proofs:      []Bytes48{Bytes48(cellProofs[2*i]), Bytes48(cellProofs[2*i+1])},
+			done: func(ok bool, err error) {
+				require.NoError(t, err)
+				results[i] = ok
+			},
+		}
+		if i == bad {
+			job.proofs[1] = job.proofs[0]
+		}
+		jobs = append(jobs, job)
+	}
+	verifyCellJobs(jobs)
+
+	for i, ok := range results {
+		require.Equal(t, i != bad, ok, "job %d", i)
+	}
+	// Both halves of each range containing the bad job are verified again.
+	require.Equal(t, 1+2*3, fi.Calls("VerifyCellKZGProofBatch"))
+}
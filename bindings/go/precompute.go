@@ -0,0 +1,198 @@
+package ckzg4844
+
+// #cgo CFLAGS: -I${SRCDIR}/../../src
+// #cgo CFLAGS: -I${SRCDIR}/blst_headers
+// #include <stdlib.h>
+// #include "ckzg.h"
+import "C"
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"unsafe"
+)
+
+var (
+	// ErrNoPrecompute is returned by WritePrecomputeSnapshot for a trusted
+	// setup which was loaded without precompute tables.
+	ErrNoPrecompute = errors.New("trusted setup was loaded without precompute tables")
+	// ErrInvalidPrecomputeSnapshot is returned by RestorePrecomputeSnapshot
+	// for a file which isn't a snapshot for the loaded trusted setup.
+	ErrInvalidPrecomputeSnapshot = errors.New("invalid precompute snapshot")
+)
+
+// MaxPrecompute is the largest precompute value a trusted setup can be loaded
+// with.
+const MaxPrecompute = 15
+
+// precomputeSnapshotMagic starts every snapshot. The last byte is the version
+// of the format.
+const precomputeSnapshotMagic = "CKZGPRE\x01"
+
+// precomputeSnapshotHeader precedes the tables in a snapshot. Its size keeps
+// the tables aligned when the snapshot is memory-mapped.
+type precomputeSnapshotHeader struct {
+	Magic     [8]byte
+	Wbits     uint64
+	Tables    uint64
+	TableSize uint64
+	SetupHash [32]byte
+}
+
+const precomputeSnapshotHeaderSize = 64
+
+// setupHash identifies the trusted setup of c, so that a snapshot can't be
+// restored for a different setup.
+func (c *Context) setupHash() [32]byte {
+	h := sha256.New()
+	h.Write(unsafe.Slice((*byte)(unsafe.Pointer(c.settings.g1_values_monomial)), FieldElementsPerBlob*C.sizeof_g1_t))
+	h.Write(unsafe.Slice((*byte)(unsafe.Pointer(c.settings.g2_values_monomial)), 65*C.sizeof_g2_t))
+	var out [32]byte
+	h.Sum(out[:0])
+	return out
+}
+
+/*
+PrecomputeMemory returns the memory in bytes taken by the precompute tables of
+a trusted setup loaded with the given precompute value, which is zero for no
+precomputation.
+*/
+func PrecomputeMemory(precompute uint) uint64 {
+	if precompute == 0 || precompute > MaxPrecompute {
+		return 0
+	}
+	return CellsPerExtBlob * precomputeTableSize(uint64(precompute))
+}
+
+func precomputeTableSize(wbits uint64) uint64 {
+	return uint64(C.blst_p1s_mult_wbits_precompute_sizeof(C.size_t(wbits), C.FIELD_ELEMENTS_PER_CELL))
+}
+
+func (c *Context) precomputeTables() []*C.blst_p1_affine {
+	return unsafe.Slice(c.settings.tables, CellsPerExtBlob)
+}
+
+/*
+WritePrecomputeSnapshot writes the fixed-base precompute tables of the loaded
+trusted setup to w, so that later processes can restore them with
+RestorePrecomputeSnapshot instead of computing them again. The setup must have
+been loaded with a non-zero precompute value.
+*/
+func WritePrecomputeSnapshot(w io.Writer) error {
+	return defaultContext.WritePrecomputeSnapshot(w)
+}
+
+// WritePrecomputeSnapshot is WritePrecomputeSnapshot for the trusted setup of
+// c.
+func (c *Context) WritePrecomputeSnapshot(w io.Writer) error {
+	if !c.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	if c.settings.wbits == 0 || c.settings.tables == nil {
+		return ErrNoPrecompute
+	}
+	header := precomputeSnapshotHeader{
+		Wbits:     uint64(c.settings.wbits),
+		Tables:    CellsPerExtBlob,
+		TableSize: precomputeTableSize(uint64(c.settings.wbits)),
+		SetupHash: c.setupHash(),
+	}
+	copy(header.Magic[:], precomputeSnapshotMagic)
+	if err := binary.Write(w, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+	for _, table := range c.precomputeTables() {
+		if _, err := w.Write(unsafe.Slice((*byte)(unsafe.Pointer(table)), header.TableSize)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+RestorePrecomputeSnapshot installs the precompute tables from a snapshot made
+with WritePrecomputeSnapshot. The trusted setup must have been loaded with a
+precompute value of zero, and must be the setup the snapshot was made with.
+Where supported, the snapshot is memory-mapped rather than read, so it must not
+be modified until FreeTrustedSetup is called.
+
+It must be called before the setup is used by any other goroutine.
+*/
+func RestorePrecomputeSnapshot(path string) error {
+	return defaultContext.RestorePrecomputeSnapshot(path)
+}
+
+// RestorePrecomputeSnapshot is RestorePrecomputeSnapshot for the trusted setup
+// of c. The snapshot must not be modified until c is freed.
+func (c *Context) RestorePrecomputeSnapshot(path string) error {
+	if !c.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	if c.shared {
+		return errors.New("can't restore a precompute snapshot into a context from WithProfilerLabels")
+	}
+	if c.settings.wbits != 0 || c.settings.tables != nil {
+		return errors.New("trusted setup already has precompute tables")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var header precomputeSnapshotHeader
+	if err := binary.Read(f, binary.LittleEndian, &header); err != nil {
+		return ErrInvalidPrecomputeSnapshot
+	}
+	if !bytes.Equal(header.Magic[:], []byte(precomputeSnapshotMagic)) ||
+		header.Tables != CellsPerExtBlob ||
+		header.Wbits == 0 || header.Wbits > MaxPrecompute ||
+		header.TableSize != precomputeTableSize(header.Wbits) ||
+		header.SetupHash != c.setupHash() {
+		return ErrInvalidPrecomputeSnapshot
+	}
+	size := header.Tables * header.TableSize
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if uint64(info.Size()) != precomputeSnapshotHeaderSize+size {
+		return ErrInvalidPrecomputeSnapshot
+	}
+
+	data, release, err := mapSnapshot(f, precomputeSnapshotHeaderSize, int(size))
+	if err != nil {
+		return err
+	}
+	tables := (**C.blst_p1_affine)(C.calloc(C.size_t(header.Tables), C.size_t(unsafe.Sizeof(uintptr(0)))))
+	if tables == nil {
+		release()
+		return ErrMalloc
+	}
+	c.settings.tables = tables
+	for i := range c.precomputeTables() {
+		c.precomputeTables()[i] = (*C.blst_p1_affine)(unsafe.Add(data, uint64(i)*header.TableSize))
+	}
+	c.settings.wbits = C.size_t(header.Wbits)
+	c.settings.scratch_size = C.blst_p1s_mult_wbits_scratch_sizeof(C.FIELD_ELEMENTS_PER_CELL)
+	c.snapshotRelease = release
+	return nil
+}
+
+// releasePrecomputeSnapshot detaches the tables of a restored snapshot from the
+// settings, so that free_trusted_setup doesn't free them, and releases them.
+func (c *Context) releasePrecomputeSnapshot() {
+	if c.snapshotRelease == nil {
+		return
+	}
+	tables := c.precomputeTables()
+	for i := range tables {
+		tables[i] = nil
+	}
+	c.snapshotRelease()
+	c.snapshotRelease = nil
+}
@@ -0,0 +1,29 @@
+//go:build !unix
+
+package ckzg4844
+
+// #include <stdlib.h>
+import "C"
+
+import (
+	"io"
+	"os"
+	"unsafe"
+)
+
+// mapSnapshot reads size bytes of f starting at offset into C memory, as
+// memory-mapping isn't supported on this platform.
+func mapSnapshot(f *os.File, offset int64, size int) (unsafe.Pointer, func(), error) {
+	data := C.malloc(C.size_t(size))
+	if data == nil {
+		return nil, nil, ErrMalloc
+	}
+	release := func() {
+		C.free(data)
+	}
+	if _, err := io.ReadFull(io.NewSectionReader(f, offset, int64(size)), unsafe.Slice((*byte)(data), size)); err != nil {
+		release()
+		return nil, nil, err
+	}
+	return data, release, nil
+}
@@ -0,0 +1,54 @@
+package ckzg4844
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrecomputeSnapshot(t *testing.T) {
+	const trustedSetupFile = "../../src/trusted_setup.txt"
+	path := filepath.Join(t.TempDir(), "precompute.bin")
+	blob := selfTestBlob()
+	expectedCells, expectedProofs, err := ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+
+	// The tests otherwise use a setup without precompute tables.
+	defer func() {
+		FreeTrustedSetup()
+		require.NoError(t, LoadTrustedSetupFile(trustedSetupFile, 0))
+	}()
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	require.ErrorIs(t, WritePrecomputeSnapshot(f), ErrNoPrecompute)
+	FreeTrustedSetup()
+	require.NoError(t, LoadTrustedSetupFile(trustedSetupFile, 4))
+	require.NoError(t, WritePrecomputeSnapshot(f))
+	require.NoError(t, f.Close())
+
+	FreeTrustedSetup()
+	require.NoError(t, LoadTrustedSetupFile(trustedSetupFile, 0))
+	require.NoError(t, RestorePrecomputeSnapshot(path))
+	require.Equal(t, uint64(4), uint64(defaultContext.settings.wbits))
+	cells, proofs, err := ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+	require.Equal(t, expectedCells, cells)
+	require.Equal(t, expectedProofs, proofs)
+}
+
+func TestRestorePrecomputeSnapshotInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "precompute.bin")
+	require.NoError(t, os.WriteFile(path, make([]byte, 1024), 0o644))
+	require.ErrorIs(t, RestorePrecomputeSnapshot(path), ErrInvalidPrecomputeSnapshot)
+}
+
+func TestPrecomputeMemory(t *testing.T) {
+	require.Zero(t, PrecomputeMemory(0))
+	require.Equal(t, uint64(96<<20), PrecomputeMemory(8))
+	for i := uint(2); i <= MaxPrecompute; i++ {
+		require.Greater(t, PrecomputeMemory(i), PrecomputeMemory(i-1))
+	}
+}
@@ -0,0 +1,23 @@
+//go:build unix
+
+package ckzg4844
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// mapSnapshot memory-maps size bytes of f starting at offset, which must be a
+// multiple of the alignment of the tables.
+func mapSnapshot(f *os.File, offset int64, size int) (unsafe.Pointer, func(), error) {
+	// Map from the start of the file, as offset isn't page aligned.
+	mapping, err := syscall.Mmap(int(f.Fd()), 0, int(offset)+size, syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+	release := func() {
+		_ = syscall.Munmap(mapping)
+	}
+	return unsafe.Pointer(&mapping[offset]), release, nil
+}
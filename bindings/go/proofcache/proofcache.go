@@ -0,0 +1,186 @@
+// Package proofcache persists computed blob proofs and cell proofs, keyed by
+// the hash of the blob, the commitment and the trusted setup, so that a proposer or batcher restarted after a crash
+// doesn't recompute proofs for blobs it already processed. The storage is
+// pluggable through the Store interface; DirStore keeps entries on disk.
+//
+// A trusted setup must be loaded before computing proofs.
+package proofcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+)
+
+// ErrNotFound is returned by Store.Get when there is no value for a key.
+var ErrNotFound = errors.New("not found")
+
+// Store is a key-value store. Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the value for key, or ErrNotFound.
+	Get(key []byte) ([]byte, error)
+	// Put stores value for key, replacing any previous value.
+	Put(key, value []byte) error
+}
+
+// DirStore is a Store which keeps each entry in its own file in a directory.
+// Values are written atomically, so a crash never leaves a partial entry.
+type DirStore struct {
+	dir string
+}
+
+// NewDirStore returns a DirStore for dir, creating it if necessary.
+func NewDirStore(dir string) (*DirStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DirStore{dir: dir}, nil
+}
+
+func (s *DirStore) path(key []byte) string {
+	return filepath.Join(s.dir, hex.EncodeToString(key))
+}
+
+func (s *DirStore) Get(key []byte) ([]byte, error) {
+	value, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return value, err
+}
+
+func (s *DirStore) Put(key, value []byte) error {
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(value); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(key))
+}
+
+// Key prefixes, so that different kinds of entries can share a store.
+const (
+	prefixBlobProof  = 'b'
+	prefixCellProofs = 'c'
+)
+
+// Cache computes proofs through the bindings, storing them in a Store and
+// returning stored proofs instead of recomputing them.
+type Cache struct {
+	store Store
+}
+
+// New returns a Cache backed by store.
+func New(store Store) *Cache {
+	return &Cache{store: store}
+}
+
+// key returns the key of the proofs of blob, which depend on the loaded
+// trusted setup and, for blob proofs, the commitment.
+func key(prefix byte, blob *ckzg4844.Blob, commitmentBytes *ckzg4844.Bytes48) []byte {
+	fingerprint := ckzg4844.TrustedSetupFingerprint()
+	h := sha256.New()
+	h.Write(fingerprint[:])
+	h.Write(blob[:])
+	if commitmentBytes != nil {
+		h.Write(commitmentBytes[:])
+	}
+	return h.Sum([]byte{prefix})
+}
+
+// get returns the stored value for key if it exists and has the expected size.
+// Entries of the wrong size are treated as missing and will be overwritten.
+func (c *Cache) get(key []byte, size int) ([]byte, bool, error) {
+	value, err := c.store.Get(key)
+	if errors.Is(err, ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("proofcache: get: %w", err)
+	}
+	return value, len(value) == size, nil
+}
+
+func (c *Cache) put(key, value []byte) error {
+	if err := c.store.Put(key, value); err != nil {
+		return fmt.Errorf("proofcache: put: %w", err)
+	}
+	return nil
+}
+
+/*
+ComputeBlobKZGProof returns the stored proof for blob and commitmentBytes, or
+computes it with ckzg4844.ComputeBlobKZGProof and stores it.
+*/
+func (c *Cache) ComputeBlobKZGProof(blob *ckzg4844.Blob, commitmentBytes ckzg4844.Bytes48) (ckzg4844.KZGProof, error) {
+	if blob == nil {
+		return ckzg4844.KZGProof{}, ckzg4844.ErrBadArgs
+	}
+	k := key(prefixBlobProof, blob, &commitmentBytes)
+	var proof ckzg4844.KZGProof
+	value, ok, err := c.get(k, len(proof))
+	if err != nil {
+		return ckzg4844.KZGProof{}, err
+	}
+	if ok {
+		copy(proof[:], value)
+		return proof, nil
+	}
+	proof, err = ckzg4844.ComputeBlobKZGProof(blob, commitmentBytes)
+	if err != nil {
+		return ckzg4844.KZGProof{}, err
+	}
+	return proof, c.put(k, proof[:])
+}
+
+/*
+ComputeCellsAndKZGProofs returns the cells of blob together with the stored
+cell proofs, or computes both with ckzg4844.ComputeCellsAndKZGProofs and stores
+the proofs. Cells are never stored; computing them alone is cheap compared to
+computing the proofs.
+*/
+func (c *Cache) ComputeCellsAndKZGProofs(blob *ckzg4844.Blob) ([ckzg4844.CellsPerExtBlob]ckzg4844.Cell, [ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof, error) {
+	var cells [ckzg4844.CellsPerExtBlob]ckzg4844.Cell
+	var proofs [ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof
+	if blob == nil {
+		return cells, proofs, ckzg4844.ErrBadArgs
+	}
+	k := key(prefixCellProofs, blob, nil)
+	value, ok, err := c.get(k, len(proofs)*len(proofs[0]))
+	if err != nil {
+		return cells, proofs, err
+	}
+	if ok {
+		for i := range proofs {
+			copy(proofs[i][:], value[i*len(proofs[i]):])
+		}
+		if err := ckzg4844.ComputeCellsAndKZGProofsInto(&cells, nil, blob); err != nil {
+			return cells, proofs, err
+		}
+		return cells, proofs, nil
+	}
+	if err := ckzg4844.ComputeCellsAndKZGProofsInto(&cells, &proofs, blob); err != nil {
+		return cells, proofs, err
+	}
+	value = make([]byte, 0, len(proofs)*len(proofs[0]))
+	for i := range proofs {
+		value = append(value, proofs[i][:]...)
+	}
+	return cells, proofs, c.put(k, value)
+}
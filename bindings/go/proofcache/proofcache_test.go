@@ -0,0 +1,60 @@
+package proofcache
+
+import (
+	"testing"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/ckzgtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	ckzgtest.LoadTrustedSetup(t)
+	blob := ckzgtest.RandomBlob(1)
+	commitment, err := ckzg4844.BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	store, err := NewDirStore(dir)
+	require.NoError(t, err)
+	proof, err := New(store).ComputeBlobKZGProof(blob, ckzg4844.Bytes48(commitment))
+	require.NoError(t, err)
+	cells, proofs, err := New(store).ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+
+	// A new cache over the same directory must not compute proofs again.
+	fi := ckzg4844.NewFaultInjector()
+	fi.FailNthCall("ComputeBlobKZGProof", 1, nil)
+	ckzg4844.SetFaultInjector(fi)
+	defer ckzg4844.SetFaultInjector(nil)
+
+	store, err = NewDirStore(dir)
+	require.NoError(t, err)
+	cache := New(store)
+	cachedProof, err := cache.ComputeBlobKZGProof(blob, ckzg4844.Bytes48(commitment))
+	require.NoError(t, err)
+	require.Equal(t, proof, cachedProof)
+	cachedCells, cachedProofs, err := cache.ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+	require.Equal(t, cells, cachedCells)
+	require.Equal(t, proofs, cachedProofs)
+	require.Equal(t, 0, fi.Calls("ComputeBlobKZGProof"))
+	ckzg4844.SetFaultInjector(nil)
+
+	// A proof for another commitment isn't served from the cache.
+	otherCommitment, err := ckzg4844.BlobToKZGCommitment(ckzgtest.RandomBlob(2))
+	require.NoError(t, err)
+	otherProof, err := cache.ComputeBlobKZGProof(blob, ckzg4844.Bytes48(otherCommitment))
+	require.NoError(t, err)
+	expectedProof, err := ckzg4844.ComputeBlobKZGProof(blob, ckzg4844.Bytes48(otherCommitment))
+	require.NoError(t, err)
+	require.Equal(t, expectedProof, otherProof)
+	require.NotEqual(t, proof, otherProof)
+}
+
+func TestDirStoreNotFound(t *testing.T) {
+	store, err := NewDirStore(t.TempDir())
+	require.NoError(t, err)
+	_, err = store.Get([]byte("missing"))
+	require.ErrorIs(t, err, ErrNotFound)
+}
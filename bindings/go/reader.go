@@ -0,0 +1,57 @@
+package ckzg4844
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+// BytesPerBlobPayload is the amount of data which fits in a blob when packed
+// 31 bytes per field element, so that every field element is canonical.
+const BytesPerBlobPayload = FieldElementsPerBlob * (BytesPerFieldElement - 1)
+
+var readerBlobs = sync.Pool{
+	New: func() any { return new(Blob) },
+}
+
+/*
+CommitFromReader reads up to BytesPerBlobPayload bytes from r, packs them into a
+blob 31 bytes per field element, with the first byte of each field element set
+to zero and the unused remainder of the blob zeroed, and returns the commitment
+to that blob along with the number of bytes read. The blob is a pooled buffer,
+so rollup batchers streaming data don't allocate one per commitment.
+
+Reading stops at the end of r or once the blob is full; any further data is left
+unread. Errors of r other than io.EOF are returned.
+*/
+func CommitFromReader(r io.Reader) (KZGCommitment, int, error) {
+	if !defaultContext.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	blob := readerBlobs.Get().(*Blob)
+	defer func() {
+		*blob = Blob{}
+		readerBlobs.Put(blob)
+	}()
+
+	// Read the payload into the end of the blob, then spread it out over the
+	// field elements. Moving field elements in ascending order never
+	// overwrites payload which hasn't been moved yet.
+	payload := blob[BytesPerBlob-BytesPerBlobPayload:]
+	n, err := io.ReadFull(r, payload)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return KZGCommitment{}, n, err
+	}
+	for i := range payload[n:] {
+		payload[n+i] = 0
+	}
+	const width = BytesPerFieldElement - 1
+	for i := 0; i < FieldElementsPerBlob; i++ {
+		offset := i * BytesPerFieldElement
+		copy(blob[offset+1:offset+BytesPerFieldElement], payload[i*width:(i+1)*width])
+		blob[offset] = 0
+	}
+
+	commitment, err := BlobToKZGCommitment(blob)
+	return commitment, n, err
+}
@@ -0,0 +1,37 @@
+package ckzg4844
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitFromReader(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, size := range []int{0, 1, 31, 1000, BytesPerBlobPayload, BytesPerBlobPayload + 10} {
+		data := make([]byte, size)
+		r.Read(data)
+
+		var blob Blob
+		for i := 0; i*31 < size && i < FieldElementsPerBlob; i++ {
+			copy(blob[i*32+1:i*32+32], data[i*31:])
+		}
+		expected, err := BlobToKZGCommitment(&blob)
+		require.NoError(t, err)
+
+		reader := bytes.NewReader(data)
+		commitment, n, err := CommitFromReader(reader)
+		require.NoError(t, err)
+		require.Equal(t, expected, commitment, "size %d", size)
+		if size > BytesPerBlobPayload {
+			require.Equal(t, BytesPerBlobPayload, n)
+			rest, _ := io.ReadAll(reader)
+			require.Len(t, rest, size-BytesPerBlobPayload)
+		} else {
+			require.Equal(t, size, n)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package ckzg4844
+
+import (
+	"sync"
+)
+
+// recoveryScratch holds the full recovery results, from which the subset
+// variant copies out the requested cells.
+type recoveryScratch struct {
+	cells  [CellsPerExtBlob]Cell
+	proofs [CellsPerExtBlob]KZGProof
+}
+
+var recoveryScratches = sync.Pool{New: func() any { return new(recoveryScratch) }}
+
+/*
+RecoverCellsAndKZGProofsSubset is like RecoverCellsAndKZGProofs, but returns only
+the cells with the indices in targetIndices, and their proofs, in that order.
+Callers which reconstruct a few columns don't have to allocate and copy all
+cells of the extended blob. The full recovery happens in pooled memory.
+*/
+func RecoverCellsAndKZGProofsSubset(cellIndices []uint64, cells []Cell, targetIndices []uint64) ([]Cell, []KZGProof, error) {
+	return defaultContext.RecoverCellsAndKZGProofsSubset(cellIndices, cells, targetIndices)
+}
+
+// RecoverCellsAndKZGProofsSubset is RecoverCellsAndKZGProofsSubset using the
+// trusted setup of c.
+func (c *Context) RecoverCellsAndKZGProofsSubset(cellIndices []uint64, cells []Cell, targetIndices []uint64) ([]Cell, []KZGProof, error) {
+	if !c.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	for i, index := range targetIndices {
+		if index >= CellsPerExtBlob {
+			return nil, nil, &Error{Op: "RecoverCellsAndKZGProofsSubset", Err: ErrInvalidCellIndex{Index: i, CellIndex: index}}
+		}
+	}
+	scratch := recoveryScratches.Get().(*recoveryScratch)
+	defer recoveryScratches.Put(scratch)
+	if err := c.RecoverCellsAndKZGProofsInto(&scratch.cells, &scratch.proofs, cellIndices, cells); err != nil {
+		return nil, nil, opError("RecoverCellsAndKZGProofsSubset", err, nil)
+	}
+	recoveredCells := make([]Cell, len(targetIndices))
+	recoveredProofs := make([]KZGProof, len(targetIndices))
+	for i, index := range targetIndices {
+		recoveredCells[i] = scratch.cells[index]
+		recoveredProofs[i] = scratch.proofs[index]
+	}
+	return recoveredCells, recoveredProofs, nil
+}
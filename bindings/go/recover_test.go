@@ -0,0 +1,33 @@
+package ckzg4844
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoverCellsAndKZGProofsSubset(t *testing.T) {
+	blob := selfTestBlob()
+	cells, proofs, err := ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+	cellIndices, partialCells := getPartialCells(cells, 2)
+
+	targets := []uint64{6, 0, 127, 6}
+	recoveredCells, recoveredProofs, err := RecoverCellsAndKZGProofsSubset(cellIndices, partialCells, targets)
+	require.NoError(t, err)
+	require.Len(t, recoveredCells, len(targets))
+	for i, index := range targets {
+		require.Equal(t, cells[index], recoveredCells[i])
+		require.Equal(t, proofs[index], recoveredProofs[i])
+	}
+
+	recoveredCells, recoveredProofs, err = RecoverCellsAndKZGProofsSubset(cellIndices, partialCells, nil)
+	require.NoError(t, err)
+	require.Empty(t, recoveredCells)
+	require.Empty(t, recoveredProofs)
+
+	_, _, err = RecoverCellsAndKZGProofsSubset(cellIndices, partialCells, []uint64{CellsPerExtBlob})
+	require.ErrorIs(t, err, ErrBadArgs)
+	_, _, err = RecoverCellsAndKZGProofsSubset(cellIndices[:10], partialCells[:10], []uint64{0})
+	require.ErrorIs(t, err, ErrBadArgs)
+}
@@ -0,0 +1,23 @@
+//go:build ckzg_refvectors
+
+package refvectors
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// vectors is a compact subset of the reference tests: a few valid and invalid
+// cases for every spec function, preferring the smaller test files.
+//
+//go:embed vectors
+var vectors embed.FS
+
+// Embedded returns the embedded test vectors.
+func Embedded() (fs.FS, bool) {
+	fsys, err := fs.Sub(vectors, "vectors")
+	if err != nil {
+		panic(err)
+	}
+	return fsys, true
+}
@@ -0,0 +1,11 @@
+//go:build !ckzg_refvectors
+
+package refvectors
+
+import "io/fs"
+
+// Embedded returns the embedded test vectors. The vectors are only embedded
+// when building with the ckzg_refvectors build tag.
+func Embedded() (fs.FS, bool) {
+	return nil, false
+}
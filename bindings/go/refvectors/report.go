@@ -0,0 +1,83 @@
+package refvectors
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+/*
+RunDir runs the reference tests in dir, or the embedded test vectors if dir is
+empty, as the commands which run the reference tests do. It returns
+ErrNotEmbedded if dir is empty and the vectors aren't embedded, and an error if
+no tests were found.
+*/
+func RunDir(dir string) ([]Result, error) {
+	var results []Result
+	var err error
+	source := dir
+	if dir == "" {
+		source = "the embedded test vectors"
+		results, err = RunEmbedded()
+	} else {
+		results, err = Run(os.DirFS(dir))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no reference tests found in %s", source)
+	}
+	return results, nil
+}
+
+// Summary counts the results of a spec function for one preset.
+type Summary struct {
+	Function string `json:"function"`
+	Preset   string `json:"preset"`
+	Passed   int    `json:"passed"`
+	Failed   int    `json:"failed"`
+}
+
+// Failure describes a failed test.
+type Failure struct {
+	Function string `json:"function"`
+	Preset   string `json:"preset"`
+	Name     string `json:"name"`
+	Error    string `json:"error"`
+}
+
+// Summarize counts the results per spec function and preset, sorted by both,
+// and lists the failed tests in the order of the results.
+func Summarize(results []Result) ([]Summary, []Failure) {
+	summaries := []Summary{}
+	failures := []Failure{}
+	index := map[[2]string]int{}
+	for _, result := range results {
+		key := [2]string{result.Function, result.Preset}
+		i, ok := index[key]
+		if !ok {
+			i = len(summaries)
+			index[key] = i
+			summaries = append(summaries, Summary{Function: result.Function, Preset: result.Preset})
+		}
+		if result.Passed() {
+			summaries[i].Passed++
+			continue
+		}
+		summaries[i].Failed++
+		failures = append(failures, Failure{
+			Function: result.Function,
+			Preset:   result.Preset,
+			Name:     result.Name,
+			Error:    result.Err.Error(),
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Function != summaries[j].Function {
+			return summaries[i].Function < summaries[j].Function
+		}
+		return summaries[i].Preset < summaries[j].Preset
+	})
+	return summaries, failures
+}
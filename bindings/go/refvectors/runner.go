@@ -0,0 +1,463 @@
+// Package refvectors runs consensus-spec style reference test vectors against
+// the bindings. The vectors can come from any fs.FS, such as a checkout of the
+// reference tests, or from the compact subset embedded in this package when it
+// is built with the ckzg_refvectors build tag.
+//
+// A trusted setup must be loaded before running the vectors.
+package refvectors
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrNotEmbedded = errors.New("reference vectors not embedded, build with -tags ckzg_refvectors")
+
+// Functions lists the spec functions which have reference tests.
+var Functions = []string{
+	"blob_to_kzg_commitment",
+	"compute_kzg_proof",
+	"compute_blob_kzg_proof",
+	"verify_kzg_proof",
+	"verify_blob_kzg_proof",
+	"verify_blob_kzg_proof_batch",
+	"compute_cells_and_kzg_proofs",
+	"recover_cells_and_kzg_proofs",
+	"verify_cell_kzg_proof_batch",
+}
+
+// Result is the outcome of a single test vector.
+type Result struct {
+	// Function is the spec function under test, e.g. "verify_blob_kzg_proof".
+	Function string
+	// Preset is the preset directory of the test, e.g. "kzg-mainnet".
+	Preset string
+	// Name is the name of the test case.
+	Name string
+	// Err describes why the test failed, or is nil if it passed.
+	Err error
+}
+
+func (r Result) Passed() bool {
+	return r.Err == nil
+}
+
+/*
+Run runs every test vector in fsys. The layout is the same as the reference
+tests, that is:
+
+	<function>/<preset>/<case>/data.yaml
+
+Directories for unknown functions are ignored. An error is only returned if
+fsys can't be read; failed test vectors are reported in the results.
+*/
+func Run(fsys fs.FS) ([]Result, error) {
+	var results []Result
+	for _, function := range Functions {
+		testPaths, err := fs.Glob(fsys, path.Join(function, "*", "*", "data.yaml"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(testPaths)
+		for _, testPath := range testPaths {
+			parts := strings.Split(testPath, "/")
+			data, err := fs.ReadFile(fsys, testPath)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, Result{
+				Function: function,
+				Preset:   parts[1],
+				Name:     parts[2],
+				Err:      RunTest(function, data),
+			})
+		}
+	}
+	return results, nil
+}
+
+// RunEmbedded runs the embedded test vectors.
+func RunEmbedded() ([]Result, error) {
+	fsys, ok := Embedded()
+	if !ok {
+		return nil, ErrNotEmbedded
+	}
+	return Run(fsys)
+}
+
+// RunTest runs a single YAML test vector for the given spec function.
+func RunTest(function string, data []byte) error {
+	switch function {
+	case "blob_to_kzg_commitment":
+		return runBlobToKZGCommitment(data)
+	case "compute_kzg_proof":
+		return runComputeKZGProof(data)
+	case "compute_blob_kzg_proof":
+		return runComputeBlobKZGProof(data)
+	case "verify_kzg_proof":
+		return runVerifyKZGProof(data)
+	case "verify_blob_kzg_proof":
+		return runVerifyBlobKZGProof(data)
+	case "verify_blob_kzg_proof_batch":
+		return runVerifyBlobKZGProofBatch(data)
+	case "compute_cells_and_kzg_proofs":
+		return runComputeCellsAndKZGProofs(data)
+	case "recover_cells_and_kzg_proofs":
+		return runRecoverCellsAndKZGProofs(data)
+	case "verify_cell_kzg_proof_batch":
+		return runVerifyCellKZGProofBatch(data)
+	}
+	return fmt.Errorf("unknown function: %s", function)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Helper Functions
+///////////////////////////////////////////////////////////////////////////////
+
+// checkError is used when the inputs were rejected; this is only correct if
+// the test expects no output.
+func checkError(hasOutput bool, err error) error {
+	if hasOutput {
+		return fmt.Errorf("expected output, got error: %w", err)
+	}
+	return nil
+}
+
+func checkEqual(name string, expected, actual []byte) error {
+	if string(expected) != string(actual) {
+		return fmt.Errorf("%s mismatch: expected 0x%x, got 0x%x", name, expected, actual)
+	}
+	return nil
+}
+
+func parseBytes48s(strs []string) ([]ckzg4844.Bytes48, error) {
+	out := make([]ckzg4844.Bytes48, len(strs))
+	for i, s := range strs {
+		if err := out[i].UnmarshalText([]byte(s)); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func parseCells(strs []string) ([]ckzg4844.Cell, error) {
+	out := make([]ckzg4844.Cell, len(strs))
+	for i, s := range strs {
+		if err := out[i].UnmarshalText([]byte(s)); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// checkCellsAndProofs compares cells and proofs with the expected [cells, proofs] output.
+func checkCellsAndProofs(expected [][]string, cells *[ckzg4844.CellsPerExtBlob]ckzg4844.Cell, proofs *[ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof) error {
+	if len(expected) != 2 {
+		return fmt.Errorf("malformed output")
+	}
+	expectedCells, err := parseCells(expected[0])
+	if err != nil {
+		return err
+	}
+	expectedProofs, err := parseBytes48s(expected[1])
+	if err != nil {
+		return err
+	}
+	if len(expectedCells) != len(cells) || len(expectedProofs) != len(proofs) {
+		return fmt.Errorf("expected %d cells and %d proofs", len(expectedCells), len(expectedProofs))
+	}
+	for i := range cells {
+		if err := checkEqual(fmt.Sprintf("cell %d", i), expectedCells[i][:], cells[i][:]); err != nil {
+			return err
+		}
+		if err := checkEqual(fmt.Sprintf("proof %d", i), expectedProofs[i][:], proofs[i][:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkBool(expected *bool, actual bool, err error) error {
+	if err != nil {
+		return checkError(expected != nil, err)
+	}
+	if expected == nil {
+		return fmt.Errorf("expected error, got %v", actual)
+	}
+	if *expected != actual {
+		return fmt.Errorf("expected %v, got %v", *expected, actual)
+	}
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Test Runners
+///////////////////////////////////////////////////////////////////////////////
+
+func runBlobToKZGCommitment(data []byte) error {
+	var test struct {
+		Input struct {
+			Blob string `yaml:"blob"`
+		}
+		Output *ckzg4844.Bytes48 `yaml:"output"`
+	}
+	if err := yaml.Unmarshal(data, &test); err != nil {
+		return err
+	}
+	blob := new(ckzg4844.Blob)
+	if err := blob.UnmarshalText([]byte(test.Input.Blob)); err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	commitment, err := ckzg4844.BlobToKZGCommitment(blob)
+	if err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	if test.Output == nil {
+		return fmt.Errorf("expected error, got commitment")
+	}
+	return checkEqual("commitment", test.Output[:], commitment[:])
+}
+
+func runComputeKZGProof(data []byte) error {
+	var test struct {
+		Input struct {
+			Blob string `yaml:"blob"`
+			Z    string `yaml:"z"`
+		}
+		Output *[]string `yaml:"output"`
+	}
+	if err := yaml.Unmarshal(data, &test); err != nil {
+		return err
+	}
+	blob := new(ckzg4844.Blob)
+	if err := blob.UnmarshalText([]byte(test.Input.Blob)); err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	var z ckzg4844.Bytes32
+	if err := z.UnmarshalText([]byte(test.Input.Z)); err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	proof, y, err := ckzg4844.ComputeKZGProof(blob, z)
+	if err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	if test.Output == nil || len(*test.Output) != 2 {
+		return fmt.Errorf("expected error, got proof")
+	}
+	var expectedProof ckzg4844.Bytes48
+	if err := expectedProof.UnmarshalText([]byte((*test.Output)[0])); err != nil {
+		return err
+	}
+	var expectedY ckzg4844.Bytes32
+	if err := expectedY.UnmarshalText([]byte((*test.Output)[1])); err != nil {
+		return err
+	}
+	if err := checkEqual("proof", expectedProof[:], proof[:]); err != nil {
+		return err
+	}
+	return checkEqual("y", expectedY[:], y[:])
+}
+
+func runComputeBlobKZGProof(data []byte) error {
+	var test struct {
+		Input struct {
+			Blob       string `yaml:"blob"`
+			Commitment string `yaml:"commitment"`
+		}
+		Output *ckzg4844.Bytes48 `yaml:"output"`
+	}
+	if err := yaml.Unmarshal(data, &test); err != nil {
+		return err
+	}
+	blob := new(ckzg4844.Blob)
+	if err := blob.UnmarshalText([]byte(test.Input.Blob)); err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	var commitment ckzg4844.Bytes48
+	if err := commitment.UnmarshalText([]byte(test.Input.Commitment)); err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	proof, err := ckzg4844.ComputeBlobKZGProof(blob, commitment)
+	if err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	if test.Output == nil {
+		return fmt.Errorf("expected error, got proof")
+	}
+	return checkEqual("proof", test.Output[:], proof[:])
+}
+
+func runVerifyKZGProof(data []byte) error {
+	var test struct {
+		Input struct {
+			Commitment string `yaml:"commitment"`
+			Z          string `yaml:"z"`
+			Y          string `yaml:"y"`
+			Proof      string `yaml:"proof"`
+		}
+		Output *bool `yaml:"output"`
+	}
+	if err := yaml.Unmarshal(data, &test); err != nil {
+		return err
+	}
+	var commitment, proof ckzg4844.Bytes48
+	var z, y ckzg4844.Bytes32
+	if err := commitment.UnmarshalText([]byte(test.Input.Commitment)); err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	if err := z.UnmarshalText([]byte(test.Input.Z)); err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	if err := y.UnmarshalText([]byte(test.Input.Y)); err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	if err := proof.UnmarshalText([]byte(test.Input.Proof)); err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	valid, err := ckzg4844.VerifyKZGProof(commitment, z, y, proof)
+	return checkBool(test.Output, valid, err)
+}
+
+func runVerifyBlobKZGProof(data []byte) error {
+	var test struct {
+		Input struct {
+			Blob       string `yaml:"blob"`
+			Commitment string `yaml:"commitment"`
+			Proof      string `yaml:"proof"`
+		}
+		Output *bool `yaml:"output"`
+	}
+	if err := yaml.Unmarshal(data, &test); err != nil {
+		return err
+	}
+	blob := new(ckzg4844.Blob)
+	if err := blob.UnmarshalText([]byte(test.Input.Blob)); err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	var commitment, proof ckzg4844.Bytes48
+	if err := commitment.UnmarshalText([]byte(test.Input.Commitment)); err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	if err := proof.UnmarshalText([]byte(test.Input.Proof)); err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	valid, err := ckzg4844.VerifyBlobKZGProof(blob, commitment, proof)
+	return checkBool(test.Output, valid, err)
+}
+
+func runVerifyBlobKZGProofBatch(data []byte) error {
+	var test struct {
+		Input struct {
+			Blobs       []string `yaml:"blobs"`
+			Commitments []string `yaml:"commitments"`
+			Proofs      []string `yaml:"proofs"`
+		}
+		Output *bool `yaml:"output"`
+	}
+	if err := yaml.Unmarshal(data, &test); err != nil {
+		return err
+	}
+	blobs := make([]ckzg4844.Blob, len(test.Input.Blobs))
+	for i, b := range test.Input.Blobs {
+		if err := blobs[i].UnmarshalText([]byte(b)); err != nil {
+			return checkError(test.Output != nil, err)
+		}
+	}
+	commitments, err := parseBytes48s(test.Input.Commitments)
+	if err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	proofs, err := parseBytes48s(test.Input.Proofs)
+	if err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	valid, err := ckzg4844.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+	return checkBool(test.Output, valid, err)
+}
+
+func runComputeCellsAndKZGProofs(data []byte) error {
+	var test struct {
+		Input struct {
+			Blob string `yaml:"blob"`
+		}
+		Output *[][]string `yaml:"output"`
+	}
+	if err := yaml.Unmarshal(data, &test); err != nil {
+		return err
+	}
+	blob := new(ckzg4844.Blob)
+	if err := blob.UnmarshalText([]byte(test.Input.Blob)); err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	cells := new([ckzg4844.CellsPerExtBlob]ckzg4844.Cell)
+	proofs := new([ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof)
+	if err := ckzg4844.ComputeCellsAndKZGProofsInto(cells, proofs, blob); err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	if test.Output == nil {
+		return fmt.Errorf("expected error, got cells")
+	}
+	return checkCellsAndProofs(*test.Output, cells, proofs)
+}
+
+func runRecoverCellsAndKZGProofs(data []byte) error {
+	var test struct {
+		Input struct {
+			CellIndices []uint64 `yaml:"cell_indices"`
+			Cells       []string `yaml:"cells"`
+		}
+		Output *[][]string `yaml:"output"`
+	}
+	if err := yaml.Unmarshal(data, &test); err != nil {
+		return err
+	}
+	cells, err := parseCells(test.Input.Cells)
+	if err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	recoveredCells := new([ckzg4844.CellsPerExtBlob]ckzg4844.Cell)
+	recoveredProofs := new([ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof)
+	if err := ckzg4844.RecoverCellsAndKZGProofsInto(recoveredCells, recoveredProofs, test.Input.CellIndices, cells); err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	if test.Output == nil {
+		return fmt.Errorf("expected error, got cells")
+	}
+	return checkCellsAndProofs(*test.Output, recoveredCells, recoveredProofs)
+}
+
+func runVerifyCellKZGProofBatch(data []byte) error {
+	var test struct {
+		Input struct {
+			Commitments []string `yaml:"commitments"`
+			CellIndices []uint64 `yaml:"cell_indices"`
+			Cells       []string `yaml:"cells"`
+			Proofs      []string `yaml:"proofs"`
+		}
+		Output *bool `yaml:"output"`
+	}
+	if err := yaml.Unmarshal(data, &test); err != nil {
+		return err
+	}
+	commitments, err := parseBytes48s(test.Input.Commitments)
+	if err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	cells, err := parseCells(test.Input.Cells)
+	if err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	proofs, err := parseBytes48s(test.Input.Proofs)
+	if err != nil {
+		return checkError(test.Output != nil, err)
+	}
+	valid, err := ckzg4844.VerifyCellKZGProofBatch(commitments, test.Input.CellIndices, cells, proofs)
+	return checkBool(test.Output, valid, err)
+}
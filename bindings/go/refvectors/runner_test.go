@@ -0,0 +1,77 @@
+package refvectors
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/ckzgtest"
+)
+
+func requirePassed(t *testing.T, results []Result) {
+	if len(results) == 0 {
+		t.Fatal("no test vectors were run")
+	}
+	for _, result := range results {
+		if !result.Passed() {
+			t.Errorf("%s/%s/%s: %v", result.Function, result.Preset, result.Name, result.Err)
+		}
+	}
+}
+
+func TestRunReferenceTests(t *testing.T) {
+	ckzgtest.LoadTrustedSetup(t)
+	results, err := Run(os.DirFS(ckzgtest.ReferenceTestsDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	requirePassed(t, results)
+}
+
+func TestRunEmbedded(t *testing.T) {
+	ckzgtest.LoadTrustedSetup(t)
+	results, err := RunEmbedded()
+	if errors.Is(err, ErrNotEmbedded) {
+		t.Skip("built without the ckzg_refvectors tag")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	requirePassed(t, results)
+}
+
+func TestRunDir(t *testing.T) {
+	ckzgtest.LoadTrustedSetup(t)
+	results, err := RunDir(ckzgtest.ReferenceTestsDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	requirePassed(t, results)
+
+	if _, err := RunDir(t.TempDir()); err == nil {
+		t.Fatal("expected an error for a directory without tests")
+	}
+	_, err = RunDir("")
+	if _, ok := Embedded(); !ok && !errors.Is(err, ErrNotEmbedded) {
+		t.Fatalf("expected ErrNotEmbedded, got %v", err)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	summaries, failures := Summarize([]Result{
+		{Function: "verify_blob_kzg_proof", Preset: "kzg-mainnet", Name: "a"},
+		{Function: "verify_blob_kzg_proof", Preset: "kzg-mainnet", Name: "b", Err: errors.New("mismatch")},
+		{Function: "blob_to_kzg_commitment", Preset: "kzg-mainnet", Name: "c"},
+	})
+	expected := []Summary{
+		{Function: "blob_to_kzg_commitment", Preset: "kzg-mainnet", Passed: 1},
+		{Function: "verify_blob_kzg_proof", Preset: "kzg-mainnet", Passed: 1, Failed: 1},
+	}
+	if !reflect.DeepEqual(summaries, expected) {
+		t.Fatalf("summaries are %v, expected %v", summaries, expected)
+	}
+	if !reflect.DeepEqual(failures, []Failure{{Function: "verify_blob_kzg_proof", Preset: "kzg-mainnet", Name: "b", Error: "mismatch"}}) {
+		t.Fatalf("unexpected failures %v", failures)
+	}
+}
@@ -0,0 +1,185 @@
+// Package resumable runs bulk recovery and proving jobs, such as historical
+// reconstruction, so that they can be interrupted and resumed: progress is
+// checkpointed after every blob, and a resumed job skips the blobs which were
+// already done instead of starting over.
+//
+// A trusted setup must be loaded before running jobs.
+package resumable
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+)
+
+// ErrInvalidID is returned for a task whose ID can't be recorded in the
+// checkpoint file.
+var ErrInvalidID = errors.New("task IDs must be non-empty and must not contain newlines")
+
+/*
+Checkpoint records which tasks of a job are done, in an append-only file with
+one task ID per line. Every MarkDone is synced to disk before it returns, so a
+task marked done stays done after a crash. It is safe for concurrent use.
+*/
+type Checkpoint struct {
+	mu   sync.Mutex
+	f    *os.File
+	done map[string]bool
+}
+
+// OpenCheckpoint opens the checkpoint at path, creating it if necessary.
+func OpenCheckpoint(path string) (*Checkpoint, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	cp := &Checkpoint{f: f, done: map[string]bool{}}
+	lines := strings.Split(string(data), "\n")
+	// A crash while appending can leave a partial last line. Drop it, so
+	// that its task is redone rather than skipped.
+	last := len(lines) - 1
+	if partial := lines[last]; partial != "" {
+		if err := f.Truncate(int64(len(data) - len(partial))); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	for _, id := range lines[:last] {
+		cp.done[id] = true
+	}
+	return cp, nil
+}
+
+// Done reports whether the task with the given ID is done.
+func (cp *Checkpoint) Done(id string) bool {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	return cp.done[id]
+}
+
+// Len returns the number of tasks which are done.
+func (cp *Checkpoint) Len() int {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	return len(cp.done)
+}
+
+// MarkDone records that the task with the given ID is done.
+func (cp *Checkpoint) MarkDone(id string) error {
+	if id == "" || strings.ContainsAny(id, "\r\n") {
+		return ErrInvalidID
+	}
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	if cp.done[id] {
+		return nil
+	}
+	if _, err := cp.f.WriteString(id + "\n"); err != nil {
+		return err
+	}
+	if err := cp.f.Sync(); err != nil {
+		return err
+	}
+	cp.done[id] = true
+	return nil
+}
+
+// Close closes the checkpoint file.
+func (cp *Checkpoint) Close() error {
+	return cp.f.Close()
+}
+
+// run calls work for every task which isn't done yet and marks it done once
+// work succeeded. It returns the number of tasks skipped as already done.
+func run(ctx context.Context, cp *Checkpoint, ids []string, work func(i int) error) (int, error) {
+	skipped := 0
+	for i, id := range ids {
+		if id == "" || strings.ContainsAny(id, "\r\n") {
+			return skipped, ErrInvalidID
+		}
+		if cp.Done(id) {
+			skipped++
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return skipped, err
+		}
+		if err := work(i); err != nil {
+			return skipped, fmt.Errorf("task %s: %w", id, err)
+		}
+		if err := cp.MarkDone(id); err != nil {
+			return skipped, err
+		}
+	}
+	return skipped, nil
+}
+
+// RecoveryTask is the recovery of the cells and proofs of one blob.
+type RecoveryTask struct {
+	// ID identifies the blob within the job, for example "<slot>/<index>".
+	ID          string
+	CellIndices []uint64
+	Cells       []ckzg4844.Cell
+}
+
+/*
+Recover recovers the cells and proofs of every task which isn't done according
+to cp, passing them to output and marking the task done once output returned
+nil. output must have persisted the result by then. The cells and proofs are
+reused for the next task, so output must copy them to keep them.
+
+It stops at the first error, or when ctx is done, and returns the number of
+tasks skipped because they were already done. Calling it again with the same
+tasks and checkpoint resumes where it stopped.
+*/
+func Recover(ctx context.Context, cp *Checkpoint, tasks []RecoveryTask, output func(id string, cells *[ckzg4844.CellsPerExtBlob]ckzg4844.Cell, proofs *[ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof) error) (int, error) {
+	ids := make([]string, len(tasks))
+	for i := range tasks {
+		ids[i] = tasks[i].ID
+	}
+	cells := new([ckzg4844.CellsPerExtBlob]ckzg4844.Cell)
+	proofs := new([ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof)
+	return run(ctx, cp, ids, func(i int) error {
+		if err := ckzg4844.RecoverCellsAndKZGProofsInto(cells, proofs, tasks[i].CellIndices, tasks[i].Cells); err != nil {
+			return err
+		}
+		return output(tasks[i].ID, cells, proofs)
+	})
+}
+
+// ProvingTask is the computation of the cells and proofs of one blob.
+type ProvingTask struct {
+	// ID identifies the blob within the job.
+	ID   string
+	Blob *ckzg4844.Blob
+}
+
+/*
+ComputeCellsAndKZGProofs computes the cells and proofs of every task which
+isn't done according to cp, like Recover.
+*/
+func ComputeCellsAndKZGProofs(ctx context.Context, cp *Checkpoint, tasks []ProvingTask, output func(id string, cells *[ckzg4844.CellsPerExtBlob]ckzg4844.Cell, proofs *[ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof) error) (int, error) {
+	ids := make([]string, len(tasks))
+	for i := range tasks {
+		ids[i] = tasks[i].ID
+	}
+	cells := new([ckzg4844.CellsPerExtBlob]ckzg4844.Cell)
+	proofs := new([ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof)
+	return run(ctx, cp, ids, func(i int) error {
+		if err := ckzg4844.ComputeCellsAndKZGProofsInto(cells, proofs, tasks[i].Blob); err != nil {
+			return err
+		}
+		return output(tasks[i].ID, cells, proofs)
+	})
+}
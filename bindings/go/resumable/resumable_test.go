@@ -0,0 +1,76 @@
+package resumable
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/ckzgtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpointPartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint")
+	require.NoError(t, os.WriteFile(path, []byte("a\nbc"), 0o644))
+	cp, err := OpenCheckpoint(path)
+	require.NoError(t, err)
+	require.True(t, cp.Done("a"))
+	require.False(t, cp.Done("bc"))
+	require.NoError(t, cp.MarkDone("b"))
+	require.ErrorIs(t, cp.MarkDone("c\nd"), ErrInvalidID)
+	require.NoError(t, cp.Close())
+
+	cp, err = OpenCheckpoint(path)
+	require.NoError(t, err)
+	defer cp.Close()
+	require.True(t, cp.Done("b"))
+	require.False(t, cp.Done("bc"))
+	require.Equal(t, 2, cp.Len())
+}
+
+func TestRecoverResume(t *testing.T) {
+	ckzgtest.LoadTrustedSetup(t)
+	indices := ckzgtest.HalfCellIndices()
+	var tasks []RecoveryTask
+	var expected [][ckzg4844.CellsPerExtBlob]ckzg4844.Cell
+	for i := 0; i < 3; i++ {
+		cells, _, err := ckzg4844.ComputeCellsAndKZGProofs(ckzgtest.RandomBlob(int64(i)))
+		require.NoError(t, err)
+		task := RecoveryTask{ID: string(rune('a' + i)), CellIndices: indices}
+		for _, index := range indices {
+			task.Cells = append(task.Cells, cells[index])
+		}
+		tasks = append(tasks, task)
+		expected = append(expected, cells)
+	}
+
+	path := filepath.Join(t.TempDir(), "checkpoint")
+	cp, err := OpenCheckpoint(path)
+	require.NoError(t, err)
+	errInterrupted := errors.New("interrupted")
+	var recovered []string
+	output := func(id string, cells *[ckzg4844.CellsPerExtBlob]ckzg4844.Cell, _ *[ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof) error {
+		if id == "b" && len(recovered) == 1 {
+			return errInterrupted
+		}
+		require.Equal(t, expected[id[0]-'a'], *cells)
+		recovered = append(recovered, id)
+		return nil
+	}
+	skipped, err := Recover(context.Background(), cp, tasks, output)
+	require.ErrorIs(t, err, errInterrupted)
+	require.Equal(t, 0, skipped)
+	require.NoError(t, cp.Close())
+
+	cp, err = OpenCheckpoint(path)
+	require.NoError(t, err)
+	defer cp.Close()
+	recovered = append(recovered, "resumed")
+	skipped, err = Recover(context.Background(), cp, tasks, output)
+	require.NoError(t, err)
+	require.Equal(t, 1, skipped)
+	require.Equal(t, []string{"a", "resumed", "b", "c"}, recovered)
+}
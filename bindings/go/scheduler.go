@@ -0,0 +1,430 @@
+package ckzg4844
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// ErrSchedulerClosed is returned by requests made to a BatchScheduler after it
+// was closed.
+var ErrSchedulerClosed = errors.New("batch scheduler is closed")
+
+// Defaults for BatchSchedulerConfig.
+const (
+	DefaultMaxBatchSize  = 64
+	DefaultMaxBatchDelay = 5 * time.Millisecond
+)
+
+/*
+Priority is the class of a verification request. Batches of a higher priority
+class are verified before any waiting batch of a lower class, so that block
+import isn't delayed behind mempool or backfill work.
+*/
+type Priority int
+
+const (
+	// PriorityCritical is for work on the critical path, such as block import.
+	PriorityCritical Priority = iota
+	// PriorityBackground is for work such as mempool validation. It is the
+	// priority of requests which don't specify one.
+	PriorityBackground
+	// PriorityBackfill is for work such as syncing archival data.
+	PriorityBackfill
+
+	numPriorities = int(PriorityBackfill) + 1
+)
+
+type priorityKey struct{}
+
+// WithPriority returns a context which makes BatchScheduler requests made with
+// it use priority p.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+func priorityFromContext(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok && p >= 0 && int(p) < numPriorities {
+		return p
+	}
+	return PriorityBackground
+}
+
+// BatchSchedulerConfig configures a BatchScheduler.
+type BatchSchedulerConfig struct {
+	// MaxBatchSize is the number of requests of one kind and priority which
+	// are verified together. A batch is verified as soon as it is full.
+	// Defaults to DefaultMaxBatchSize.
+	MaxBatchSize int
+	// MaxDelay is how long the first request of a batch waits for others to
+	// arrive before the batch is verified anyway. Defaults to
+	// DefaultMaxBatchDelay.
+	MaxDelay time.Duration
+	// Workers is the number of batches which are verified at once. Defaults
+	// to runtime.NumCPU().
+	Workers int
+	// Limiter, if set, admits every batch before it is verified. Requests in
+	// batches which it rejects fail with its error.
+	Limiter *Limiter
+}
+
+type verifyResult struct {
+	ok  bool
+	err error
+}
+
+type blobRequest struct {
+	priority   Priority
+	blob       Blob
+	commitment Bytes48
+	proof      Bytes48
+	result     chan verifyResult
+}
+
+type cellRequest struct {
+	priority   Priority
+	commitment Bytes48
+	cellIndex  uint64
+	cell       Cell
+	proof      Bytes48
+	result     chan verifyResult
+}
+
+// batchQueue holds batches which are ready to be verified, by priority.
+type batchQueue struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	batches [numPriorities][]func()
+	closed  bool
+}
+
+func newBatchQueue() *batchQueue {
+	q := &batchQueue{}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+func (q *batchQueue) push(p Priority, batch func()) {
+	q.mu.Lock()
+	q.batches[p] = append(q.batches[p], batch)
+	q.mu.Unlock()
+	q.cond.Signal()
+}
+
+// pop returns the oldest batch of the highest priority, waiting for one if
+// necessary. It returns nil once the queue is closed and empty.
+func (q *batchQueue) pop() func() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for {
+		for p := range q.batches {
+			if len(q.batches[p]) > 0 {
+				batch := q.batches[p][0]
+				q.batches[p][0] = nil
+				q.batches[p] = q.batches[p][1:]
+				return batch
+			}
+		}
+		if q.closed {
+			return nil
+		}
+		q.cond.Wait()
+	}
+}
+
+func (q *batchQueue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.cond.Broadcast()
+}
+
+/*
+BatchScheduler collects verification requests arriving from many goroutines
+and verifies them in batches, which is much cheaper per item than verifying
+each request on its own. Requests wait at most MaxDelay for a batch to fill.
+Requests are only batched with requests of the same priority, see WithPriority.
+
+When a batch doesn't verify, it is split in half until the items which don't
+verify are isolated, so that every caller learns whether its own item is valid
+without a single bad item costing a verification of every other item.
+*/
+type BatchScheduler struct {
+	config  BatchSchedulerConfig
+	blobs   chan *blobRequest
+	cells   chan *cellRequest
+	queue   *batchQueue
+	quit    chan struct{}
+	done    chan struct{}
+	workers sync.WaitGroup
+
+	closeOnce sync.Once
+	mu        sync.RWMutex
+	closed    bool
+}
+
+// NewBatchScheduler starts a BatchScheduler. Call Close to stop it.
+func NewBatchScheduler(config BatchSchedulerConfig) *BatchScheduler {
+	if config.MaxBatchSize <= 0 {
+		config.MaxBatchSize = DefaultMaxBatchSize
+	}
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = DefaultMaxBatchDelay
+	}
+	if config.Workers <= 0 {
+		config.Workers = runtime.NumCPU()
+	}
+	s := &BatchScheduler{
+		config: config,
+		blobs:  make(chan *blobRequest),
+		cells:  make(chan *cellRequest),
+		queue:  newBatchQueue(),
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	for i := 0; i < config.Workers; i++ {
+		s.workers.Add(1)
+		go s.worker()
+	}
+	go s.loop()
+	return s
+}
+
+func (s *BatchScheduler) worker() {
+	defer s.workers.Done()
+	for batch := s.queue.pop(); batch != nil; batch = s.queue.pop() {
+		batch()
+	}
+}
+
+func (s *BatchScheduler) loop() {
+	defer close(s.done)
+	var (
+		blobs [numPriorities][]*blobRequest
+		cells [numPriorities][]*cellRequest
+		timer = time.NewTimer(0)
+		armed bool
+	)
+	<-timer.C
+	arm := func() {
+		if !armed {
+			timer.Reset(s.config.MaxDelay)
+			armed = true
+		}
+	}
+	flush := func() {
+		for p := range blobs {
+			if len(blobs[p]) > 0 {
+				s.dispatchBlobs(Priority(p), blobs[p])
+				blobs[p] = nil
+			}
+			if len(cells[p]) > 0 {
+				s.dispatchCells(Priority(p), cells[p])
+				cells[p] = nil
+			}
+		}
+	}
+	for {
+		select {
+		case req := <-s.blobs:
+			p := req.priority
+			blobs[p] = append(blobs[p], req)
+			if len(blobs[p]) >= s.config.MaxBatchSize {
+				s.dispatchBlobs(p, blobs[p])
+				blobs[p] = nil
+			} else {
+				arm()
+			}
+		case req := <-s.cells:
+			p := req.priority
+			cells[p] = append(cells[p], req)
+			if len(cells[p]) >= s.config.MaxBatchSize {
+				s.dispatchCells(p, cells[p])
+				cells[p] = nil
+			} else {
+				arm()
+			}
+		case <-timer.C:
+			armed = false
+			flush()
+		case <-s.quit:
+			if armed && !timer.Stop() {
+				<-timer.C
+			}
+			flush()
+			return
+		}
+	}
+}
+
+// admit waits for the limiter, if any, to admit a batch of size bytes.
+func (s *BatchScheduler) admit(size int64) (func(), error) {
+	if s.config.Limiter == nil {
+		return func() {}, nil
+	}
+	return s.config.Limiter.Acquire(context.Background(), size)
+}
+
+func (s *BatchScheduler) dispatchBlobs(p Priority, reqs []*blobRequest) {
+	s.queue.push(p, func() {
+		release, err := s.admit(int64(len(reqs)) * BytesPerBlob)
+		if err != nil {
+			for _, req := range reqs {
+				req.result <- verifyResult{err: err}
+			}
+			return
+		}
+		defer release()
+		verifyBlobRequests(reqs)
+	})
+}
+
+func (s *BatchScheduler) dispatchCells(p Priority, reqs []*cellRequest) {
+	s.queue.push(p, func() {
+		release, err := s.admit(int64(len(reqs)) * BytesPerCell)
+		if err != nil {
+			for _, req := range reqs {
+				req.result <- verifyResult{err: err}
+			}
+			return
+		}
+		defer release()
+		verifyCellRequests(reqs)
+	})
+}
+
+/*
+bisect verifies the items start to end with verify, which verifies a range of
+items, and calls done with the result of each of them. A range which doesn't
+verify is split in half and both halves are verified again, down to single
+items, so a few bad items in a batch cost a logarithmic number of extra
+verifications rather than one for every item.
+*/
+func bisect(start, end int, verify func(start, end int) (bool, error), done func(i int, ok bool, err error)) {
+	ok, err := verify(start, end)
+	switch {
+	case err == nil && ok:
+		for i := start; i < end; i++ {
+			done(i, true, nil)
+		}
+	case end-start == 1:
+		done(start, ok, err)
+	default:
+		mid := start + (end-start)/2
+		bisect(start, mid, verify, done)
+		bisect(mid, end, verify, done)
+	}
+}
+
+func verifyBlobRequests(reqs []*blobRequest) {
+	blobs := make([]Blob, len(reqs))
+	commitments := make([]Bytes48, len(reqs))
+	proofs := make([]Bytes48, len(reqs))
+	for i, req := range reqs {
+		blobs[i], commitments[i], proofs[i] = req.blob, req.commitment, req.proof
+	}
+	bisect(0, len(reqs), func(start, end int) (bool, error) {
+		return VerifyBlobKZGProofBatch(blobs[start:end], commitments[start:end], proofs[start:end])
+	}, func(i int, ok bool, err error) {
+		reqs[i].result <- verifyResult{ok: ok, err: err}
+	})
+}
+
+func verifyCellRequests(reqs []*cellRequest) {
+	commitments := make([]Bytes48, len(reqs))
+	cellIndices := make([]uint64, len(reqs))
+	cells := make([]Cell, len(reqs))
+	proofs := make([]Bytes48, len(reqs))
+	for i, req := range reqs {
+		commitments[i], cellIndices[i], cells[i], proofs[i] = req.commitment, req.cellIndex, req.cell, req.proof
+	}
+	bisect(0, len(reqs), func(start, end int) (bool, error) {
+		return VerifyCellKZGProofBatch(commitments[start:end], cellIndices[start:end], cells[start:end], proofs[start:end])
+	}, func(i int, ok bool, err error) {
+		reqs[i].result <- verifyResult{ok: ok, err: err}
+	})
+}
+
+// wait returns the result of a submitted request, or the error of ctx.
+func wait(ctx context.Context, result chan verifyResult) (bool, error) {
+	select {
+	case r := <-result:
+		return r.ok, r.err
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// VerifyBlobKZGProof verifies a blob proof as part of a batch, with the
+// priority of ctx. It blocks until the batch is verified or ctx is done. The
+// blob is copied, so the caller may reuse it once this returns.
+func (s *BatchScheduler) VerifyBlobKZGProof(ctx context.Context, blob *Blob, commitmentBytes, proofBytes Bytes48) (bool, error) {
+	if blob == nil {
+		return false, &Error{Op: "VerifyBlobKZGProof", Err: ErrBadArgs}
+	}
+	req := &blobRequest{
+		priority:   priorityFromContext(ctx),
+		blob:       *blob,
+		commitment: commitmentBytes,
+		proof:      proofBytes,
+		result:     make(chan verifyResult, 1),
+	}
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return false, ErrSchedulerClosed
+	}
+	select {
+	case s.blobs <- req:
+		s.mu.RUnlock()
+	case <-ctx.Done():
+		s.mu.RUnlock()
+		return false, ctx.Err()
+	}
+	return wait(ctx, req.result)
+}
+
+// VerifyCellKZGProof verifies a cell proof as part of a batch, with the
+// priority of ctx. It blocks until the batch is verified or ctx is done.
+func (s *BatchScheduler) VerifyCellKZGProof(ctx context.Context, commitmentBytes Bytes48, cellIndex uint64, cell *Cell, proofBytes Bytes48) (bool, error) {
+	if cell == nil {
+		return false, &Error{Op: "VerifyCellKZGProof", Err: ErrBadArgs}
+	}
+	req := &cellRequest{
+		priority:   priorityFromContext(ctx),
+		commitment: commitmentBytes,
+		cellIndex:  cellIndex,
+		cell:       *cell,
+		proof:      proofBytes,
+		result:     make(chan verifyResult, 1),
+	}
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return false, ErrSchedulerClosed
+	}
+	select {
+	case s.cells <- req:
+		s.mu.RUnlock()
+	case <-ctx.Done():
+		s.mu.RUnlock()
+		return false, ctx.Err()
+	}
+	return wait(ctx, req.result)
+}
+
+// Close verifies the requests which are still waiting for a batch and stops
+// the scheduler. Requests made after Close return ErrSchedulerClosed.
+func (s *BatchScheduler) Close() {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		s.mu.Unlock()
+		close(s.quit)
+		<-s.done
+		s.queue.close()
+		s.workers.Wait()
+	})
+}
@@ -0,0 +1,117 @@
+package ckzg4844
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchScheduler(t *testing.T) {
+	blob := selfTestBlob()
+	commitment, err := BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+	proof, err := ComputeBlobKZGProof(blob, Bytes48(commitment))
+	require.NoError(t, err)
+	cells, cellProofs, err := ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+
+	fi := NewFaultInjector()
+	SetFaultInjector(fi)
+	defer SetFaultInjector(nil)
+
+	const n = 16
+	s := NewBatchScheduler(BatchSchedulerConfig{MaxBatchSize: n, MaxDelay: time.Second})
+	defer s.Close()
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	blobResults := make([]bool, n)
+	cellResults := make([]bool, n)
+	errs := make(chan error, 2*n)
+	for i := 0; i < n; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			p := proof
+			if i == 3 {
+				// Use a valid point which isn't the proof.
+				p = KZGProof(commitment)
+			}
+			ok, err := s.VerifyBlobKZGProof(ctx, blob, Bytes48(commitment), Bytes48(p))
+			blobResults[i] = ok
+			errs <- err
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			ok, err := s.VerifyCellKZGProof(ctx, Bytes48(commitment), uint64(i), &cells[i], Bytes48(cellProofs[i]))
+			cellResults[i] = ok
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	for i := 0; i < n; i++ {
+		require.Equal(t, i != 3, blobResults[i], "blob %d", i)
+		require.True(t, cellResults[i], "cell %d", i)
+	}
+	// The failing batch is bisected: both halves of each range containing the
+	// bad proof are verified again, rather than every item on its own.
+	require.Equal(t, 1+2*4, fi.Calls("VerifyBlobKZGProofBatch"))
+	require.Equal(t, 0, fi.Calls("VerifyBlobKZGProof"))
+	require.Equal(t, 1, fi.Calls("VerifyCellKZGProofBatch"))
+}
+
+func TestBatchSchedulerDelayAndClose(t *testing.T) {
+	blob := selfTestBlob()
+	commitment, err := BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+	proof, err := ComputeBlobKZGProof(blob, Bytes48(commitment))
+	require.NoError(t, err)
+
+	s := NewBatchScheduler(BatchSchedulerConfig{MaxDelay: time.Millisecond})
+	ok, err := s.VerifyBlobKZGProof(context.Background(), blob, Bytes48(commitment), Bytes48(proof))
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	s.Close()
+	_, err = s.VerifyBlobKZGProof(context.Background(), blob, Bytes48(commitment), Bytes48(proof))
+	require.ErrorIs(t, err, ErrSchedulerClosed)
+}
+
+func TestBatchQueuePriority(t *testing.T) {
+	q := newBatchQueue()
+	var order []string
+	q.push(PriorityBackfill, func() { order = append(order, "backfill") })
+	q.push(PriorityBackground, func() { order = append(order, "background 1") })
+	q.push(PriorityCritical, func() { order = append(order, "critical") })
+	q.push(PriorityBackground, func() { order = append(order, "background 2") })
+	q.close()
+	for batch := q.pop(); batch != nil; batch = q.pop() {
+		batch()
+	}
+	require.Equal(t, []string{"critical", "background 1", "background 2", "backfill"}, order)
+}
+
+func TestBatchSchedulerPriority(t *testing.T) {
+	blob := selfTestBlob()
+	commitment, err := BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+	proof, err := ComputeBlobKZGProof(blob, Bytes48(commitment))
+	require.NoError(t, err)
+
+	s := NewBatchScheduler(BatchSchedulerConfig{MaxDelay: time.Millisecond, Workers: 1})
+	defer s.Close()
+	require.Equal(t, PriorityBackground, priorityFromContext(context.Background()))
+	ctx := WithPriority(context.Background(), PriorityCritical)
+	require.Equal(t, PriorityCritical, priorityFromContext(ctx))
+	ok, err := s.VerifyBlobKZGProof(ctx, blob, Bytes48(commitment), Bytes48(proof))
+	require.NoError(t, err)
+	require.True(t, ok)
+}
@@ -0,0 +1,188 @@
+package ckzg4844
+
+// #cgo CFLAGS: -I${SRCDIR}/../../src
+// #cgo CFLAGS: -I${SRCDIR}/blst_headers
+// #include "ckzg.h"
+import "C"
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrSelfTestFailed means a trusted setup failed its self test.
+var ErrSelfTestFailed = errors.New("self test failed")
+
+// mainnetFingerprint is the fingerprint of the mainnet trusted setup, for
+// which the known answers hold.
+var mainnetFingerprint = [32]byte{
+	0x8d, 0x77, 0x2b, 0x06, 0x96, 0xfd, 0x2e, 0xc1, 0xf3, 0x9b, 0x34, 0x0b, 0x62, 0x8f, 0x2e, 0x78,
+	0x97, 0xaa, 0xa5, 0x5c, 0xbb, 0xb2, 0x51, 0x93, 0xb4, 0xcd, 0xf5, 0x2b, 0xe9, 0x39, 0xfa, 0xdb,
+}
+
+// Known answers for the mainnet trusted setup, computed with selfTestBlob.
+const (
+	selfTestCommitment = "b6b9804594a3ec4d0d6a7233d9daa1bf152b10c35eabe8925197e97bcfa406dc5a369748dfefa3eb3f0b54fc6a050861"
+	selfTestBlobProof  = "b3704e48d87127bdceae1fd9fdd792754a5039fb103a7406b594077980a201b9caa3a2a13d4136cc22ff8e9dd9a560b5"
+	selfTestKZGProof   = "b80fd14ac96ca247f829c3ef23cbd5cf7f45a0650752ba18a1547fe550d49496aa17775dd5d1ac9d4435c4226918500e"
+	selfTestY          = "32b69394009bfaa512340ad2f24c6660420049151a47992b66795a1377351f47"
+	selfTestCellProof0 = "ae9b2667c9f319d225e4cd2d0ce2e0c7c21197593c351dd63013bf462e5746f04f15dab5916bc9b4c83945ddf5dac7f0"
+)
+
+// selfTestBlob returns a blob where the i-th field element is equal to i.
+func selfTestBlob() *Blob {
+	blob := new(Blob)
+	for i := 0; i < FieldElementsPerBlob; i++ {
+		offset := (i + 1) * BytesPerFieldElement
+		blob[offset-1] = byte(i)
+		blob[offset-2] = byte(i >> 8)
+	}
+	return blob
+}
+
+// selfTestZ returns the evaluation point used for the known-answer proof.
+func selfTestZ() Bytes32 {
+	var z Bytes32
+	z[len(z)-1] = 7
+	return z
+}
+
+func selfTestCheck(name string, expected string, actual []byte) error {
+	if hex.EncodeToString(actual) != expected {
+		return fmt.Errorf("%w: %s mismatch: expected 0x%s, got 0x%x", ErrSelfTestFailed, name, expected, actual)
+	}
+	return nil
+}
+
+var selfTestOnLoad atomic.Bool
+
+/*
+EnableSelfTestOnLoad makes every load of a trusted setup, with LoadTrustedSetup*
+or NewContext*, run the self test of the loaded setup before returning. A setup
+which fails it is freed again, and the load returns the self test error.
+
+It is disabled by default.
+*/
+func EnableSelfTestOnLoad(enabled bool) {
+	selfTestOnLoad.Store(enabled)
+}
+
+// loadSelfTest runs the self test for op if it is enabled, freeing the setup
+// of c if it fails.
+func (c *Context) loadSelfTest(op string) error {
+	if !selfTestOnLoad.Load() {
+		return nil
+	}
+	if err := c.SelfTest(); err != nil {
+		C.free_trusted_setup(&c.settings)
+		c.loaded = false
+		return &Error{Op: op, Err: err}
+	}
+	return nil
+}
+
+// SelfTest runs the self test of Context.SelfTest for the default context.
+func SelfTest() error {
+	return defaultContext.SelfTest()
+}
+
+/*
+SelfTest runs a small battery of tests against the trusted setup of c. It is
+meant to be run right after the setup is loaded, before any real data is
+processed (see EnableSelfTestOnLoad), and catches corrupted setups, miscompiled
+C code, or a broken blst build.
+
+Every setup must produce proofs which verify, reject a wrong evaluation, and
+recover cells and proofs from half of the cells. The mainnet setup must also
+reproduce known answers, which other setups can't.
+*/
+func (c *Context) SelfTest() error {
+	if !c.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	blob := selfTestBlob()
+	knownAnswers := c.Fingerprint() == mainnetFingerprint
+	check := func(name string, expected string, actual []byte) error {
+		if !knownAnswers {
+			return nil
+		}
+		return selfTestCheck(name, expected, actual)
+	}
+
+	commitment, err := c.BlobToKZGCommitment(blob)
+	if err != nil {
+		return fmt.Errorf("%w: BlobToKZGCommitment: %v", ErrSelfTestFailed, err)
+	}
+	if err := check("commitment", selfTestCommitment, commitment[:]); err != nil {
+		return err
+	}
+
+	proof, err := c.ComputeBlobKZGProof(blob, Bytes48(commitment))
+	if err != nil {
+		return fmt.Errorf("%w: ComputeBlobKZGProof: %v", ErrSelfTestFailed, err)
+	}
+	if err := check("blob proof", selfTestBlobProof, proof[:]); err != nil {
+		return err
+	}
+	ok, err := c.VerifyBlobKZGProof(blob, Bytes48(commitment), Bytes48(proof))
+	if err != nil || !ok {
+		return fmt.Errorf("%w: VerifyBlobKZGProof rejected known-good proof", ErrSelfTestFailed)
+	}
+
+	z := selfTestZ()
+	kzgProof, y, err := c.ComputeKZGProof(blob, z)
+	if err != nil {
+		return fmt.Errorf("%w: ComputeKZGProof: %v", ErrSelfTestFailed, err)
+	}
+	if err := check("kzg proof", selfTestKZGProof, kzgProof[:]); err != nil {
+		return err
+	}
+	// The evaluation doesn't depend on the setup.
+	if err := selfTestCheck("y", selfTestY, y[:]); err != nil {
+		return err
+	}
+	ok, err = c.VerifyKZGProof(Bytes48(commitment), z, y, Bytes48(kzgProof))
+	if err != nil || !ok {
+		return fmt.Errorf("%w: VerifyKZGProof rejected known-good proof", ErrSelfTestFailed)
+	}
+	wrongY := y
+	wrongY[len(wrongY)-1] ^= 1
+	ok, err = c.VerifyKZGProof(Bytes48(commitment), z, wrongY, Bytes48(kzgProof))
+	if err != nil || ok {
+		return fmt.Errorf("%w: VerifyKZGProof accepted a wrong evaluation", ErrSelfTestFailed)
+	}
+
+	cells, cellProofs, err := c.ComputeCellsAndKZGProofs(blob)
+	if err != nil {
+		return fmt.Errorf("%w: ComputeCellsAndKZGProofs: %v", ErrSelfTestFailed, err)
+	}
+	if err := check("cell proof", selfTestCellProof0, cellProofs[0][:]); err != nil {
+		return err
+	}
+	ok, err = c.VerifyCellKZGProofBatch(
+		[]Bytes48{Bytes48(commitment)},
+		[]uint64{0},
+		[]Cell{cells[0]},
+		[]Bytes48{Bytes48(cellProofs[0])})
+	if err != nil || !ok {
+		return fmt.Errorf("%w: VerifyCellKZGProofBatch rejected known-good proof", ErrSelfTestFailed)
+	}
+
+	cellIndices := make([]uint64, CellsPerExtBlob/2)
+	partialCells := make([]Cell, len(cellIndices))
+	for i := range cellIndices {
+		cellIndices[i] = uint64(2*i + 1)
+		partialCells[i] = cells[cellIndices[i]]
+	}
+	recoveredCells, recoveredProofs, err := c.RecoverCellsAndKZGProofs(cellIndices, partialCells)
+	if err != nil {
+		return fmt.Errorf("%w: RecoverCellsAndKZGProofs: %v", ErrSelfTestFailed, err)
+	}
+	if recoveredCells != cells || recoveredProofs != cellProofs {
+		return fmt.Errorf("%w: RecoverCellsAndKZGProofs recovered wrong cells or proofs", ErrSelfTestFailed)
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package ckzg4844
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+/*
+ParseTrustedSetup parses a trusted setup in the format read by
+LoadTrustedSetupFile, and returns its points as LoadTrustedSetup takes them:
+
+	<number of g1 points>
+	<number of g2 points>
+	<g1 lagrange points, one per line>
+	<g2 monomial points, one per line>
+	<g1 monomial points, one per line>
+
+Malformed input returns an error wrapping ErrBadArgs. The points themselves
+are only checked once they are loaded.
+*/
+func ParseTrustedSetup(r io.Reader) (g1MonomialBytes, g1LagrangeBytes, g2MonomialBytes []byte, err error) {
+	scanner := bufio.NewScanner(r)
+	nextLine := func() (string, error) {
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				return line, nil
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("%w: unexpected end of trusted setup", ErrBadArgs)
+	}
+	readCount := func() (int, error) {
+		line, err := nextLine()
+		if err != nil {
+			return 0, err
+		}
+		n, err := strconv.Atoi(line)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%w: invalid count %q", ErrBadArgs, line)
+		}
+		return n, nil
+	}
+	readPoints := func(n, size int) ([]byte, error) {
+		// The counts aren't trusted, so they don't size the allocation.
+		out := make([]byte, 0, size*FieldElementsPerBlob)
+		for i := 0; i < n; i++ {
+			line, err := nextLine()
+			if err != nil {
+				return nil, err
+			}
+			point, err := hex.DecodeString(strings.TrimPrefix(line, "0x"))
+			if err != nil || len(point) != size {
+				return nil, fmt.Errorf("%w: point %d isn't %d hex encoded bytes", ErrBadArgs, i, size)
+			}
+			out = append(out, point...)
+		}
+		return out, nil
+	}
+
+	numG1, err := readCount()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read number of g1 points: %w", err)
+	}
+	numG2, err := readCount()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read number of g2 points: %w", err)
+	}
+	if g1LagrangeBytes, err = readPoints(numG1, 48); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read g1 lagrange points: %w", err)
+	}
+	if g2MonomialBytes, err = readPoints(numG2, 96); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read g2 monomial points: %w", err)
+	}
+	if g1MonomialBytes, err = readPoints(numG1, 48); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read g1 monomial points: %w", err)
+	}
+	if _, err := nextLine(); err == nil {
+		return nil, nil, nil, fmt.Errorf("%w: unexpected data after the last point", ErrBadArgs)
+	}
+	return g1MonomialBytes, g1LagrangeBytes, g2MonomialBytes, nil
+}
+
+/*
+LoadTrustedSetupFromReader is like LoadTrustedSetupFile, but reads the trusted
+setup from r, so that it doesn't need to be a file on disk. The mainnet
+trusted setup is embedded in the mainnet package.
+*/
+func LoadTrustedSetupFromReader(r io.Reader, precompute uint) error {
+	if defaultContext.loaded {
+		panic("trusted setup is already loaded")
+	}
+	g1MonomialBytes, g1LagrangeBytes, g2MonomialBytes, err := ParseTrustedSetup(r)
+	if err != nil {
+		return err
+	}
+	return defaultContext.load(g1MonomialBytes, g1LagrangeBytes, g2MonomialBytes, precompute)
+}
+
+// NewContextFromReader loads a trusted setup like LoadTrustedSetupFromReader,
+// into a new context.
+func NewContextFromReader(r io.Reader, precompute uint) (*Context, error) {
+	g1MonomialBytes, g1LagrangeBytes, g2MonomialBytes, err := ParseTrustedSetup(r)
+	if err != nil {
+		return nil, err
+	}
+	return NewContext(g1MonomialBytes, g1LagrangeBytes, g2MonomialBytes, precompute)
+}
+
+/*
+TrustedSetupFingerprint returns a hash which identifies the loaded trusted
+setup. It is the same every time a setup is loaded, so it can be stored with
+values which depend on the setup, such as cached proofs, to tell them apart
+from values computed with another setup.
+*/
+func TrustedSetupFingerprint() [32]byte {
+	return defaultContext.Fingerprint()
+}
+
+// Fingerprint is TrustedSetupFingerprint for the trusted setup of c.
+func (c *Context) Fingerprint() [32]byte {
+	if !c.loaded {
+		panic("trusted setup isn't loaded")
+	}
+	return c.setupHash()
+}
@@ -0,0 +1,33 @@
+package ckzg4844
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTrustedSetup(t *testing.T) {
+	f, err := os.Open("../../src/trusted_setup.txt")
+	require.NoError(t, err)
+	defer f.Close()
+	g1Monomial, g1Lagrange, g2Monomial, err := ParseTrustedSetup(f)
+	require.NoError(t, err)
+	require.Len(t, g1Monomial, FieldElementsPerBlob*48)
+	require.Len(t, g1Lagrange, FieldElementsPerBlob*48)
+	require.Len(t, g2Monomial, 65*96)
+
+	for _, input := range []string{
+		"",
+		"x\n1\n",
+		"1\n1\n" + strings.Repeat("ab", 48) + "\n",
+		"1\n0\n" + strings.Repeat("ab", 47) + "\n" + strings.Repeat("ab", 48) + "\n",
+		"1\n0\n" + strings.Repeat("ab", 48) + "\n" + strings.Repeat("ab", 48) + "\nab\n",
+	} {
+		_, _, _, err := ParseTrustedSetup(strings.NewReader(input))
+		require.ErrorIs(t, err, ErrBadArgs)
+	}
+	_, _, _, err = ParseTrustedSetup(strings.NewReader("1\n0\n0x" + strings.Repeat("ab", 48) + "\n\n" + strings.Repeat("cd", 48)))
+	require.NoError(t, err)
+}
@@ -0,0 +1,152 @@
+package simulation
+
+import (
+	"math/big"
+	"sync"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+)
+
+// The cells are a Reed-Solomon code over the scalar field, like the real
+// backend's, but with the much simpler evaluation points 0, 1, ..., 127: the
+// j-th field elements of the cells are the evaluations at the cell indices of
+// the polynomial of degree less than 64 whose evaluations at 0, ..., 63 are
+// the j-th field elements of the blob's cells. Any 64 cells determine those
+// polynomials, and so the blob.
+
+const fieldElementsPerCell = ckzg4844.BytesPerCell / ckzg4844.BytesPerFieldElement
+
+var modulus = new(big.Int).SetBytes(ckzg4844.BLSModulus[:])
+
+var (
+	// inverses[d] is the inverse of d, for 0 < d < CellsPerExtBlob. The
+	// differences of evaluation points are all small integers.
+	inverses [ckzg4844.CellsPerExtBlob]*big.Int
+	// extension[i][k] is the coefficient of cell k in cell cellsPerBlob+i.
+	extension   [cellsPerBlob][]*big.Int
+	erasureOnce sync.Once
+)
+
+func initErasure() {
+	for d := 1; d < len(inverses); d++ {
+		inverses[d] = new(big.Int).ModInverse(big.NewInt(int64(d)), modulus)
+	}
+	data := make([]int, cellsPerBlob)
+	for k := range data {
+		data[k] = k
+	}
+	ip := newInterpolator(data)
+	for i := range extension {
+		extension[i] = ip.coefficients(cellsPerBlob + i)
+	}
+}
+
+// difference returns a - b in the field.
+func difference(a, b int) *big.Int {
+	return new(big.Int).Mod(big.NewInt(int64(a-b)), modulus)
+}
+
+// inverseDifference returns 1 / (a - b) in the field, for a != b.
+func inverseDifference(a, b int) *big.Int {
+	if a > b {
+		return inverses[a-b]
+	}
+	return new(big.Int).Sub(modulus, inverses[b-a])
+}
+
+// interpolator computes the coefficients which interpolate the evaluations
+// at points to the evaluation at another point.
+type interpolator struct {
+	points []int
+	// weights[k] is the inverse of the product of points[k] - points[l] for
+	// l != k.
+	weights []*big.Int
+}
+
+func newInterpolator(points []int) *interpolator {
+	weights := make([]*big.Int, len(points))
+	for k, point := range points {
+		w := big.NewInt(1)
+		for _, other := range points {
+			if other != point {
+				w.Mul(w, inverseDifference(point, other))
+				w.Mod(w, modulus)
+			}
+		}
+		weights[k] = w
+	}
+	return &interpolator{points: points, weights: weights}
+}
+
+// coefficients returns the coefficients for x, which isn't one of the points.
+func (ip *interpolator) coefficients(x int) []*big.Int {
+	product := big.NewInt(1)
+	for _, point := range ip.points {
+		product.Mul(product, difference(x, point))
+		product.Mod(product, modulus)
+	}
+	coefficients := make([]*big.Int, len(ip.points))
+	for k, point := range ip.points {
+		c := new(big.Int).Mul(product, ip.weights[k])
+		c.Mul(c.Mod(c, modulus), inverseDifference(x, point))
+		coefficients[k] = c.Mod(c, modulus)
+	}
+	return coefficients
+}
+
+// cellElements returns the field elements of a cell as integers.
+func cellElements(cell *ckzg4844.Cell) []*big.Int {
+	elements := make([]*big.Int, fieldElementsPerCell)
+	for j := range elements {
+		elements[j] = new(big.Int).SetBytes(cell[j*ckzg4844.BytesPerFieldElement : (j+1)*ckzg4844.BytesPerFieldElement])
+	}
+	return elements
+}
+
+// combine sets out to the linear combination of cells with coefficients.
+func combine(out *ckzg4844.Cell, coefficients []*big.Int, cells [][]*big.Int) {
+	sum, product := new(big.Int), new(big.Int)
+	for j := 0; j < fieldElementsPerCell; j++ {
+		sum.SetInt64(0)
+		for k, c := range coefficients {
+			sum.Add(sum, product.Mul(c, cells[k][j]))
+		}
+		sum.Mod(sum, modulus)
+		sum.FillBytes(out[j*ckzg4844.BytesPerFieldElement : (j+1)*ckzg4844.BytesPerFieldElement])
+	}
+}
+
+// extend computes the second half of the cells from the first half.
+func extend(cells *[ckzg4844.CellsPerExtBlob]ckzg4844.Cell) {
+	erasureOnce.Do(initErasure)
+	data := make([][]*big.Int, cellsPerBlob)
+	for k := range data {
+		data[k] = cellElements(&cells[k])
+	}
+	for i := range extension {
+		combine(&cells[cellsPerBlob+i], extension[i], data)
+	}
+}
+
+// recoverBlob computes the blob from the cells with cellsPerBlob distinct
+// indices.
+func recoverBlob(blob *ckzg4844.Blob, cellIndices []int, cells []*ckzg4844.Cell) {
+	erasureOnce.Do(initErasure)
+	known := make([][]*big.Int, len(cells))
+	var have [cellsPerBlob]bool
+	for k, index := range cellIndices {
+		known[k] = cellElements(cells[k])
+		if index < cellsPerBlob {
+			copy(blob[index*ckzg4844.BytesPerCell:], cells[k][:])
+			have[index] = true
+		}
+	}
+	ip := newInterpolator(cellIndices)
+	var cell ckzg4844.Cell
+	for index, ok := range have {
+		if !ok {
+			combine(&cell, ip.coefficients(index), known)
+			copy(blob[index*ckzg4844.BytesPerCell:], cell[:])
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Package simulation is a KZG backend WITHOUT ANY CRYPTOGRAPHY, for network
+// simulations with thousands of simulated nodes, where real KZG operations
+// would be the bottleneck. NEVER USE IT FOR ANYTHING BUT SIMULATIONS: anyone
+// can forge its commitments and proofs.
+//
+// Commitments and proofs are cheap deterministic hashes of the data they
+// commit to, and verification succeeds exactly when the data matches. Blobs
+// are validated like the real backend does, so invalid blobs are rejected
+// just the same. The backend doesn't need a trusted setup.
+//
+// The first half of the cells are the blob itself, and the second half is a
+// cheap Reed-Solomon extension of it, so recovery works from any half of the
+// cells like with the real backend. The cells differ from the real backend's.
+package simulation
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/binary"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+)
+
+const cellsPerBlob = ckzg4844.CellsPerExtBlob / 2
+
+// Backend is the simulated ckzg4844.Backend.
+type Backend struct{}
+
+var _ ckzg4844.Backend = Backend{}
+
+func validFieldElements(data []byte) bool {
+	for i := 0; i < len(data); i += ckzg4844.BytesPerFieldElement {
+		if bytes.Compare(data[i:i+ckzg4844.BytesPerFieldElement], ckzg4844.BLSModulus[:]) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// hash48 returns a 48 byte hash of the parts, domain separated by domain.
+func hash48(domain string, parts ...[]byte) [48]byte {
+	h := sha512.New384()
+	h.Write([]byte("ckzg4844 simulation " + domain))
+	for _, part := range parts {
+		h.Write(part)
+	}
+	var out [48]byte
+	h.Sum(out[:0])
+	return out
+}
+
+func commitment(blob *ckzg4844.Blob) ckzg4844.KZGCommitment {
+	return ckzg4844.KZGCommitment(hash48("commitment", blob[:]))
+}
+
+func blobProof(commitmentBytes ckzg4844.Bytes48) ckzg4844.KZGProof {
+	return ckzg4844.KZGProof(hash48("blob proof", commitmentBytes[:]))
+}
+
+func evaluationProof(commitmentBytes ckzg4844.Bytes48, zBytes ckzg4844.Bytes32) ckzg4844.KZGProof {
+	return ckzg4844.KZGProof(hash48("evaluation proof", commitmentBytes[:], zBytes[:]))
+}
+
+func cellProof(commitmentBytes ckzg4844.Bytes48, cellIndex uint64, cell *ckzg4844.Cell) ckzg4844.KZGProof {
+	var index [8]byte
+	binary.BigEndian.PutUint64(index[:], cellIndex)
+	return ckzg4844.KZGProof(hash48("cell proof", commitmentBytes[:], index[:], cell[:]))
+}
+
+func (Backend) BlobToKZGCommitment(blob *ckzg4844.Blob) (ckzg4844.KZGCommitment, error) {
+	if blob == nil || !validFieldElements(blob[:]) {
+		return ckzg4844.KZGCommitment{}, ckzg4844.ErrBadArgs
+	}
+	return commitment(blob), nil
+}
+
+// ComputeKZGProof returns a proof and a y which is a hash of the blob and z,
+// rather than the evaluation of the blob at z.
+func (Backend) ComputeKZGProof(blob *ckzg4844.Blob, zBytes ckzg4844.Bytes32) (ckzg4844.KZGProof, ckzg4844.Bytes32, error) {
+	if blob == nil || !validFieldElements(blob[:]) || !validFieldElements(zBytes[:]) {
+		return ckzg4844.KZGProof{}, ckzg4844.Bytes32{}, ckzg4844.ErrBadArgs
+	}
+	var y ckzg4844.Bytes32
+	hash := hash48("evaluation", blob[:], zBytes[:])
+	copy(y[1:], hash[:])
+	return evaluationProof(ckzg4844.Bytes48(commitment(blob)), zBytes), y, nil
+}
+
+func (Backend) ComputeBlobKZGProof(blob *ckzg4844.Blob, commitmentBytes ckzg4844.Bytes48) (ckzg4844.KZGProof, error) {
+	if blob == nil || !validFieldElements(blob[:]) {
+		return ckzg4844.KZGProof{}, ckzg4844.ErrBadArgs
+	}
+	return blobProof(commitmentBytes), nil
+}
+
+// VerifyKZGProof checks the proof against the commitment and z. As there is
+// no blob to evaluate, y is only checked to be a field element.
+func (Backend) VerifyKZGProof(commitmentBytes ckzg4844.Bytes48, zBytes, yBytes ckzg4844.Bytes32, proofBytes ckzg4844.Bytes48) (bool, error) {
+	if !validFieldElements(zBytes[:]) || !validFieldElements(yBytes[:]) {
+		return false, ckzg4844.ErrBadArgs
+	}
+	return proofBytes == ckzg4844.Bytes48(evaluationProof(commitmentBytes, zBytes)), nil
+}
+
+func (b Backend) VerifyBlobKZGProof(blob *ckzg4844.Blob, commitmentBytes, proofBytes ckzg4844.Bytes48) (bool, error) {
+	if blob == nil || !validFieldElements(blob[:]) {
+		return false, ckzg4844.ErrBadArgs
+	}
+	return commitmentBytes == ckzg4844.Bytes48(commitment(blob)) &&
+		proofBytes == ckzg4844.Bytes48(blobProof(commitmentBytes)), nil
+}
+
+func (b Backend) VerifyBlobKZGProofBatch(blobs []ckzg4844.Blob, commitmentsBytes, proofsBytes []ckzg4844.Bytes48) (bool, error) {
+	if len(blobs) != len(commitmentsBytes) || len(blobs) != len(proofsBytes) {
+		return false, ckzg4844.ErrBadArgs
+	}
+	result := true
+	for i := range blobs {
+		ok, err := b.VerifyBlobKZGProof(&blobs[i], commitmentsBytes[i], proofsBytes[i])
+		if err != nil {
+			return false, err
+		}
+		result = result && ok
+	}
+	return result, nil
+}
+
+// cellsAndProofs extends blob to cells and computes their proofs.
+func cellsAndProofs(blob *ckzg4844.Blob) ([ckzg4844.CellsPerExtBlob]ckzg4844.Cell, [ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof) {
+	var cells [ckzg4844.CellsPerExtBlob]ckzg4844.Cell
+	var proofs [ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof
+	for i := 0; i < cellsPerBlob; i++ {
+		copy(cells[i][:], blob[i*ckzg4844.BytesPerCell:])
+	}
+	extend(&cells)
+	commitmentBytes := ckzg4844.Bytes48(commitment(blob))
+	for i := range proofs {
+		proofs[i] = cellProof(commitmentBytes, uint64(i), &cells[i])
+	}
+	return cells, proofs
+}
+
+func (Backend) ComputeCellsAndKZGProofs(blob *ckzg4844.Blob) ([ckzg4844.CellsPerExtBlob]ckzg4844.Cell, [ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof, error) {
+	if blob == nil || !validFieldElements(blob[:]) {
+		return [ckzg4844.CellsPerExtBlob]ckzg4844.Cell{}, [ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof{}, ckzg4844.ErrBadArgs
+	}
+	cells, proofs := cellsAndProofs(blob)
+	return cells, proofs, nil
+}
+
+// RecoverCellsAndKZGProofs needs at least 64 cells with distinct indices, like
+// the real backend.
+func (Backend) RecoverCellsAndKZGProofs(cellIndices []uint64, cells []ckzg4844.Cell) ([ckzg4844.CellsPerExtBlob]ckzg4844.Cell, [ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof, error) {
+	if len(cellIndices) != len(cells) || len(cells) < cellsPerBlob || len(cells) > ckzg4844.CellsPerExtBlob {
+		return [ckzg4844.CellsPerExtBlob]ckzg4844.Cell{}, [ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof{}, ckzg4844.ErrBadArgs
+	}
+	var seen [ckzg4844.CellsPerExtBlob]bool
+	for i, index := range cellIndices {
+		if index >= ckzg4844.CellsPerExtBlob || seen[index] || !validFieldElements(cells[i][:]) {
+			return [ckzg4844.CellsPerExtBlob]ckzg4844.Cell{}, [ckzg4844.CellsPerExtBlob]ckzg4844.KZGProof{}, ckzg4844.ErrBadArgs
+		}
+		seen[index] = true
+	}
+	// Any cellsPerBlob of the cells determine the blob.
+	indices := make([]int, cellsPerBlob)
+	known := make([]*ckzg4844.Cell, cellsPerBlob)
+	for i := range indices {
+		indices[i] = int(cellIndices[i])
+		known[i] = &cells[i]
+	}
+	var blob ckzg4844.Blob
+	recoverBlob(&blob, indices, known)
+	recoveredCells, recoveredProofs := cellsAndProofs(&blob)
+	return recoveredCells, recoveredProofs, nil
+}
+
+func (Backend) VerifyCellKZGProofBatch(commitmentsBytes []ckzg4844.Bytes48, cellIndices []uint64, cells []ckzg4844.Cell, proofsBytes []ckzg4844.Bytes48) (bool, error) {
+	if len(commitmentsBytes) != len(cells) || len(cellIndices) != len(cells) || len(proofsBytes) != len(cells) {
+		return false, ckzg4844.ErrBadArgs
+	}
+	result := true
+	for i := range cells {
+		if cellIndices[i] >= ckzg4844.CellsPerExtBlob || !validFieldElements(cells[i][:]) {
+			return false, ckzg4844.ErrBadArgs
+		}
+		result = result && proofsBytes[i] == ckzg4844.Bytes48(cellProof(commitmentsBytes[i], cellIndices[i], &cells[i]))
+	}
+	return result, nil
+}
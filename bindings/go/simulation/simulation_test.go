@@ -0,0 +1,100 @@
+package simulation
+
+import (
+	"math/rand"
+	"testing"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/ckzgtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackend(t *testing.T) {
+	var b ckzg4844.Backend = Backend{}
+	blob := ckzgtest.RandomBlob(1)
+	commitment, err := b.BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+	proof, err := b.ComputeBlobKZGProof(blob, ckzg4844.Bytes48(commitment))
+	require.NoError(t, err)
+	ok, err := b.VerifyBlobKZGProof(blob, ckzg4844.Bytes48(commitment), ckzg4844.Bytes48(proof))
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = b.VerifyBlobKZGProofBatch([]ckzg4844.Blob{*blob}, []ckzg4844.Bytes48{ckzg4844.Bytes48(commitment)}, []ckzg4844.Bytes48{ckzg4844.Bytes48(commitment)})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	var z ckzg4844.Bytes32
+	z[31] = 7
+	kzgProof, y, err := b.ComputeKZGProof(blob, z)
+	require.NoError(t, err)
+	ok, err = b.VerifyKZGProof(ckzg4844.Bytes48(commitment), z, y, ckzg4844.Bytes48(kzgProof))
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	cells, proofs, err := b.ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+	ok, err = b.VerifyCellKZGProofBatch(
+		[]ckzg4844.Bytes48{ckzg4844.Bytes48(commitment), ckzg4844.Bytes48(commitment)},
+		[]uint64{0, 100},
+		[]ckzg4844.Cell{cells[0], cells[100]},
+		[]ckzg4844.Bytes48{ckzg4844.Bytes48(proofs[0]), ckzg4844.Bytes48(proofs[100])})
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = b.VerifyCellKZGProofBatch([]ckzg4844.Bytes48{ckzg4844.Bytes48(commitment)}, []uint64{1}, []ckzg4844.Cell{cells[0]}, []ckzg4844.Bytes48{ckzg4844.Bytes48(proofs[0])})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	var indices []uint64
+	var subset []ckzg4844.Cell
+	for i := uint64(32); i < 96; i++ {
+		indices = append(indices, i)
+		subset = append(subset, cells[i])
+	}
+	recoveredCells, recoveredProofs, err := b.RecoverCellsAndKZGProofs(indices, subset)
+	require.NoError(t, err)
+	require.Equal(t, cells, recoveredCells)
+	require.Equal(t, proofs, recoveredProofs)
+	_, _, err = b.RecoverCellsAndKZGProofs(indices[1:], subset[1:])
+	require.ErrorIs(t, err, ckzg4844.ErrBadArgs)
+	indices[1] = indices[0]
+	_, _, err = b.RecoverCellsAndKZGProofs(indices, subset)
+	require.ErrorIs(t, err, ckzg4844.ErrBadArgs)
+
+	var invalid ckzg4844.Blob
+	for i := range invalid {
+		invalid[i] = 0xff
+	}
+	_, err = b.BlobToKZGCommitment(&invalid)
+	require.ErrorIs(t, err, ckzg4844.ErrBadArgs)
+}
+
+func TestRecoverRandomHalf(t *testing.T) {
+	b := Backend{}
+	blob := ckzgtest.RandomBlob(2)
+	cells, proofs, err := b.ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+	require.Equal(t, blob[:], cellBytes(cells[:cellsPerBlob]))
+	require.NotEqual(t, cells[:cellsPerBlob], cells[cellsPerBlob:])
+
+	rng := rand.New(rand.NewSource(2))
+	for i := 0; i < 4; i++ {
+		var indices []uint64
+		var subset []ckzg4844.Cell
+		for _, index := range rng.Perm(ckzg4844.CellsPerExtBlob)[:cellsPerBlob+i] {
+			indices = append(indices, uint64(index))
+			subset = append(subset, cells[index])
+		}
+		recoveredCells, recoveredProofs, err := b.RecoverCellsAndKZGProofs(indices, subset)
+		require.NoError(t, err)
+		require.Equal(t, cells, recoveredCells)
+		require.Equal(t, proofs, recoveredProofs)
+	}
+}
+
+func cellBytes(cells []ckzg4844.Cell) []byte {
+	var data []byte
+	for i := range cells {
+		data = append(data, cells[i][:]...)
+	}
+	return data
+}
@@ -0,0 +1,215 @@
+package ckzg4844
+
+import "unsafe"
+
+/*
+The functions in this file take byte slices and caller-provided buffers instead
+of the fixed-size array types. A Blob is 128KiB and the cells of an extended
+blob are 256KiB, so copying them into and out of arrays, on every call and every
+conversion from bytes received from the network, is a large part of the
+allocations of a busy node. These functions check the lengths of their
+arguments and then use the memory they are given, without copies.
+*/
+
+// NewBlob returns a new zeroed blob. It can be used as the New function of a
+// sync.Pool of blobs.
+func NewBlob() *Blob {
+	return new(Blob)
+}
+
+// NewCells returns a slice of CellsPerExtBlob zeroed cells, the buffer that
+// ComputeCellsInto takes. It can be used as the New function of a sync.Pool.
+func NewCells() []Cell {
+	return make([]Cell, CellsPerExtBlob)
+}
+
+// NewKZGProofs returns a slice of CellsPerExtBlob zeroed proofs, the buffer
+// that ComputeCellsAndKZGProofsIntoSlices takes.
+func NewKZGProofs() []KZGProof {
+	return make([]KZGProof, CellsPerExtBlob)
+}
+
+// AsBlob returns the blob which b holds, sharing its memory. It returns
+// ErrLengthMismatch if b isn't BytesPerBlob long.
+func AsBlob(b []byte) (*Blob, error) {
+	if len(b) != BytesPerBlob {
+		return nil, lengthError("AsBlob", "blob", len(b), BytesPerBlob)
+	}
+	return (*Blob)(b), nil
+}
+
+// AsCells returns the cells which b holds, sharing its memory. It returns
+// ErrLengthMismatch if the length of b isn't a multiple of BytesPerCell.
+func AsCells(b []byte) ([]Cell, error) {
+	if len(b)%BytesPerCell != 0 {
+		return nil, &Error{Op: "AsCells", Err: ErrLengthMismatch{Argument: "cells", Length: len(b), Expected: BytesPerCell, Multiple: true}}
+	}
+	if len(b) == 0 {
+		return []Cell{}, nil
+	}
+	return unsafe.Slice((*Cell)(unsafe.Pointer(&b[0])), len(b)/BytesPerCell), nil
+}
+
+// cellsArray returns the array which cells, of length CellsPerExtBlob or nil,
+// holds.
+func cellsArray(cells []Cell) *[CellsPerExtBlob]Cell {
+	if cells == nil {
+		return nil
+	}
+	return (*[CellsPerExtBlob]Cell)(cells)
+}
+
+func proofsArray(proofs []KZGProof) *[CellsPerExtBlob]KZGProof {
+	if proofs == nil {
+		return nil
+	}
+	return (*[CellsPerExtBlob]KZGProof)(proofs)
+}
+
+/*
+BlobToKZGCommitmentInto is BlobToKZGCommitment for a blob held in a byte slice.
+It writes the commitment to out.
+*/
+func BlobToKZGCommitmentInto(out *KZGCommitment, blob []byte) error {
+	return defaultContext.BlobToKZGCommitmentInto(out, blob)
+}
+
+// BlobToKZGCommitmentInto is BlobToKZGCommitmentInto using the trusted setup
+// of c.
+func (c *Context) BlobToKZGCommitmentInto(out *KZGCommitment, blob []byte) error {
+	b, err := AsBlob(blob)
+	if err != nil {
+		return opError("BlobToKZGCommitmentInto", err, nil)
+	}
+	if out == nil {
+		return &Error{Op: "BlobToKZGCommitmentInto", Err: ErrBadArgs}
+	}
+	commitment, err := c.BlobToKZGCommitment(b)
+	if err != nil {
+		return opError("BlobToKZGCommitmentInto", err, nil)
+	}
+	*out = commitment
+	return nil
+}
+
+/*
+ComputeBlobKZGProofInto is ComputeBlobKZGProof for a blob held in a byte slice.
+It writes the proof to out.
+*/
+func ComputeBlobKZGProofInto(out *KZGProof, blob []byte, commitmentBytes Bytes48) error {
+	return defaultContext.ComputeBlobKZGProofInto(out, blob, commitmentBytes)
+}
+
+// ComputeBlobKZGProofInto is ComputeBlobKZGProofInto using the trusted setup
+// of c.
+func (c *Context) ComputeBlobKZGProofInto(out *KZGProof, blob []byte, commitmentBytes Bytes48) error {
+	b, err := AsBlob(blob)
+	if err != nil {
+		return opError("ComputeBlobKZGProofInto", err, nil)
+	}
+	if out == nil {
+		return &Error{Op: "ComputeBlobKZGProofInto", Err: ErrBadArgs}
+	}
+	proof, err := c.ComputeBlobKZGProof(b, commitmentBytes)
+	if err != nil {
+		return opError("ComputeBlobKZGProofInto", err, nil)
+	}
+	*out = proof
+	return nil
+}
+
+/*
+VerifyBlobKZGProofBytes is VerifyBlobKZGProof for a blob held in a byte slice,
+such as the blob of a sidecar received from the network.
+*/
+func VerifyBlobKZGProofBytes(blob []byte, commitmentBytes, proofBytes Bytes48) (bool, error) {
+	return defaultContext.VerifyBlobKZGProofBytes(blob, commitmentBytes, proofBytes)
+}
+
+// VerifyBlobKZGProofBytes is VerifyBlobKZGProofBytes using the trusted setup
+// of c.
+func (c *Context) VerifyBlobKZGProofBytes(blob []byte, commitmentBytes, proofBytes Bytes48) (bool, error) {
+	b, err := AsBlob(blob)
+	if err != nil {
+		return false, opError("VerifyBlobKZGProofBytes", err, nil)
+	}
+	ok, err := c.VerifyBlobKZGProof(b, commitmentBytes, proofBytes)
+	if err != nil {
+		return false, opError("VerifyBlobKZGProofBytes", err, nil)
+	}
+	return ok, nil
+}
+
+/*
+ComputeCellsInto computes the cells of the extended blob of a blob held in a
+byte slice, and writes them to cells, which must have a length of
+CellsPerExtBlob. It is the cheapest way to compute cells when the proofs aren't
+needed.
+*/
+func ComputeCellsInto(cells []Cell, blob []byte) error {
+	return defaultContext.ComputeCellsInto(cells, blob)
+}
+
+// ComputeCellsInto is ComputeCellsInto using the trusted setup of c.
+func (c *Context) ComputeCellsInto(cells []Cell, blob []byte) error {
+	return c.computeCellsIntoSlices("ComputeCellsInto", cells, nil, blob)
+}
+
+/*
+ComputeCellsAndKZGProofsIntoSlices is ComputeCellsAndKZGProofsInto for a blob
+held in a byte slice. It writes the cells and their proofs to cells and proofs,
+which must have a length of CellsPerExtBlob. One of them may be nil if it isn't
+needed.
+*/
+func ComputeCellsAndKZGProofsIntoSlices(cells []Cell, proofs []KZGProof, blob []byte) error {
+	return defaultContext.ComputeCellsAndKZGProofsIntoSlices(cells, proofs, blob)
+}
+
+// ComputeCellsAndKZGProofsIntoSlices is ComputeCellsAndKZGProofsIntoSlices
+// using the trusted setup of c.
+func (c *Context) ComputeCellsAndKZGProofsIntoSlices(cells []Cell, proofs []KZGProof, blob []byte) error {
+	return c.computeCellsIntoSlices("ComputeCellsAndKZGProofsIntoSlices", cells, proofs, blob)
+}
+
+func (c *Context) computeCellsIntoSlices(op string, cells []Cell, proofs []KZGProof, blob []byte) error {
+	b, err := AsBlob(blob)
+	if err != nil {
+		return opError(op, err, nil)
+	}
+	if cells != nil && len(cells) != CellsPerExtBlob {
+		return lengthError(op, "cells", len(cells), CellsPerExtBlob)
+	}
+	if proofs != nil && len(proofs) != CellsPerExtBlob {
+		return lengthError(op, "proofs", len(proofs), CellsPerExtBlob)
+	}
+	if err := c.ComputeCellsAndKZGProofsInto(cellsArray(cells), proofsArray(proofs), b); err != nil {
+		return opError(op, err, nil)
+	}
+	return nil
+}
+
+/*
+RecoverCellsAndKZGProofsIntoSlices is RecoverCellsAndKZGProofsInto writing to
+slices, which must have a length of CellsPerExtBlob. recoveredProofs may be nil
+if the proofs aren't needed.
+*/
+func RecoverCellsAndKZGProofsIntoSlices(recoveredCells []Cell, recoveredProofs []KZGProof, cellIndices []uint64, cells []Cell) error {
+	return defaultContext.RecoverCellsAndKZGProofsIntoSlices(recoveredCells, recoveredProofs, cellIndices, cells)
+}
+
+// RecoverCellsAndKZGProofsIntoSlices is RecoverCellsAndKZGProofsIntoSlices
+// using the trusted setup of c.
+func (c *Context) RecoverCellsAndKZGProofsIntoSlices(recoveredCells []Cell, recoveredProofs []KZGProof, cellIndices []uint64, cells []Cell) error {
+	const op = "RecoverCellsAndKZGProofsIntoSlices"
+	if len(recoveredCells) != CellsPerExtBlob {
+		return lengthError(op, "recoveredCells", len(recoveredCells), CellsPerExtBlob)
+	}
+	if recoveredProofs != nil && len(recoveredProofs) != CellsPerExtBlob {
+		return lengthError(op, "recoveredProofs", len(recoveredProofs), CellsPerExtBlob)
+	}
+	err := c.RecoverCellsAndKZGProofsInto(cellsArray(recoveredCells), proofsArray(recoveredProofs), cellIndices, cells)
+	if err != nil {
+		return opError(op, err, nil)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package ckzg4844
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSliceAPI(t *testing.T) {
+	var blob Blob
+	fillBlobRandom(&blob, 14)
+	expectedCommitment, err := BlobToKZGCommitment(&blob)
+	require.NoError(t, err)
+	expectedProof, err := ComputeBlobKZGProof(&blob, Bytes48(expectedCommitment))
+	require.NoError(t, err)
+	expectedCells, expectedProofs, err := ComputeCellsAndKZGProofs(&blob)
+	require.NoError(t, err)
+
+	b, err := AsBlob(blob[:])
+	require.NoError(t, err)
+	require.Same(t, &blob, b)
+
+	var commitment KZGCommitment
+	require.NoError(t, BlobToKZGCommitmentInto(&commitment, blob[:]))
+	require.Equal(t, expectedCommitment, commitment)
+	var proof KZGProof
+	require.NoError(t, ComputeBlobKZGProofInto(&proof, blob[:], Bytes48(commitment)))
+	require.Equal(t, expectedProof, proof)
+	ok, err := VerifyBlobKZGProofBytes(blob[:], Bytes48(commitment), Bytes48(proof))
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	cells := NewCells()
+	require.NoError(t, ComputeCellsInto(cells, blob[:]))
+	require.Equal(t, expectedCells[:], cells)
+	proofs := NewKZGProofs()
+	require.NoError(t, ComputeCellsAndKZGProofsIntoSlices(nil, proofs, blob[:]))
+	require.Equal(t, expectedProofs[:], proofs)
+
+	cellIndices, partialCells := getPartialCells(expectedCells, 2)
+	recoveredCells := NewCells()
+	recoveredProofs := NewKZGProofs()
+	require.NoError(t, RecoverCellsAndKZGProofsIntoSlices(recoveredCells, recoveredProofs, cellIndices, partialCells))
+	require.Equal(t, expectedCells[:], recoveredCells)
+	require.Equal(t, expectedProofs[:], recoveredProofs)
+
+	// The cells of a byte slice are shared with it.
+	cellBytes := make([]byte, 2*BytesPerCell)
+	asCells, err := AsCells(cellBytes)
+	require.NoError(t, err)
+	require.Len(t, asCells, 2)
+	asCells[1][0] = 1
+	require.Equal(t, byte(1), cellBytes[BytesPerCell])
+}
+
+func TestSliceAPILengths(t *testing.T) {
+	var lengthErr ErrLengthMismatch
+	var commitment KZGCommitment
+	err := BlobToKZGCommitmentInto(&commitment, make([]byte, BytesPerBlob-1))
+	require.ErrorAs(t, err, &lengthErr)
+	require.Equal(t, ErrLengthMismatch{Argument: "blob", Length: BytesPerBlob - 1, Expected: BytesPerBlob}, lengthErr)
+	require.ErrorIs(t, BlobToKZGCommitmentInto(nil, make([]byte, BytesPerBlob)), ErrBadArgs)
+
+	err = ComputeCellsInto(make([]Cell, CellsPerExtBlob-1), make([]byte, BytesPerBlob))
+	require.ErrorAs(t, err, &lengthErr)
+	require.Equal(t, "cells", lengthErr.Argument)
+	require.ErrorIs(t, ComputeCellsInto(nil, make([]byte, BytesPerBlob)), ErrBadArgs)
+
+	err = RecoverCellsAndKZGProofsIntoSlices(NewCells(), make([]KZGProof, 1), nil, nil)
+	require.ErrorAs(t, err, &lengthErr)
+	require.Equal(t, "recoveredProofs", lengthErr.Argument)
+
+	_, err = AsCells(make([]byte, BytesPerCell+1))
+	require.ErrorAs(t, err, &lengthErr)
+	require.Equal(t, ErrLengthMismatch{Argument: "cells", Length: BytesPerCell + 1, Expected: BytesPerCell, Multiple: true}, lengthErr)
+	require.Equal(t, "AsCells: bad arguments: cells has length 2049, expected a multiple of 2048", err.Error())
+}
+
+func BenchmarkComputeCellsInto(b *testing.B) {
+	var blob Blob
+	fillBlobRandom(&blob, 15)
+	pool := sync.Pool{New: func() any { return NewCells() }}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cells := pool.Get().([]Cell)
+		if err := ComputeCellsInto(cells, blob[:]); err != nil {
+			b.Fatal(err)
+		}
+		pool.Put(cells)
+	}
+}
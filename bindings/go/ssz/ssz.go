@@ -0,0 +1,236 @@
+// Package ssz decodes the SSZ containers of the consensus specs which embed
+// cells and proofs, DataColumnSidecar and MatrixEntry, directly into binding
+// types and encodes them back, so that clients don't need a copy layer between
+// their SSZ codec and the KZG library.
+//
+// Decoding doesn't copy cells, commitments or proofs: the decoded slices alias
+// the input.
+package ssz
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unsafe"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+)
+
+const (
+	// MaxBlobCommitmentsPerBlock is the limit of the lists in a
+	// DataColumnSidecar.
+	MaxBlobCommitmentsPerBlock = 4096
+	// KZGCommitmentsInclusionProofDepth is the length of the inclusion proof
+	// of the commitments in the block body.
+	KZGCommitmentsInclusionProofDepth = 4
+
+	beaconBlockHeaderSize       = 8 + 8 + 32 + 32 + 32
+	signedBeaconBlockHeaderSize = beaconBlockHeaderSize + 96
+	dataColumnSidecarFixedSize  = 8 + 4 + 4 + 4 + signedBeaconBlockHeaderSize + KZGCommitmentsInclusionProofDepth*32
+
+	// MatrixEntrySize is the size of an encoded MatrixEntry.
+	MatrixEntrySize = ckzg4844.BytesPerCell + ckzg4844.BytesPerProof + 8 + 8
+)
+
+// ErrInvalidSSZ is returned when decoding data which isn't a valid SSZ
+// encoding of the type.
+var ErrInvalidSSZ = errors.New("invalid SSZ encoding")
+
+type BeaconBlockHeader struct {
+	Slot          uint64
+	ProposerIndex uint64
+	ParentRoot    [32]byte
+	StateRoot     [32]byte
+	BodyRoot      [32]byte
+}
+
+type SignedBeaconBlockHeader struct {
+	Message   BeaconBlockHeader
+	Signature [96]byte
+}
+
+// DataColumnSidecar is the column of cells of all blobs of a block, with
+// index Index.
+type DataColumnSidecar struct {
+	Index                        uint64
+	Column                       []ckzg4844.Cell
+	KZGCommitments               []ckzg4844.Bytes48
+	KZGProofs                    []ckzg4844.Bytes48
+	SignedBlockHeader            SignedBeaconBlockHeader
+	KZGCommitmentsInclusionProof [KZGCommitmentsInclusionProofDepth][32]byte
+}
+
+// MatrixEntry is a cell of the extended blob matrix with its proof.
+type MatrixEntry struct {
+	Cell        ckzg4844.Cell
+	KZGProof    ckzg4844.Bytes48
+	ColumnIndex uint64
+	RowIndex    uint64
+}
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidSSZ, fmt.Sprintf(format, args...))
+}
+
+// asArrays reinterprets data, whose length is a multiple of size, as a slice
+// of T, which must be a byte array of that size.
+func asArrays[T any](data []byte, size int) []T {
+	if len(data) == 0 {
+		return []T{}
+	}
+	return unsafe.Slice((*T)(unsafe.Pointer(&data[0])), len(data)/size)
+}
+
+func asBytes[T any](items []T, size int) []byte {
+	if len(items) == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(&items[0])), len(items)*size)
+}
+
+func (h *SignedBeaconBlockHeader) unmarshal(data []byte) {
+	h.Message.Slot = binary.LittleEndian.Uint64(data[0:])
+	h.Message.ProposerIndex = binary.LittleEndian.Uint64(data[8:])
+	copy(h.Message.ParentRoot[:], data[16:])
+	copy(h.Message.StateRoot[:], data[48:])
+	copy(h.Message.BodyRoot[:], data[80:])
+	copy(h.Signature[:], data[beaconBlockHeaderSize:])
+}
+
+func (h *SignedBeaconBlockHeader) marshal(out []byte) []byte {
+	out = binary.LittleEndian.AppendUint64(out, h.Message.Slot)
+	out = binary.LittleEndian.AppendUint64(out, h.Message.ProposerIndex)
+	out = append(out, h.Message.ParentRoot[:]...)
+	out = append(out, h.Message.StateRoot[:]...)
+	out = append(out, h.Message.BodyRoot[:]...)
+	return append(out, h.Signature[:]...)
+}
+
+// SizeSSZ returns the size of the encoding of s.
+func (s *DataColumnSidecar) SizeSSZ() int {
+	return dataColumnSidecarFixedSize +
+		len(s.Column)*ckzg4844.BytesPerCell +
+		len(s.KZGCommitments)*ckzg4844.BytesPerCommitment +
+		len(s.KZGProofs)*ckzg4844.BytesPerProof
+}
+
+/*
+UnmarshalSSZ decodes s from data. Column, KZGCommitments and KZGProofs alias
+data rather than being copied, so data must not be modified while s is in use.
+*/
+func (s *DataColumnSidecar) UnmarshalSSZ(data []byte) error {
+	if len(data) < dataColumnSidecarFixedSize {
+		return invalid("data column sidecar is %d bytes, less than %d", len(data), dataColumnSidecarFixedSize)
+	}
+	offsets := [4]int{
+		int(binary.LittleEndian.Uint32(data[8:])),
+		int(binary.LittleEndian.Uint32(data[12:])),
+		int(binary.LittleEndian.Uint32(data[16:])),
+		len(data),
+	}
+	if offsets[0] != dataColumnSidecarFixedSize {
+		return invalid("column offset is %d, expected %d", offsets[0], dataColumnSidecarFixedSize)
+	}
+	for i := 1; i < len(offsets); i++ {
+		if offsets[i] < offsets[i-1] {
+			return invalid("offsets aren't increasing")
+		}
+	}
+	sizes := [3]int{ckzg4844.BytesPerCell, ckzg4844.BytesPerCommitment, ckzg4844.BytesPerProof}
+	names := [3]string{"column", "kzg_commitments", "kzg_proofs"}
+	for i, size := range sizes {
+		n := offsets[i+1] - offsets[i]
+		if n%size != 0 {
+			return invalid("%s is %d bytes, not a multiple of %d", names[i], n, size)
+		}
+		if n/size > MaxBlobCommitmentsPerBlock {
+			return invalid("%s has %d items, more than %d", names[i], n/size, MaxBlobCommitmentsPerBlock)
+		}
+	}
+
+	s.Index = binary.LittleEndian.Uint64(data)
+	s.SignedBlockHeader.unmarshal(data[20:])
+	proof := data[20+signedBeaconBlockHeaderSize:]
+	for i := range s.KZGCommitmentsInclusionProof {
+		copy(s.KZGCommitmentsInclusionProof[i][:], proof[i*32:])
+	}
+	s.Column = asArrays[ckzg4844.Cell](data[offsets[0]:offsets[1]], ckzg4844.BytesPerCell)
+	s.KZGCommitments = asArrays[ckzg4844.Bytes48](data[offsets[1]:offsets[2]], ckzg4844.BytesPerCommitment)
+	s.KZGProofs = asArrays[ckzg4844.Bytes48](data[offsets[2]:offsets[3]], ckzg4844.BytesPerProof)
+	return nil
+}
+
+// MarshalSSZ encodes s.
+func (s *DataColumnSidecar) MarshalSSZ() ([]byte, error) {
+	return s.MarshalSSZTo(make([]byte, 0, s.SizeSSZ()))
+}
+
+// MarshalSSZTo appends the encoding of s to out.
+func (s *DataColumnSidecar) MarshalSSZTo(out []byte) ([]byte, error) {
+	if len(s.Column) > MaxBlobCommitmentsPerBlock ||
+		len(s.KZGCommitments) > MaxBlobCommitmentsPerBlock ||
+		len(s.KZGProofs) > MaxBlobCommitmentsPerBlock {
+		return nil, invalid("list longer than %d", MaxBlobCommitmentsPerBlock)
+	}
+	offset := dataColumnSidecarFixedSize
+	out = binary.LittleEndian.AppendUint64(out, s.Index)
+	out = binary.LittleEndian.AppendUint32(out, uint32(offset))
+	offset += len(s.Column) * ckzg4844.BytesPerCell
+	out = binary.LittleEndian.AppendUint32(out, uint32(offset))
+	offset += len(s.KZGCommitments) * ckzg4844.BytesPerCommitment
+	out = binary.LittleEndian.AppendUint32(out, uint32(offset))
+	out = s.SignedBlockHeader.marshal(out)
+	for i := range s.KZGCommitmentsInclusionProof {
+		out = append(out, s.KZGCommitmentsInclusionProof[i][:]...)
+	}
+	out = append(out, asBytes(s.Column, ckzg4844.BytesPerCell)...)
+	out = append(out, asBytes(s.KZGCommitments, ckzg4844.BytesPerCommitment)...)
+	out = append(out, asBytes(s.KZGProofs, ckzg4844.BytesPerProof)...)
+	return out, nil
+}
+
+/*
+VerifyKZGProofs verifies the cell proofs of the sidecar, as
+verify_data_column_sidecar_kzg_proofs in the consensus specs. It returns
+ErrBadArgs if the lists don't have the same length.
+*/
+func (s *DataColumnSidecar) VerifyKZGProofs() (bool, error) {
+	if len(s.Column) != len(s.KZGCommitments) || len(s.Column) != len(s.KZGProofs) {
+		return false, ckzg4844.ErrBadArgs
+	}
+	cellIndices := make([]uint64, len(s.Column))
+	for i := range cellIndices {
+		cellIndices[i] = s.Index
+	}
+	return ckzg4844.VerifyCellKZGProofBatch(s.KZGCommitments, cellIndices, s.Column, s.KZGProofs)
+}
+
+// UnmarshalSSZ decodes e from data, copying the cell and proof.
+func (e *MatrixEntry) UnmarshalSSZ(data []byte) error {
+	if len(data) != MatrixEntrySize {
+		return invalid("matrix entry is %d bytes, expected %d", len(data), MatrixEntrySize)
+	}
+	copy(e.Cell[:], data)
+	copy(e.KZGProof[:], data[ckzg4844.BytesPerCell:])
+	e.ColumnIndex = binary.LittleEndian.Uint64(data[ckzg4844.BytesPerCell+ckzg4844.BytesPerProof:])
+	e.RowIndex = binary.LittleEndian.Uint64(data[ckzg4844.BytesPerCell+ckzg4844.BytesPerProof+8:])
+	return nil
+}
+
+// MarshalSSZ encodes e.
+func (e *MatrixEntry) MarshalSSZ() ([]byte, error) {
+	return e.MarshalSSZTo(make([]byte, 0, MatrixEntrySize))
+}
+
+// MarshalSSZTo appends the encoding of e to out.
+func (e *MatrixEntry) MarshalSSZTo(out []byte) ([]byte, error) {
+	out = append(out, e.Cell[:]...)
+	out = append(out, e.KZGProof[:]...)
+	out = binary.LittleEndian.AppendUint64(out, e.ColumnIndex)
+	return binary.LittleEndian.AppendUint64(out, e.RowIndex), nil
+}
+
+// SizeSSZ returns the size of the encoding of e.
+func (e *MatrixEntry) SizeSSZ() int {
+	return MatrixEntrySize
+}
@@ -0,0 +1,62 @@
+package ssz
+
+import (
+	"encoding/binary"
+	"testing"
+
+	ckzg4844 "github.com/ethereum/c-kzg-4844/v2/bindings/go"
+	"github.com/ethereum/c-kzg-4844/v2/bindings/go/ckzgtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataColumnSidecar(t *testing.T) {
+	ckzgtest.LoadTrustedSetup(t)
+	const index = 7
+	sidecar := DataColumnSidecar{Index: index}
+	for i := int64(0); i < 2; i++ {
+		blob := ckzgtest.RandomBlob(i)
+		commitment, err := ckzg4844.BlobToKZGCommitment(blob)
+		require.NoError(t, err)
+		cells, proofs, err := ckzg4844.ComputeCellsAndKZGProofs(blob)
+		require.NoError(t, err)
+		sidecar.Column = append(sidecar.Column, cells[index])
+		sidecar.KZGCommitments = append(sidecar.KZGCommitments, ckzg4844.Bytes48(commitment))
+		sidecar.KZGProofs = append(sidecar.KZGProofs, ckzg4844.Bytes48(proofs[index]))
+	}
+	sidecar.SignedBlockHeader.Message.Slot = 12345
+	sidecar.SignedBlockHeader.Signature[0] = 0xc0
+	sidecar.KZGCommitmentsInclusionProof[3][31] = 1
+
+	data, err := sidecar.MarshalSSZ()
+	require.NoError(t, err)
+	require.Len(t, data, sidecar.SizeSSZ())
+	require.Equal(t, 356+2*(2048+48+48), len(data))
+
+	var decoded DataColumnSidecar
+	require.NoError(t, decoded.UnmarshalSSZ(data))
+	require.Equal(t, sidecar, decoded)
+	ok, err := decoded.VerifyKZGProofs()
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// The decoded cells alias the input.
+	data[356] ^= 1
+	require.NotEqual(t, sidecar.Column[0], decoded.Column[0])
+
+	binary.LittleEndian.PutUint32(data[12:], 357)
+	require.ErrorIs(t, decoded.UnmarshalSSZ(data), ErrInvalidSSZ)
+	require.ErrorIs(t, decoded.UnmarshalSSZ(data[:100]), ErrInvalidSSZ)
+}
+
+func TestMatrixEntry(t *testing.T) {
+	entry := MatrixEntry{ColumnIndex: 3, RowIndex: 9}
+	entry.Cell[0] = 1
+	entry.KZGProof[47] = 2
+	data, err := entry.MarshalSSZ()
+	require.NoError(t, err)
+	require.Len(t, data, MatrixEntrySize)
+	var decoded MatrixEntry
+	require.NoError(t, decoded.UnmarshalSSZ(data))
+	require.Equal(t, entry, decoded)
+	require.ErrorIs(t, decoded.UnmarshalSSZ(data[1:]), ErrInvalidSSZ)
+}
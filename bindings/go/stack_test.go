@@ -0,0 +1,98 @@
+package ckzg4844
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// measureStackGrowth runs fn in numGoroutines goroutines and returns the
+// average amount of stack memory each goroutine holds after fn returns, and
+// the first error returned by fn.
+func measureStackGrowth(numGoroutines int, fn func() error) (uint64, error) {
+	var before, after runtime.MemStats
+	errs := make(chan error, numGoroutines)
+	release := make(chan struct{})
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	for i := 0; i < numGoroutines; i++ {
+		go func() {
+			errs <- fn()
+			<-release
+		}()
+	}
+	var firstErr error
+	for i := 0; i < numGoroutines; i++ {
+		if err := <-errs; err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	runtime.ReadMemStats(&after)
+	close(release)
+	if after.StackInuse < before.StackInuse {
+		return 0, firstErr
+	}
+	return (after.StackInuse - before.StackInuse) / uint64(numGoroutines), firstErr
+}
+
+func TestComputeCellsAndKZGProofsInto(t *testing.T) {
+	var blob Blob
+	fillBlobRandom(&blob, 11)
+	expectedCells, expectedProofs, err := ComputeCellsAndKZGProofs(&blob)
+	require.NoError(t, err)
+
+	cells := new([CellsPerExtBlob]Cell)
+	proofs := new([CellsPerExtBlob]KZGProof)
+	require.NoError(t, ComputeCellsAndKZGProofsInto(cells, proofs, &blob))
+	require.Equal(t, expectedCells, *cells)
+	require.Equal(t, expectedProofs, *proofs)
+
+	onlyCells := new([CellsPerExtBlob]Cell)
+	require.NoError(t, ComputeCellsAndKZGProofsInto(onlyCells, nil, &blob))
+	require.Equal(t, expectedCells, *onlyCells)
+	require.ErrorIs(t, ComputeCellsAndKZGProofsInto(nil, nil, &blob), ErrBadArgs)
+}
+
+func TestRecoverCellsAndKZGProofsInto(t *testing.T) {
+	var blob Blob
+	fillBlobRandom(&blob, 12)
+	cells, proofs, err := ComputeCellsAndKZGProofs(&blob)
+	require.NoError(t, err)
+
+	cellIndices, partialCells := getPartialCells(cells, 2)
+	recoveredCells := new([CellsPerExtBlob]Cell)
+	recoveredProofs := new([CellsPerExtBlob]KZGProof)
+	require.NoError(t, RecoverCellsAndKZGProofsInto(recoveredCells, recoveredProofs, cellIndices, partialCells))
+	require.Equal(t, cells, *recoveredCells)
+	require.Equal(t, proofs, *recoveredProofs)
+}
+
+func TestStackGrowth(t *testing.T) {
+	const numGoroutines = 8
+	var blob Blob
+	fillBlobRandom(&blob, 13)
+	cells := make([][CellsPerExtBlob]Cell, numGoroutines)
+	proofs := make([][CellsPerExtBlob]KZGProof, numGoroutines)
+
+	next := make(chan int, numGoroutines)
+	for i := 0; i < numGoroutines; i++ {
+		next <- i
+	}
+	byPointer, err := measureStackGrowth(numGoroutines, func() error {
+		i := <-next
+		return ComputeCellsAndKZGProofsInto(&cells[i], &proofs[i], &blob)
+	})
+	require.NoError(t, err)
+	byValue, err := measureStackGrowth(numGoroutines, func() error {
+		_, _, err := ComputeCellsAndKZGProofs(&blob)
+		return err
+	})
+	require.NoError(t, err)
+	t.Logf("stack growth per goroutine: by value %d bytes, by pointer %d bytes", byValue, byPointer)
+
+	// The by-value arrays alone are more than 256KiB; the pointer-based
+	// variant should stay within a few pages of the initial stack.
+	require.Greater(t, byValue, byPointer)
+	require.Less(t, byPointer, uint64(64*1024))
+}